@@ -0,0 +1,20 @@
+package apdu
+
+import "fmt"
+
+// StatusWordError is returned when the card completes a command with a status word other than
+// 9000 (success) or 61XX (more data available).
+type StatusWordError struct {
+	// Command is the APDU sent to the card.
+	Command []byte
+	// SW is the status word returned by the card.
+	SW uint16
+}
+
+func (e *StatusWordError) Error() string {
+	return fmt.Sprintf("returned an unexpected response with status %04X", e.SW)
+}
+
+func (e *StatusWordError) SW1() byte { return byte(e.SW >> 8) }
+
+func (e *StatusWordError) SW2() byte { return byte(e.SW) }
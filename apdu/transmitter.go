@@ -2,72 +2,77 @@ package apdu
 
 import (
 	"bytes"
-	"fmt"
+	"context"
 	"io"
 	"slices"
 	"sync"
 )
 
 type Transmitter struct {
-	MSS            int
+	MSS int
+	// mutex is held for a whole command, from its first STORE DATA block to the last GET RESPONSE.
 	mutex          sync.Mutex
 	channel        SmartCardChannel
 	logicalChannel byte
-	response       *bytes.Buffer
 }
 
-func NewTransmitter(channel SmartCardChannel, AID []byte, MSS int) (io.ReadWriteCloser, error) {
+func NewTransmitter(channel SmartCardChannel, AID []byte, MSS int) (*Transmitter, error) {
 	var err error
 	if err = channel.Connect(); err != nil {
+		_ = channel.Disconnect()
 		return nil, err
 	}
 	var transmitter Transmitter
 	transmitter.channel = channel
 	if transmitter.logicalChannel, err = channel.OpenLogicalChannel(AID); err != nil {
+		_ = channel.Disconnect()
 		return nil, err
 	}
 	transmitter.MSS = MSS
 	return &transmitter, nil
 }
 
-func (t *Transmitter) Read(p []byte) (n int, err error) {
-	return t.response.Read(p)
-}
-
-func (t *Transmitter) Write(command []byte) (n int, err error) {
-	t.response = new(bytes.Buffer)
-	request := Request{CLA: 0x80, INS: 0xE2}
+// Transmit sends the command to the ISD-R in STORE DATA blocks and returns the response.
+// The blocks and the GET RESPONSE commands reading the response are never interleaved with those of a concurrent Transmit.
+// It returns the error of the context once the context is done.
+func (t *Transmitter) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var err error
 	var response Response
-	chunks := byte(len(command) / t.MSS)
+	buffer := new(bytes.Buffer)
+	request := Request{CLA: 0x80, INS: 0xE2}
+	chunks := byte((len(command) - 1) / t.MSS)
 	for request.Data = range slices.Chunk(command, t.MSS) {
 		if request.P1 = 0x11; request.P2 == chunks {
 			request.P1 = 0x91
 		}
-		if response, err = t.transmit(&request); err != nil {
-			break
+		if response, err = t.transmit(ctx, &request); err != nil {
+			return nil, err
 		}
 		request.P2++
-		n += len(request.Data)
 		if !response.HasMore() {
-			t.response.Write(response.Data())
+			buffer.Write(response.Data())
 			continue
 		}
-		if err = t.readCommandResponse(t.response, response.SW2()); err != nil {
-			break
+		if err = t.readCommandResponse(ctx, buffer, response.SW2()); err != nil {
+			return nil, err
 		}
 	}
-	return
+	return buffer.Bytes(), nil
 }
 
-func (t *Transmitter) transmit(request *Request) (response Response, err error) {
-	t.mutex.Lock()
-	defer t.mutex.Unlock()
+func (t *Transmitter) transmit(ctx context.Context, request *Request) (response Response, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
 	t.setChannelToCLA(request, t.logicalChannel)
-	if response, err = t.channel.Transmit(request.APDU()); err != nil {
+	command := request.APDU()
+	if response, err = t.channel.Transmit(ctx, command); err != nil {
 		return
 	}
 	if !response.OK() && !response.HasMore() {
-		err = fmt.Errorf("returned an unexpected response with status %04X", response.SW())
+		err = &StatusWordError{Command: command, SW: response.SW()}
 	}
 	return
 }
@@ -80,7 +85,7 @@ func (t *Transmitter) setChannelToCLA(request *Request, channel byte) {
 	}
 }
 
-func (t *Transmitter) readCommandResponse(w io.Writer, le byte) error {
+func (t *Transmitter) readCommandResponse(ctx context.Context, w io.Writer, le byte) error {
 	var err error
 	var request Request
 	var response Response
@@ -88,7 +93,7 @@ func (t *Transmitter) readCommandResponse(w io.Writer, le byte) error {
 	request.INS = 0xC0
 	request.Le = &le
 	for {
-		if response, err = t.transmit(&request); err != nil {
+		if response, err = t.transmit(ctx, &request); err != nil {
 			return err
 		}
 		if _, err = w.Write(response.Data()); err != nil {
@@ -102,7 +107,18 @@ func (t *Transmitter) readCommandResponse(w io.Writer, le byte) error {
 	return nil
 }
 
+// Exclusive calls fn while no command is being transmitted, for the other requests of the channel,
+// such as reading the IMEI of the modem, which must not be interleaved with the blocks of a command.
+func (t *Transmitter) Exclusive(fn func() error) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return fn()
+}
+
+// Close closes the logical channel and disconnects the channel, once the command being transmitted is done.
 func (t *Transmitter) Close() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
 	if err := t.channel.CloseLogicalChannel(t.logicalChannel); err != nil {
 		return err
 	}
@@ -0,0 +1,118 @@
+package apdu
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type statusChannel struct{ sw []byte }
+
+func (c *statusChannel) Connect() error                              { return nil }
+func (c *statusChannel) Disconnect() error                           { return nil }
+func (c *statusChannel) OpenLogicalChannel(AID []byte) (byte, error) { return 1, nil }
+func (c *statusChannel) CloseLogicalChannel(channel byte) error      { return nil }
+
+func (c *statusChannel) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	return c.sw, nil
+}
+
+func TestTransmitter_StatusWordError(t *testing.T) {
+	transmitter, err := NewTransmitter(&statusChannel{sw: []byte{0x6A, 0x88}}, nil, 120)
+	require.NoError(t, err)
+	_, err = transmitter.Transmit(context.Background(), []byte{0xBF, 0x3E, 0x03, 0x5C, 0x01, 0x5A})
+	var statusErr *StatusWordError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, uint16(0x6A88), statusErr.SW)
+	assert.Equal(t, byte(0x6A), statusErr.SW1())
+	assert.Equal(t, byte(0x88), statusErr.SW2())
+	assert.Equal(t, "81E2910006BF3E035C015A", Response(statusErr.Command).String())
+	assert.EqualError(t, err, "returned an unexpected response with status 6A88")
+}
+
+// failingChannel fails to connect and records whether it was disconnected.
+type failingChannel struct {
+	statusChannel
+	disconnected bool
+}
+
+func (c *failingChannel) Connect() error { return errors.New("no card") }
+
+func (c *failingChannel) Disconnect() error {
+	c.disconnected = true
+	return nil
+}
+
+func TestNewTransmitter_ConnectError(t *testing.T) {
+	channel := new(failingChannel)
+	_, err := NewTransmitter(channel, nil, 120)
+	assert.EqualError(t, err, "no card")
+	assert.True(t, channel.disconnected)
+}
+
+// sequenceChannel fails the STORE DATA blocks which do not follow the previous block of their command.
+type sequenceChannel struct {
+	statusChannel
+	mutex sync.Mutex
+	next  byte
+}
+
+func (c *sequenceChannel) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	p1, p2 := command[2], command[3]
+	if p2 != c.next {
+		return []byte{0x69, 0x85}, nil
+	}
+	if c.next = p2 + 1; p1 == 0x91 {
+		c.next = 0
+	}
+	time.Sleep(time.Millisecond)
+	return []byte{0x90, 0x00}, nil
+}
+
+func TestTransmitter_Concurrent(t *testing.T) {
+	transmitter, err := NewTransmitter(new(sequenceChannel), nil, 2)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := transmitter.Transmit(context.Background(), []byte{0xBF, 0x3E, 0x03, 0x5C, 0x01, 0x5A})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTransmitter_Exclusive(t *testing.T) {
+	channel := new(sequenceChannel)
+	transmitter, err := NewTransmitter(channel, nil, 2)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := transmitter.Transmit(context.Background(), []byte{0xBF, 0x3E, 0x03, 0x5C, 0x01, 0x5A})
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, transmitter.Exclusive(func() error {
+				channel.mutex.Lock()
+				defer channel.mutex.Unlock()
+				// No command is halfway through its blocks.
+				assert.Zero(t, channel.next)
+				return nil
+			}))
+		}()
+	}
+	wg.Wait()
+}
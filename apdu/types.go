@@ -1,9 +1,24 @@
 package apdu
 
+import "context"
+
+// SmartCardChannel is a channel to the card.
+// Disconnect releases the channel even when Connect failed.
+// Transmit must return once the context is done, with the error of the context.
 type SmartCardChannel interface {
 	Connect() error
 	Disconnect() error
 	OpenLogicalChannel(AID []byte) (byte, error)
-	Transmit(command []byte) ([]byte, error)
+	Transmit(ctx context.Context, command []byte) ([]byte, error)
 	CloseLogicalChannel(channel byte) error
 }
+
+// Observer is called with every command and response a channel exchanges with the card.
+type Observer func(command, response []byte, err error)
+
+// ObservableChannel is implemented by the channels sending APDUs on their own,
+// such as the terminal capability on Connect or MANAGE CHANNEL and SELECT on OpenLogicalChannel.
+type ObservableChannel interface {
+	SmartCardChannel
+	SetObserver(observer Observer)
+}
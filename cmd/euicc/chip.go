@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/KilimcininKorOglu/euicc-go/lpac"
+)
+
+func chipInfo(ctx context.Context, app *app, args []string) error {
+	if _, err := parseFlags(flag.NewFlagSet("chip info", flag.ContinueOnError), args, 0); err != nil {
+		return err
+	}
+	info, err := app.client.ChipInfo(ctx)
+	if err != nil {
+		return err
+	}
+	return app.output.result(lpac.NewChipInfo(info), func(out io.Writer) error {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "EID:\t%s\n", info.EID)
+		if addresses := info.ConfiguredAddresses; addresses != nil {
+			fmt.Fprintf(w, "Default SM-DP+:\t%s\n", addresses.DefaultSMDPAddress)
+			fmt.Fprintf(w, "Root SM-DS:\t%s\n", addresses.RootSMDSAddress)
+		}
+		if info2 := info.Info2; info2 != nil {
+			fmt.Fprintf(w, "Profile version:\t%s\n", info2.ProfileVersion)
+			fmt.Fprintf(w, "SGP.22 version:\t%s\n", info2.SVN)
+			fmt.Fprintf(w, "Firmware version:\t%s\n", info2.EUICCFirmwareVer)
+			fmt.Fprintf(w, "Free non-volatile memory:\t%d\n", info2.ExtCardResource.FreeNonVolatileMemory)
+			fmt.Fprintf(w, "Free volatile memory:\t%d\n", info2.ExtCardResource.FreeVolatileMemory)
+			fmt.Fprintf(w, "Installed applications:\t%d\n", info2.ExtCardResource.InstalledApplication)
+			fmt.Fprintf(w, "Category:\t%s\n", info2.EUICCCategory)
+			fmt.Fprintf(w, "CI keys (verification):\t%s\n", strings.Join(info2.EUICCCiPKIdListForVerification, ", "))
+			fmt.Fprintf(w, "CI keys (signing):\t%s\n", strings.Join(info2.EUICCCiPKIdListForSigning, ", "))
+			fmt.Fprintf(w, "Platform label:\t%s\n", info2.CertificationDataObject.PlatformLabel)
+		}
+		return w.Flush()
+	})
+}
+
+func chipDefaultSMDP(ctx context.Context, app *app, args []string) error {
+	args, err := parseFlags(flag.NewFlagSet("chip default-smdp", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	if err := app.client.SetDefaultDPAddress(ctx, args[0]); err != nil {
+		return err
+	}
+	return app.output.result(nil, nil)
+}
+
+func chipMemoryReset(ctx context.Context, app *app, args []string) error {
+	flags := flag.NewFlagSet("chip memory-reset", flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "do not ask for confirmation, required with -json")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	if !*yes && !app.output.confirm("Delete all profiles on the eUICC?") {
+		return errAborted
+	}
+	if err := app.client.MemoryReset(ctx); err != nil {
+		return err
+	}
+	return app.output.result(nil, nil)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"text/tabwriter"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"github.com/KilimcininKorOglu/euicc-go/driver/probe"
+	"github.com/KilimcininKorOglu/euicc-go/driver/relay"
+)
+
+type probeEUICC struct {
+	Driver string `json:"driver"`
+	Device string `json:"device"`
+	Slot   uint8  `json:"slot,omitempty"`
+	EID    string `json:"eid"`
+	URI    string `json:"uri"`
+}
+
+func driverProbe(ctx context.Context, app *app, args []string) error {
+	var opts probe.Options
+	flags := flag.NewFlagSet("driver probe", flag.ContinueOnError)
+	flags.DurationVar(&opts.Timeout, "timeout", 10*time.Second, "time spent on each device and slot")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	euiccs, err := probe.Discover(ctx, &opts)
+	if err != nil {
+		return err
+	}
+	data := make([]*probeEUICC, len(euiccs))
+	for i, euicc := range euiccs {
+		data[i] = &probeEUICC{Driver: euicc.Driver, Device: euicc.Device, Slot: euicc.Slot, EID: euicc.EID, URI: euicc.URI}
+	}
+	return app.output.result(data, func(out io.Writer) error {
+		if len(euiccs) == 0 {
+			_, err := fmt.Fprintln(out, "no eUICC found")
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "EID\tDRIVER\tDEVICE\tSLOT\tURI")
+		for _, euicc := range euiccs {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", euicc.EID, euicc.Driver, euicc.Device, euicc.Slot, euicc.URI)
+		}
+		return w.Flush()
+	})
+}
+
+// driverRelay serves the channel of the global flags until interrupted,
+// over TCP for relay:// URIs, or over WebSocket for relay+ws:// URIs.
+func driverRelay(ctx context.Context, app *app, args []string) error {
+	flags := flag.NewFlagSet("driver relay", flag.ContinueOnError)
+	address := flags.String("listen", "127.0.0.1:8720", "address the relay listens on, only reachable from this host by default")
+	webSocket := flags.Bool("websocket", false, "accept WebSocket connections instead of TCP connections")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	uri, err := app.options.channelURI()
+	if err != nil {
+		return err
+	}
+	lockFile, err := driver.LockPath(uri)
+	if err != nil {
+		return err
+	}
+	channel, err := driver.Open(uri)
+	if err != nil {
+		return err
+	}
+	server := relay.NewServer(channel, &relay.ServerOptions{LockFile: lockFile})
+	listener, err := net.Listen("tcp", *address)
+	if err != nil {
+		_ = channel.Disconnect()
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = listener.Close() })
+	defer stop()
+	app.output.progress("driver_relay", fmt.Sprintf("relaying %s on %s", uri, listener.Addr()))
+	if *webSocket {
+		err = http.Serve(listener, server.Handler())
+	} else {
+		err = server.Serve(listener)
+	}
+	if ctx.Err() != nil && errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,179 @@
+// Command euicc manages the profiles of an eUICC through a modem or a smart card reader.
+//
+// Usage:
+//
+//	euicc [flags] <command> <subcommand> [arguments]
+//
+// For example:
+//
+//	euicc -driver qmi -device /dev/cdc-wdm0 -slot 1 profile list
+//	euicc -driver at -device /dev/ttyUSB2 profile download -imei 356938035643809 'LPA:1$smdp.io$QR-G-5C-1LS-1W1Z9P7'
+//	euicc -driver ccid notification process -remove
+//
+// With -json the results are written as the JSON envelope of lpac, so that scripts written for lpac keep working.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+)
+
+// command is a node of the command tree, either a group of subcommands or a runnable leaf.
+type command struct {
+	name        string
+	usage       string
+	description string
+	subcommands []*command
+	// function is the lpac name of the function reported when the command fails in JSON mode.
+	function string
+	// standalone commands run without opening the eUICC, app.client is nil.
+	standalone bool
+	run        func(ctx context.Context, app *app, args []string) error
+}
+
+// app is what a command runs with, the global flags, the LPA client and the output.
+type app struct {
+	options *options
+	client  *lpa.Client
+	output  *output
+}
+
+var commands = []*command{
+	{
+		name:        "driver",
+		description: "drivers and devices",
+		subcommands: []*command{
+			{name: "probe", usage: "[-timeout duration]", description: "discover the eUICCs of the modems and readers", function: "driver_probe", standalone: true, run: driverProbe},
+			{name: "relay", usage: "[-listen address] [-websocket]", description: "expose the channel to the relay driver of other hosts", function: "driver_relay", standalone: true, run: driverRelay},
+		},
+	},
+	{
+		name:        "chip",
+		description: "eUICC information and configuration",
+		subcommands: []*command{
+			{name: "info", description: "show the EID, configured addresses and eUICC information", function: "es10c_get_eid", run: chipInfo},
+			{name: "default-smdp", usage: "<address>", description: "set the default SM-DP+ address, an empty address removes it", function: "es10a_set_default_dp_address", run: chipDefaultSMDP},
+			{name: "memory-reset", usage: "[-yes]", description: "delete all profiles and reset the default SM-DP+ address", function: "es10c_euicc_memory_reset", run: chipMemoryReset},
+		},
+	},
+	{
+		name:        "profile",
+		description: "profile management",
+		subcommands: []*command{
+			{name: "list", description: "list the installed profiles", function: "es10c_get_profiles_info", run: profileList},
+			{name: "enable", usage: "[-refresh] <iccid|aid>", description: "enable a profile", function: "es10c_enable_profile", run: profileEnable},
+			{name: "disable", usage: "[-refresh] <iccid|aid>", description: "disable a profile", function: "es10c_disable_profile", run: profileDisable},
+			{name: "delete", usage: "<iccid|aid>", description: "delete a profile", function: "es10c_delete_profile", run: profileDelete},
+			{name: "nickname", usage: "<iccid> <nickname>", description: "set the nickname of a profile", function: "es10c_set_nickname", run: profileNickname},
+			{name: "download", usage: "[flags] [activation code]", description: "download a profile from an SM-DP+", function: "es10b_get_euicc_challenge_and_info", run: profileDownload},
+			{name: "discovery", usage: "[flags]", description: "discover the profiles pending on an SM-DS", function: "es11_authenticate_client", run: profileDiscovery},
+		},
+	},
+	{
+		name:        "notification",
+		description: "notification management",
+		subcommands: []*command{
+			{name: "list", description: "list the pending notifications", function: "es10b_list_notification", run: notificationList},
+			{name: "process", usage: "[-remove] [sequence number...]", description: "send notifications to their SM-DP+, all of them if none is given", function: "es9p_handle_notification", run: notificationProcess},
+			{name: "remove", usage: "<sequence number...>", description: "remove notifications from the eUICC", function: "es10b_remove_notification_from_list", run: notificationRemove},
+		},
+	},
+}
+
+func main() {
+	var opts options
+	flags := flag.NewFlagSet("euicc", flag.ExitOnError)
+	opts.register(flags)
+	flags.Usage = func() { usage(flags.Output(), flags) }
+	_ = flags.Parse(os.Args[1:])
+
+	cmd, args := lookup(commands, flags.Args())
+	if cmd == nil || cmd.run == nil {
+		usage(os.Stderr, flags)
+		os.Exit(2)
+	}
+	if opts.verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+	} else {
+		slog.SetLogLoggerLevel(slog.LevelWarn)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	out := newOutput(opts.json)
+	out.function = cmd.function
+	if err := execute(ctx, &opts, out, cmd, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		out.failure(err)
+		os.Exit(1)
+	}
+}
+
+func execute(ctx context.Context, opts *options, out *output, cmd *command, args []string) error {
+	if cmd.standalone {
+		return cmd.run(ctx, &app{options: opts, output: out}, args)
+	}
+	client, err := opts.open()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return cmd.run(ctx, &app{options: opts, client: client, output: out}, args)
+}
+
+// lookup walks the command tree and returns the command named by the arguments and the remaining arguments.
+func lookup(commands []*command, args []string) (*command, []string) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		if cmd.subcommands == nil {
+			return cmd, args[1:]
+		}
+		return lookup(cmd.subcommands, args[1:])
+	}
+	return nil, nil
+}
+
+func usage(w io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintln(w, "Usage: euicc [flags] <command> <subcommand> [arguments]")
+	fmt.Fprintln(w, "\nCommands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, group := range commands {
+		for _, cmd := range group.subcommands {
+			fmt.Fprintf(tw, "  %s\t%s\n", strings.TrimSpace(group.name+" "+cmd.name+" "+cmd.usage), cmd.description)
+		}
+	}
+	_ = tw.Flush()
+	fmt.Fprintln(w, "\nFlags:")
+	flags.SetOutput(w)
+	flags.PrintDefaults()
+}
+
+// parseFlags parses the flags of a subcommand and returns its positional arguments.
+func parseFlags(flags *flag.FlagSet, args []string, positional int) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if positional >= 0 && flags.NArg() != positional {
+		return nil, fmt.Errorf("%s expects %d argument(s), got %d", flags.Name(), positional, flags.NArg())
+	}
+	return flags.Args(), nil
+}
+
+var errAborted = errors.New("aborted")
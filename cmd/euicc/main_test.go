@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	cmd, args := lookup(commands, []string{"profile", "nickname", "8944476500001224158", "Work"})
+	require.NotNil(t, cmd)
+	assert.Equal(t, "nickname", cmd.name)
+	assert.Equal(t, []string{"8944476500001224158", "Work"}, args)
+
+	cmd, _ = lookup(commands, []string{"profile"})
+	assert.Nil(t, cmd)
+	cmd, _ = lookup(commands, []string{"profile", "unknown"})
+	assert.Nil(t, cmd)
+}
+
+func TestParseProfileIdentifier(t *testing.T) {
+	identifier, err := parseProfileIdentifier("A0000005591010FFFFFFFF8900001100")
+	require.NoError(t, err)
+	assert.Equal(t, sgp22.ISDPAID{0xA0, 0x00, 0x00, 0x05, 0x59, 0x10, 0x10, 0xFF, 0xFF, 0xFF, 0xFF, 0x89, 0x00, 0x00, 0x11, 0x00}, identifier)
+
+	identifier, err = parseProfileIdentifier("8944476500001224158")
+	require.NoError(t, err)
+	assert.Equal(t, "8944476500001224158", identifier.(sgp22.ICCID).String())
+
+	_, err = parseProfileIdentifier("not a profile")
+	assert.Error(t, err)
+}
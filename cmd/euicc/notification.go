@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/KilimcininKorOglu/euicc-go/lpac"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+func notificationList(ctx context.Context, app *app, args []string) error {
+	if _, err := parseFlags(flag.NewFlagSet("notification list", flag.ContinueOnError), args, 0); err != nil {
+		return err
+	}
+	notifications, err := app.client.ListNotification(ctx)
+	if err != nil {
+		return err
+	}
+	return app.output.result(lpac.NewNotifications(notifications), func(out io.Writer) error {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SEQUENCE\tOPERATION\tICCID\tADDRESS")
+		for _, notification := range notifications {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n",
+				notification.SequenceNumber, notification.ProfileManagementOperation,
+				notification.ICCID, notification.Address)
+		}
+		return w.Flush()
+	})
+}
+
+func notificationProcess(ctx context.Context, app *app, args []string) error {
+	var opts lpa.ProcessNotificationsOptions
+	flags := flag.NewFlagSet("notification process", flag.ContinueOnError)
+	flags.BoolVar(&opts.AutoRemove, "remove", false, "remove the notifications from the eUICC once sent")
+	args, err := parseFlags(flags, args, -1)
+	if err != nil {
+		return err
+	}
+	sequenceNumbers, err := parseSequenceNumbers(args)
+	if err != nil {
+		return err
+	}
+	opts.ContinueOnError = true
+	var results []*lpa.NotificationProcessResult
+	if len(sequenceNumbers) == 0 {
+		results, err = app.client.ProcessAllNotifications(ctx, &opts)
+	} else {
+		results, err = app.client.ProcessNotifications(ctx, &opts, sequenceNumbers...)
+	}
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for _, result := range results {
+		if result.Error != nil {
+			failed = append(failed, fmt.Sprintf("%d: %v", result.SequenceNumber, result.Error))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d notifications failed: %s", len(failed), len(results), strings.Join(failed, "; "))
+	}
+	return app.output.result(nil, func(out io.Writer) error {
+		for _, result := range results {
+			if result.Removed {
+				fmt.Fprintf(out, "%d: sent and removed\n", result.SequenceNumber)
+			} else {
+				fmt.Fprintf(out, "%d: sent\n", result.SequenceNumber)
+			}
+		}
+		return nil
+	})
+}
+
+func notificationRemove(ctx context.Context, app *app, args []string) error {
+	args, err := parseFlags(flag.NewFlagSet("notification remove", flag.ContinueOnError), args, -1)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("notification remove expects at least one sequence number")
+	}
+	sequenceNumbers, err := parseSequenceNumbers(args)
+	if err != nil {
+		return err
+	}
+	for _, sequenceNumber := range sequenceNumbers {
+		if err := app.client.RemoveNotificationFromList(ctx, sequenceNumber); err != nil {
+			return fmt.Errorf("remove notification %d: %w", sequenceNumber, err)
+		}
+	}
+	return app.output.result(nil, nil)
+}
+
+func parseSequenceNumbers(args []string) ([]sgp22.SequenceNumber, error) {
+	sequenceNumbers := make([]sgp22.SequenceNumber, len(args))
+	for i, arg := range args {
+		n, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sequence number %q", arg)
+		}
+		sequenceNumbers[i] = sgp22.SequenceNumber(n)
+	}
+	return sequenceNumbers, nil
+}
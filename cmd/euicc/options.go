@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	_ "github.com/KilimcininKorOglu/euicc-go/driver/at"
+	_ "github.com/KilimcininKorOglu/euicc-go/driver/ccid"
+	_ "github.com/KilimcininKorOglu/euicc-go/driver/mbim"
+	_ "github.com/KilimcininKorOglu/euicc-go/driver/qmi"
+	_ "github.com/KilimcininKorOglu/euicc-go/driver/relay"
+	_ "github.com/KilimcininKorOglu/euicc-go/driver/vpcd"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+)
+
+// options are the global flags selecting the driver and configuring the LPA client.
+type options struct {
+	uri     string
+	driver  string
+	device  string
+	slot    uint
+	reader  string
+	aid     string
+	mss     int
+	timeout time.Duration
+	verbose bool
+	json    bool
+}
+
+func (o *options) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.uri, "uri", "", "URI of the channel, such as qmi:///dev/cdc-wdm0?slot=1, overrides the driver flags")
+	flags.StringVar(&o.driver, "driver", "qmi", "driver used to reach the eUICC: qmi, qrtr, mbim, at or ccid")
+	flags.StringVar(&o.device, "device", "", "device path of the modem (default /dev/cdc-wdm0 for qmi and mbim, /dev/ttyUSB2 for at)")
+	flags.UintVar(&o.slot, "slot", 1, "SIM slot of the modem (qmi, qrtr and mbim)")
+	flags.StringVar(&o.reader, "reader", "", "name of the PC/SC reader (ccid, default the first reader)")
+	flags.StringVar(&o.aid, "aid", "", "AID of the ISD-R in hex (default the GSMA ISD-R)")
+	flags.IntVar(&o.mss, "mss", 0, "maximum APDU data size (default 254)")
+	flags.DurationVar(&o.timeout, "timeout", 0, "timeout of the HTTP requests (default 30s)")
+	flags.BoolVar(&o.verbose, "verbose", false, "log the APDU and HTTP exchanges")
+	flags.BoolVar(&o.json, "json", false, "write the results as the JSON output of lpac, without prompting")
+}
+
+// open opens the channel of the selected driver and creates the LPA client on it.
+func (o *options) open() (*lpa.Client, error) {
+	uri, err := o.channelURI()
+	if err != nil {
+		return nil, err
+	}
+	lockFile, err := driver.LockPath(uri)
+	if err != nil {
+		return nil, err
+	}
+	var aid []byte
+	if o.aid != "" {
+		if aid, err = hex.DecodeString(o.aid); err != nil {
+			return nil, fmt.Errorf("invalid AID %q: %w", o.aid, err)
+		}
+	}
+	lock, err := driver.Lock(lockFile, lpa.DefaultLockTimeout)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := driver.Open(uri)
+	if err != nil {
+		_ = lock.Unlock()
+		return nil, err
+	}
+	return lpa.New(&lpa.Options{
+		Channel: channel,
+		AID:     aid,
+		MSS:     o.mss,
+		Timeout: o.timeout,
+		Lock:    lock,
+	})
+}
+
+// channelURI returns the -uri flag, or the URI built from the driver flags.
+func (o *options) channelURI() (string, error) {
+	if o.uri != "" {
+		return o.uri, nil
+	}
+	if o.slot == 0 || o.slot > 255 {
+		return "", fmt.Errorf("invalid slot %d", o.slot)
+	}
+	uri := url.URL{Scheme: o.driver}
+	query := make(url.Values)
+	switch o.driver {
+	case "qmi", "mbim":
+		uri.Path = o.deviceOr("/dev/cdc-wdm0")
+		query.Set("slot", strconv.FormatUint(uint64(o.slot), 10))
+	case "qrtr":
+		query.Set("slot", strconv.FormatUint(uint64(o.slot), 10))
+	case "at":
+		uri.Path = o.deviceOr("/dev/ttyUSB2")
+	case "ccid":
+		uri.Scheme = "pcsc"
+		if o.reader != "" {
+			query.Set("reader", o.reader)
+		}
+	default:
+		return "", fmt.Errorf("unknown driver %q", o.driver)
+	}
+	uri.RawQuery = query.Encode()
+	return uri.String(), nil
+}
+
+func (o *options) deviceOr(device string) string {
+	if o.device != "" {
+		return o.device
+	}
+	return device
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// output writes the results of the commands, either as text for humans or as the JSON envelope of lpac:
+//
+//	{"type":"progress","payload":{"code":0,"message":"es10b_load_bound_profile_package","data":null}}
+//	{"type":"lpa","payload":{"code":0,"message":"success","data":[...]}}
+//	{"type":"lpa","payload":{"code":-1,"message":"es10c_enable_profile","data":"profile not in disabled state"}}
+//
+// In JSON mode the commands never prompt, like lpac: the confirmations are refused unless -yes is given.
+type output struct {
+	json     bool
+	stdout   io.Writer
+	stderr   io.Writer
+	function string
+}
+
+type envelope struct {
+	Type    string  `json:"type"`
+	Payload payload `json:"payload"`
+}
+
+type payload struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+}
+
+func newOutput(json bool) *output {
+	return &output{json: json, stdout: os.Stdout, stderr: os.Stderr}
+}
+
+// progress reports the step a command is performing, function is the lpac name of the step.
+func (o *output) progress(function, text string) {
+	o.function = function
+	if o.json {
+		o.write("progress", 0, function, nil)
+		return
+	}
+	fmt.Fprintln(o.stderr, text)
+}
+
+// result writes the data of a successful command, text renders it when the output is not JSON.
+func (o *output) result(data any, text func(w io.Writer) error) error {
+	if o.json {
+		o.write("lpa", 0, "success", data)
+		return nil
+	}
+	if text == nil {
+		return nil
+	}
+	return text(o.stdout)
+}
+
+// failure writes the error of a failed command.
+func (o *output) failure(err error) {
+	if o.json {
+		o.write("lpa", -1, o.function, err.Error())
+		return
+	}
+	fmt.Fprintln(o.stderr, "euicc:", err)
+}
+
+func (o *output) write(kind string, code int, message string, data any) {
+	_ = json.NewEncoder(o.stdout).Encode(&envelope{
+		Type:    kind,
+		Payload: payload{Code: code, Message: message, Data: data},
+	})
+}
+
+// confirm asks the question on the terminal and reports whether the answer is yes, it is always no in JSON mode.
+func (o *output) confirm(question string) bool {
+	if o.json {
+		return false
+	}
+	answer := strings.ToLower(o.prompt(question + " [y/N]"))
+	return answer == "y" || answer == "yes"
+}
+
+// prompt asks the question on the terminal and returns the answer.
+func (o *output) prompt(question string) string {
+	if o.json {
+		return ""
+	}
+	fmt.Fprintf(o.stderr, "%s: ", question)
+	var answer string
+	_, _ = fmt.Fscanln(os.Stdin, &answer)
+	return strings.TrimSpace(answer)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/KilimcininKorOglu/euicc-go/lpac"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newJSONApp(t *testing.T) (*app, *bytes.Buffer) {
+	card := virtual.New()
+	iccid, err := sgp22.NewICCID("8944476500001224158")
+	require.NoError(t, err)
+	card.AddProfile(&virtual.Profile{
+		ICCID:               iccid,
+		State:               sgp22.ProfileEnabled,
+		ServiceProviderName: "Operator A",
+		ProfileName:         "Profile A",
+		Class:               sgp22.ProfileClassOperational,
+	})
+	client, err := lpa.New(&lpa.Options{Channel: card})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	var stdout bytes.Buffer
+	out := newOutput(true)
+	out.stdout = &stdout
+	return &app{client: client, output: out}, &stdout
+}
+
+func TestOutput_ProfileList(t *testing.T) {
+	app, stdout := newJSONApp(t)
+	require.NoError(t, profileList(context.Background(), app, nil))
+	assert.JSONEq(t, `{"type":"lpa","payload":{"code":0,"message":"success","data":[{
+		"iccid":"8944476500001224158",
+		"isdpAid":"A0000005591010FFFFFFFF8900001000",
+		"profileState":"enabled",
+		"profileNickname":null,
+		"serviceProviderName":"Operator A",
+		"profileName":"Profile A",
+		"iconType":"none",
+		"icon":null,
+		"profileClass":"operational"
+	}]}}`, stdout.String())
+}
+
+func TestOutput_ChipInfo(t *testing.T) {
+	app, stdout := newJSONApp(t)
+	require.NoError(t, chipInfo(context.Background(), app, nil))
+	var response struct {
+		Type    string
+		Payload struct {
+			Code    int
+			Message string
+			Data    map[string]any
+		}
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &response))
+	assert.Equal(t, "lpa", response.Type)
+	assert.Equal(t, "success", response.Payload.Message)
+	assert.Equal(t, "89049032000000000000000000000001", response.Payload.Data["eidValue"])
+	assert.Contains(t, response.Payload.Data, "EuiccConfiguredAddresses")
+	assert.Contains(t, response.Payload.Data["EUICCInfo2"], "extCardResource")
+}
+
+func TestOutput_Failure(t *testing.T) {
+	app, stdout := newJSONApp(t)
+	app.output.function = "es10c_enable_profile"
+	err := profileEnable(context.Background(), app, []string{"8944476500001224158"})
+	require.Error(t, err)
+	app.output.failure(err)
+	assert.JSONEq(t, `{"type":"lpa","payload":{"code":-1,"message":"es10c_enable_profile","data":"profile not in disabled state"}}`, stdout.String())
+}
+
+func TestOutput_Progress(t *testing.T) {
+	var stdout bytes.Buffer
+	out := newOutput(true)
+	out.stdout = &stdout
+	out.progress(lpac.DownloadStageFunction(lpa.DownloadStageInstall), lpa.DownloadStageInstall.String())
+	out.failure(errors.New("install failed"))
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"type":"progress","payload":{"code":0,"message":"es10b_load_bound_profile_package","data":null}}`, lines[0])
+	assert.JSONEq(t, `{"type":"lpa","payload":{"code":-1,"message":"es10b_load_bound_profile_package","data":"install failed"}}`, lines[1])
+}
+
+func TestOutput_ConfirmRefusedInJSON(t *testing.T) {
+	app, stdout := newJSONApp(t)
+	assert.ErrorIs(t, chipMemoryReset(context.Background(), app, nil), errAborted)
+	assert.Empty(t, stdout.String())
+	profiles, err := app.client.ListProfile(context.Background(), nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, profiles, 1)
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/KilimcininKorOglu/euicc-go/lpac"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+func profileList(ctx context.Context, app *app, args []string) error {
+	if _, err := parseFlags(flag.NewFlagSet("profile list", flag.ContinueOnError), args, 0); err != nil {
+		return err
+	}
+	profiles, err := app.client.ListProfile(ctx, nil, nil)
+	if err != nil {
+		return err
+	}
+	return app.output.result(lpac.NewProfiles(profiles), func(out io.Writer) error {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ICCID\tISD-P AID\tSTATE\tCLASS\tPROVIDER\tNAME\tNICKNAME")
+		for _, profile := range profiles {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				profile.ICCID, profile.ISDPAID, profile.ProfileState, profile.ProfileClass,
+				profile.ServiceProviderName, profile.ProfileName, profile.ProfileNickname)
+		}
+		return w.Flush()
+	})
+}
+
+func profileEnable(ctx context.Context, app *app, args []string) error {
+	flags := flag.NewFlagSet("profile enable", flag.ContinueOnError)
+	refresh := flags.Bool("refresh", false, "ask the device to refresh after enabling the profile")
+	args, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	identifier, err := parseProfileIdentifier(args[0])
+	if err != nil {
+		return err
+	}
+	if err := app.client.EnableProfile(ctx, identifier, *refresh); err != nil {
+		return err
+	}
+	return app.output.result(nil, nil)
+}
+
+func profileDisable(ctx context.Context, app *app, args []string) error {
+	flags := flag.NewFlagSet("profile disable", flag.ContinueOnError)
+	refresh := flags.Bool("refresh", false, "ask the device to refresh after disabling the profile")
+	args, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	identifier, err := parseProfileIdentifier(args[0])
+	if err != nil {
+		return err
+	}
+	if err := app.client.DisableProfile(ctx, identifier, *refresh); err != nil {
+		return err
+	}
+	return app.output.result(nil, nil)
+}
+
+func profileDelete(ctx context.Context, app *app, args []string) error {
+	args, err := parseFlags(flag.NewFlagSet("profile delete", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	identifier, err := parseProfileIdentifier(args[0])
+	if err != nil {
+		return err
+	}
+	if err := app.client.DeleteProfile(ctx, identifier); err != nil {
+		return err
+	}
+	return app.output.result(nil, nil)
+}
+
+func profileNickname(ctx context.Context, app *app, args []string) error {
+	args, err := parseFlags(flag.NewFlagSet("profile nickname", flag.ContinueOnError), args, 2)
+	if err != nil {
+		return err
+	}
+	iccid, err := sgp22.NewICCID(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid ICCID %q", args[0])
+	}
+	if err := app.client.SetNickname(ctx, iccid, args[1]); err != nil {
+		return err
+	}
+	return app.output.result(nil, nil)
+}
+
+func profileDownload(ctx context.Context, app *app, args []string) error {
+	var ac lpa.ActivationCode
+	var smdp string
+	flags := flag.NewFlagSet("profile download", flag.ContinueOnError)
+	flags.StringVar(&smdp, "smdp", "", "SM-DP+ address, instead of an activation code")
+	flags.StringVar(&ac.MatchingID, "matching-id", "", "matching ID, instead of an activation code")
+	flags.StringVar(&ac.ConfirmationCode, "confirmation-code", "", "confirmation code, asked on the terminal if required and not given")
+	flags.StringVar(&ac.IMEI, "imei", "", "IMEI of the device, read from the modem when not given")
+	yes := flags.Bool("yes", false, "do not ask for confirmation before downloading the profile, required with -json")
+	args, err := parseFlags(flags, args, -1)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(args) == 1:
+		if err := ac.UnmarshalText([]byte(args[0])); err != nil {
+			return err
+		}
+	case len(args) == 0 && smdp != "":
+		if err := ac.UnmarshalText([]byte("LPA:1$" + smdp + "$" + ac.MatchingID)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("profile download expects an activation code or the -smdp flag")
+	}
+
+	result, err := app.client.DownloadProfile(ctx, &ac, &lpa.DownloadOptions{
+		OnProgress: func(stage lpa.DownloadStage) {
+			app.output.progress(lpac.DownloadStageFunction(stage), stage.String())
+		},
+		OnConfirm: func(metadata *sgp22.ProfileInfo) bool {
+			if *yes {
+				return true
+			}
+			fmt.Fprintf(os.Stderr, "Profile: %s (%s), ICCID %s\n", metadata.ProfileName, metadata.ServiceProviderName, metadata.ICCID)
+			return app.output.confirm("Download this profile?")
+		},
+		OnEnterConfirmationCode: func() string {
+			return app.output.prompt("Confirmation code")
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return errAborted
+	}
+	return app.output.result(nil, func(out io.Writer) error {
+		_, err := fmt.Fprintf(out, "Profile installed, ISD-P AID %s\n", result.ISDPAID())
+		return err
+	})
+}
+
+func profileDiscovery(ctx context.Context, app *app, args []string) error {
+	var opts lpa.DiscoverProfilesOptions
+	var imei string
+	flags := flag.NewFlagSet("profile discovery", flag.ContinueOnError)
+	flags.StringVar(&opts.SMDSAddress, "smds", lpa.DefaultSMDSAddress, "SM-DS address")
+	flags.StringVar(&imei, "imei", "", "IMEI of the device, read from the modem when not given")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	if imei != "" {
+		var err error
+		if opts.IMEI, err = sgp22.NewIMEI(imei); err != nil {
+			return fmt.Errorf("invalid IMEI %q", imei)
+		}
+	}
+	profiles, err := app.client.DiscoverProfiles(ctx, &opts)
+	if err != nil {
+		return err
+	}
+	return app.output.result(lpac.NewDiscoveredProfiles(profiles), func(out io.Writer) error {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "EVENT ID\tSM-DP+")
+		for _, profile := range profiles {
+			fmt.Fprintf(w, "%s\t%s\n", profile.EventID, profile.SMDPAddress)
+		}
+		return w.Flush()
+	})
+}
+
+// parseProfileIdentifier parses an ISD-P AID given in hex or an ICCID.
+func parseProfileIdentifier(value string) (any, error) {
+	if len(value) == 32 {
+		if aid, err := hex.DecodeString(value); err == nil {
+			return sgp22.ISDPAID(aid), nil
+		}
+	}
+	iccid, err := sgp22.NewICCID(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ICCID or ISD-P AID %q", value)
+	}
+	return iccid, nil
+}
@@ -0,0 +1,92 @@
+// Command lpad owns the channels of the eUICCs of the host and serves them over a local HTTP API on a Unix socket,
+// so that several programs manage the profiles without fighting over the modems. See the lpad package for the API.
+//
+// Usage:
+//
+//	lpad [-socket /run/lpad.sock] [-uri qmi:///dev/cdc-wdm0?slot=1 ...]
+//
+// Without -uri the eUICCs are discovered on the modems and readers of the host.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	_ "github.com/KilimcininKorOglu/euicc-go/driver/at"
+	_ "github.com/KilimcininKorOglu/euicc-go/driver/ccid"
+	_ "github.com/KilimcininKorOglu/euicc-go/driver/mbim"
+	_ "github.com/KilimcininKorOglu/euicc-go/driver/qmi"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/KilimcininKorOglu/euicc-go/lpad"
+	"github.com/KilimcininKorOglu/euicc-go/manager"
+)
+
+func main() {
+	var uris []string
+	socket := flag.String("socket", "/run/lpad.sock", "path of the Unix socket")
+	idleTimeout := flag.Duration("idle-timeout", time.Minute, "time the channel of an idle card stays open")
+	timeout := flag.Duration("timeout", 0, "timeout of the HTTP requests to the SM-DP+ (default 30s)")
+	verbose := flag.Bool("verbose", false, "log the APDU and HTTP exchanges")
+	flag.Func("uri", "URI of a channel, such as qmi:///dev/cdc-wdm0?slot=1, may be repeated (default discover the eUICCs)", func(uri string) error {
+		uris = append(uris, uri)
+		return nil
+	})
+	flag.Parse()
+	if *verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, *socket, uris, &manager.Options{
+		Client:      lpa.Options{Timeout: *timeout},
+		IdleTimeout: *idleTimeout,
+		Lock:        true,
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, "lpad:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, socket string, uris []string, opts *manager.Options) error {
+	m := manager.New(opts)
+	defer m.Close()
+	if len(uris) == 0 {
+		if _, err := m.Discover(ctx, nil); err != nil {
+			return err
+		}
+	}
+	for _, uri := range uris {
+		if _, err := m.Add(ctx, uri); err != nil {
+			return fmt.Errorf("%s: %w", uri, err)
+		}
+	}
+	for _, card := range m.Cards() {
+		slog.Info("[lpad] serving", "eid", card.EID, "uri", card.URI)
+	}
+
+	listener, err := lpad.Listen(socket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(socket)
+	server := &http.Server{Handler: lpad.NewServer(&lpad.Options{Manager: m})}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdown)
+	}()
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
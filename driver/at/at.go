@@ -2,109 +2,311 @@ package at
 
 import (
 	"bufio"
+	"bytes"
+	"cmp"
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
 )
 
+// AT is a channel to the SIM of a modem through its AT command port.
+//
+// It prefers the logical channel commands of 3GPP TS 27.007, AT+CCHO, AT+CGLA and AT+CCHC,
+// and falls back to MANAGE CHANNEL and SELECT sent with AT+CSIM when the modem does not support them.
 type AT struct {
-	s       io.ReadWriteCloser
-	channel byte
+	s           io.ReadWriteCloser
+	reader      *bufio.Reader
+	readTimeout time.Duration
+	csim        bool
+	cgla        bool
+	session     int
+	channel     byte
+	observer    apdu.Observer
 }
 
-func New(device string) (apdu.SmartCardChannel, error) {
-	var at AT
-	var err error
-	if at.s, err = Open(device); err != nil {
+var _ driver.DeviceIdentity = (*AT)(nil)
+
+// Error is the final result code of a failed AT command, such as ERROR or +CME ERROR: 3.
+type Error struct {
+	Command string
+	Result  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("at: %s: %s", e.Command, e.Result)
+}
+
+func init() {
+	driver.Register("at", func(uri *url.URL) (apdu.SmartCardChannel, error) {
+		device := driver.Device(uri, "")
+		if device == "" {
+			return nil, errors.New("at: the URI has no serial port device")
+		}
+		opts, err := parseOptions(uri.Query())
+		if err != nil {
+			return nil, err
+		}
+		return New(device, opts)
+	})
+	driver.RegisterEnumerator("at", func() ([]*url.URL, error) {
+		devices := append(driver.Devices("/dev/ttyUSB*"), driver.Devices("/dev/ttyACM*")...)
+		uris := make([]*url.URL, len(devices))
+		for i, device := range devices {
+			uris[i] = &url.URL{Scheme: "at", Path: device}
+		}
+		return uris, nil
+	})
+}
+
+// New opens the serial port of the device, the options may be nil.
+func New(device string, opts *Options) (apdu.SmartCardChannel, error) {
+	opts = opts.withDefaults()
+	s, err := Open(device, opts)
+	if err != nil {
 		return nil, fmt.Errorf("open serial port %s: %w", device, err)
 	}
-	return &at, nil
+	return &AT{s: s, reader: bufio.NewReader(s), readTimeout: opts.ReadTimeout}, nil
 }
 
-func (a *AT) run(command string) (string, error) {
-	if _, err := a.s.Write([]byte(command + "\r\n")); err != nil {
-		return "", err
+// deadliner is implemented by the serial ports supporting read deadlines.
+type deadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// run sends the command and returns its information lines, until the final result code.
+// The echo of the command, the empty lines and the lines of the unsolicited result codes are skipped:
+// information lines starting with a + are kept when they start with the prefix of the command only.
+func (a *AT) run(ctx context.Context, command string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// The read deadline is the deadline of the context, or the read timeout when it comes first.
+	deadline, ok := ctx.Deadline()
+	if timeout := time.Now().Add(a.readTimeout); a.readTimeout > 0 && (!ok || timeout.Before(deadline)) {
+		deadline = timeout
+	}
+	if port, ok := a.s.(deadliner); ok {
+		if err := port.SetReadDeadline(deadline); err == nil {
+			defer port.SetReadDeadline(time.Time{})
+			stop := context.AfterFunc(ctx, func() { _ = port.SetReadDeadline(time.Unix(1, 0)) })
+			defer stop()
+		}
 	}
-	reader := bufio.NewReader(a.s)
-	var sb strings.Builder
+	if _, err := a.s.Write([]byte(command + "\r")); err != nil {
+		return nil, err
+	}
+	prefix, _, _ := strings.Cut(strings.TrimPrefix(command, "AT"), "=")
+	var lines []string
 	for {
-		line, err := reader.ReadString('\n')
+		line, err := a.reader.ReadString('\n')
 		if err != nil {
-			return "", err
+			// The read deadline may expire before the context is done.
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				if deadline, ok := ctx.Deadline(); ctx.Err() != nil || ok && !time.Now().Before(deadline) {
+					return nil, cmp.Or(ctx.Err(), context.DeadlineExceeded)
+				}
+				return nil, fmt.Errorf("at: %s: no answer from the modem: %w", command, err)
+			}
+			return nil, err
 		}
 		line = strings.TrimSpace(line)
 		switch {
-		case strings.Contains(line, "OK"):
-			return strings.TrimSpace(sb.String()), nil
-		case strings.Contains(line, "ERR"):
-			return "", errors.New(line)
+		case line == "" || line == command:
+		case line == "OK":
+			return lines, nil
+		case line == "ERROR" || strings.HasPrefix(line, "+CME ERROR:") || strings.HasPrefix(line, "+CMS ERROR:"):
+			return nil, &Error{Command: command, Result: line}
+		case strings.HasPrefix(line, "+") || strings.HasPrefix(line, "^"):
+			if strings.HasPrefix(line, prefix+":") {
+				lines = append(lines, strings.TrimSpace(strings.TrimPrefix(line, prefix+":")))
+			}
 		default:
-			sb.WriteString(line + "\n")
+			lines = append(lines, line)
 		}
 	}
 }
 
-func (a *AT) Transmit(command []byte) ([]byte, error) {
-	cmd := fmt.Sprintf("%X", command)
-	cmd = fmt.Sprintf("AT+CSIM=%d,%q", len(cmd), cmd)
-	r, err := a.run(cmd)
+func (a *AT) SetObserver(observer apdu.Observer) {
+	a.observer = observer
+}
+
+func (a *AT) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	response, err := a.transmit(ctx, command)
+	if a.observer != nil {
+		a.observer(command, response, err)
+	}
+	return response, err
+}
+
+func (a *AT) transmit(ctx context.Context, command []byte) ([]byte, error) {
+	var cmd string
+	if a.session != 0 {
+		cmd = fmt.Sprintf("%X", withoutChannel(command))
+		cmd = fmt.Sprintf("AT+CGLA=%d,%d,%q", a.session, len(cmd), cmd)
+	} else {
+		cmd = fmt.Sprintf("%X", command)
+		cmd = fmt.Sprintf("AT+CSIM=%d,%q", len(cmd), cmd)
+	}
+	lines, err := a.run(ctx, cmd)
 	if err != nil {
 		return nil, err
 	}
-	sw, err := a.sw(r)
+	sw, err := parseResponse(lines)
 	if err != nil {
 		return nil, err
 	}
 	if sw[len(sw)-2] != 0x90 && sw[len(sw)-2] != 0x61 {
-		return sw, fmt.Errorf("unexpected response: %X", sw)
+		return sw, &apdu.StatusWordError{Command: command, SW: apdu.Response(sw).SW()}
 	}
 	return sw, nil
 }
 
-func (a *AT) sw(sw string) ([]byte, error) {
-	lastIdx := strings.LastIndex(sw, ",")
-	if lastIdx == -1 {
-		return nil, errors.New("invalid response")
+// withoutChannel returns the command with the logical channel of its CLA cleared, AT+CGLA addresses the channel with the session.
+func withoutChannel(command []byte) []byte {
+	if len(command) == 0 {
+		return command
+	}
+	command = bytes.Clone(command)
+	if command[0]&0x40 == 0 {
+		command[0] &= 0x9C
+	} else {
+		command[0] &= 0xB0
 	}
-	return hex.DecodeString(sw[lastIdx+2 : len(sw)-1])
+	return command
 }
 
+// parseResponse parses the <length>,"<response>" information line of AT+CSIM and AT+CGLA.
+func parseResponse(lines []string) ([]byte, error) {
+	if len(lines) == 0 {
+		return nil, errors.New("at: missing response")
+	}
+	line := lines[len(lines)-1]
+	length, data, ok := strings.Cut(line, ",")
+	if !ok {
+		return nil, fmt.Errorf("at: invalid response %q", line)
+	}
+	data = strings.Trim(strings.TrimSpace(data), `"`)
+	if n, err := strconv.Atoi(strings.TrimSpace(length)); err != nil || n != len(data) {
+		return nil, fmt.Errorf("at: invalid response %q", line)
+	}
+	response, err := hex.DecodeString(data)
+	if err != nil || len(response) < 2 {
+		return nil, fmt.Errorf("at: invalid response %q", line)
+	}
+	return response, nil
+}
+
+// lifecycleTimeout bounds Connect, OpenLogicalChannel and CloseLogicalChannel, which take no context,
+// so that a serial port without a modem answering AT commands does not block them forever.
+const lifecycleTimeout = 10 * time.Second
+
+// Connect disables the echo and detects the commands supported by the modem,
+// then sends the terminal capabilities when AT+CSIM is supported.
+// It does not close the serial port when it fails, Disconnect does.
 func (a *AT) Connect() error {
-	if _, err := a.run("AT+CSIM=?"); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout)
+	defer cancel()
+	// The modems refusing ATE0 still answer, with the echo skipped by run.
+	var atErr *Error
+	if _, err := a.run(ctx, "ATE0"); err != nil && !errors.As(err, &atErr) {
 		return err
 	}
-	_, err := a.Transmit([]byte{0x80, 0xAA, 0x00, 0x00, 0x0A, 0xA9, 0x08, 0x81, 0x00, 0x82, 0x01, 0x01, 0x83, 0x01, 0x07})
+	_, err := a.run(ctx, "AT+CSIM=?")
+	a.csim = err == nil
+	if _, err := a.run(ctx, "AT+CCHO=?"); err == nil {
+		_, err = a.run(ctx, "AT+CGLA=?")
+		a.cgla = err == nil
+	}
+	switch {
+	case ctx.Err() != nil:
+		err = ctx.Err()
+	case a.csim:
+		_, err = a.Transmit(ctx, []byte{0x80, 0xAA, 0x00, 0x00, 0x0A, 0xA9, 0x08, 0x81, 0x00, 0x82, 0x01, 0x01, 0x83, 0x01, 0x07})
+	case a.cgla:
+		err = nil
+	default:
+		err = fmt.Errorf("at: the modem supports neither AT+CSIM nor AT+CGLA: %w", err)
+	}
 	return err
 }
 
+// OpenLogicalChannel opens a logical channel with AT+CCHO, or with MANAGE CHANNEL and SELECT when it is not supported
+// or fails while AT+CSIM is supported, and returns the logical channel.
+// The channel of an AT+CCHO session is not known, it returns 0 and the modem addresses the session with AT+CGLA.
 func (a *AT) OpenLogicalChannel(AID []byte) (byte, error) {
-	channel, err := a.Transmit([]byte{0x00, 0x70, 0x00, 0x00, 0x01})
-	if err != nil {
-		return 0, err
+	ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout)
+	defer cancel()
+	if a.cgla {
+		err := a.openSession(ctx, AID)
+		if err == nil || !a.csim {
+			return 0, err
+		}
 	}
-	if channel[len(channel)-2] != 0x90 {
-		return 0, fmt.Errorf("open logical channel: %X", channel)
+	// Transmit fails on the status words other than 9000 and 61XX.
+	channel, err := a.Transmit(ctx, []byte{0x00, 0x70, 0x00, 0x00, 0x01})
+	if err != nil {
+		return 0, fmt.Errorf("open logical channel: %w", err)
 	}
 	a.channel = channel[0]
-	sw, err := a.Transmit(append([]byte{a.channel, 0xA4, 0x04, 0x00, byte(len(AID))}, AID...))
+	command := append([]byte{a.channel, 0xA4, 0x04, 0x00, byte(len(AID))}, AID...)
+	if _, err := a.Transmit(ctx, command); err != nil {
+		return 0, fmt.Errorf("select AID: %w", err)
+	}
+	return a.channel, nil
+}
+
+// openSession opens a logical channel with AT+CCHO, the modem answers the session with or without the +CCHO prefix.
+func (a *AT) openSession(ctx context.Context, AID []byte) error {
+	lines, err := a.run(ctx, fmt.Sprintf("AT+CCHO=%q", fmt.Sprintf("%X", AID)))
 	if err != nil {
-		return 0, err
+		return err
 	}
-	if sw[len(sw)-2] != 0x90 && sw[len(sw)-2] != 0x61 {
-		return 0, fmt.Errorf("select AID: %X", sw)
+	for _, line := range lines {
+		if session, err := strconv.Atoi(line); err == nil && session > 0 {
+			a.session = session
+			return nil
+		}
 	}
-	return a.channel, nil
+	return fmt.Errorf("at: invalid AT+CCHO response %q", lines)
 }
 
 func (a *AT) CloseLogicalChannel(channel byte) error {
-	_, err := a.Transmit([]byte{0x00, 0x70, 0x80, channel, 0x00})
+	ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout)
+	defer cancel()
+	if a.session != 0 {
+		_, err := a.run(ctx, fmt.Sprintf("AT+CCHC=%d", a.session))
+		a.session = 0
+		return err
+	}
+	_, err := a.Transmit(ctx, []byte{0x00, 0x70, 0x80, channel, 0x00})
 	return err
 }
 
+// IMEI reads the IMEI of the modem with AT+CGSN, the modem answers it with or without the +CGSN prefix and quotes.
+func (a *AT) IMEI(ctx context.Context) (string, error) {
+	lines, err := a.run(ctx, "AT+CGSN")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range lines {
+		imei := strings.Trim(line, `"`)
+		if len(imei) >= 14 && strings.Trim(imei, "0123456789") == "" {
+			return imei, nil
+		}
+	}
+	return "", fmt.Errorf("at: invalid AT+CGSN response %q", lines)
+}
+
 func (a *AT) Disconnect() error {
 	return a.s.Close()
 }
@@ -0,0 +1,134 @@
+package at
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// script is a modem answering each command with the lines of its entry, an unknown command with ERROR.
+type script struct {
+	bytes.Buffer
+	answers  map[string]string
+	commands []string
+}
+
+func (s *script) Write(data []byte) (int, error) {
+	command := strings.TrimSpace(string(data))
+	s.commands = append(s.commands, command)
+	answer, ok := s.answers[command]
+	if !ok {
+		answer = "\r\nERROR\r\n"
+	}
+	s.Buffer.WriteString(answer)
+	return len(data), nil
+}
+
+func (s *script) Close() error { return nil }
+
+func newScript(answers map[string]string) (*AT, *script) {
+	s := &script{answers: answers}
+	return &AT{s: s, reader: bufio.NewReader(s)}, s
+}
+
+func TestAT_CGLA(t *testing.T) {
+	at, modem := newScript(map[string]string{
+		"ATE0":      "ATE0\r\r\nOK\r\n",
+		"AT+CSIM=?": "\r\n+CME ERROR: 4\r\n",
+		"AT+CCHO=?": "\r\nOK\r\n",
+		"AT+CGLA=?": "\r\n+QIND: SMS DONE\r\n\r\nOK\r\n",
+		`AT+CCHO="A0000005591010FFFFFFFF8900000100"`: "\r\n+CCHO: 3\r\n\r\nOK\r\n",
+		// The payload contains OK and ERR, and an unsolicited result code comes before the response.
+		`AT+CGLA=3,10,"80E2910000"`: "\r\n^RSSI: 12\r\n+CGLA: 12,\"4F4BE52E9000\"\r\n\r\nOK\r\n",
+		`AT+CGLA=3,10,"80E2910001"`: "\r\n+CGLA: 4,\"6A88\"\r\n\r\nOK\r\n",
+		"AT+CCHC=3":                 "\r\nOK\r\n",
+	})
+	require.NoError(t, at.Connect())
+
+	channel, err := at.OpenLogicalChannel([]byte{0xA0, 0x00, 0x00, 0x05, 0x59, 0x10, 0x10, 0xFF, 0xFF, 0xFF, 0xFF, 0x89, 0x00, 0x00, 0x01, 0x00})
+	require.NoError(t, err)
+	assert.Zero(t, channel)
+	response, err := at.Transmit(context.Background(), []byte{0x80, 0xE2, 0x91, 0x00, 0x00})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x4F, 0x4B, 0xE5, 0x2E, 0x90, 0x00}, response)
+	response, err = at.Transmit(context.Background(), []byte{0x80, 0xE2, 0x91, 0x00, 0x01})
+	var statusErr *apdu.StatusWordError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, uint16(0x6A88), statusErr.SW)
+	assert.Equal(t, []byte{0x6A, 0x88}, response)
+	require.NoError(t, at.CloseLogicalChannel(channel))
+	assert.Equal(t, "AT+CCHC=3", modem.commands[len(modem.commands)-1])
+}
+
+func TestAT_CGLASession(t *testing.T) {
+	at, _ := newScript(map[string]string{
+		"ATE0":           "\r\nOK\r\n",
+		"AT+CSIM=?":      "\r\nERROR\r\n",
+		"AT+CCHO=?":      "\r\nOK\r\n",
+		"AT+CGLA=?":      "\r\nOK\r\n",
+		`AT+CCHO="A000"`: "\r\n+CCHO: 1234\r\n\r\nOK\r\n",
+		// The channel bits of the CLA are cleared, the session addresses the channel.
+		`AT+CGLA=1234,10,"80E2910000"`: "\r\n+CGLA: 4,\"9000\"\r\n\r\nOK\r\n",
+		"AT+CCHC=1234":                 "\r\nOK\r\n",
+	})
+	require.NoError(t, at.Connect())
+
+	channel, err := at.OpenLogicalChannel([]byte{0xA0, 0x00})
+	require.NoError(t, err)
+	for _, cla := range []byte{0x80, 0x83, 0xC5} {
+		_, err = at.Transmit(context.Background(), []byte{cla, 0xE2, 0x91, 0x00, 0x00})
+		require.NoError(t, err)
+	}
+	require.NoError(t, at.CloseLogicalChannel(channel))
+}
+
+func TestAT_CSIMFallback(t *testing.T) {
+	at, _ := newScript(map[string]string{
+		"ATE0":      "\r\nOK\r\n",
+		"AT+CSIM=?": "\r\nOK\r\n",
+		"AT+CCHO=?": "\r\nOK\r\n",
+		"AT+CGLA=?": "\r\nOK\r\n",
+		`AT+CSIM=30,"80AA00000AA9088100820101830107"`: "\r\n+CSIM: 4,\"9000\"\r\n\r\nOK\r\n",
+		`AT+CCHO="A000"`:              "\r\n+CME ERROR: 3\r\n",
+		`AT+CSIM=10,"0070000001"`:     "\r\n+CSIM: 6,\"019000\"\r\n\r\nOK\r\n",
+		`AT+CSIM=14,"01A4040002A000"`: "\r\n+CSIM: 4,\"6A82\"\r\n\r\nOK\r\n",
+	})
+	require.NoError(t, at.Connect())
+
+	_, err := at.OpenLogicalChannel([]byte{0xA0, 0x00})
+	assert.EqualError(t, err, "select AID: returned an unexpected response with status 6A82")
+}
+
+func TestAT_Error(t *testing.T) {
+	at, _ := newScript(map[string]string{
+		"ATE0": "\r\nOK\r\n",
+	})
+	err := at.Connect()
+	var atErr *Error
+	require.ErrorAs(t, err, &atErr)
+	assert.Equal(t, "AT+CSIM=?", atErr.Command)
+	assert.Equal(t, "ERROR", atErr.Result)
+}
+
+func TestAT_IMEI(t *testing.T) {
+	for answer, expected := range map[string]string{
+		"\r\n356938035643809\r\n\r\nOK\r\n":            "356938035643809",
+		"\r\n+CGSN: \"356938035643809\"\r\n\r\nOK\r\n": "356938035643809",
+		"\r\n+CGSN: unknown\r\n\r\nOK\r\n":             "",
+	} {
+		at, _ := newScript(map[string]string{"AT+CGSN": answer})
+		imei, err := at.IMEI(context.Background())
+		if expected == "" {
+			assert.ErrorContains(t, err, "invalid AT+CGSN response")
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, expected, imei)
+	}
+}
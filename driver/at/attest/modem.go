@@ -0,0 +1,206 @@
+// Package attest provides a fake AT modem on a pseudo-terminal, for testing the AT driver without a modem.
+//
+// The modem answers AT+CSIM, AT+CCHO, AT+CGLA, AT+CCHC and AT+CGSN with a channel,
+// usually a virtual eUICC, and can make its answers noisy as real modems do:
+//
+//	modem, err := attest.NewModem(virtual.New(), &attest.Options{Echo: true, URC: "+QIND: SMS DONE"})
+//	defer modem.Close()
+//	channel, err := at.New(modem.Path(), nil)
+//
+// The pseudo-terminals are only supported on Linux.
+package attest
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+)
+
+// Options configure the modem.
+type Options struct {
+	// IMEI is answered to AT+CGSN, it defaults to 356938035643809.
+	IMEI string
+	// Echo echoes the commands, even after ATE0 as some modems do.
+	Echo bool
+	// URC is an unsolicited result code sent before each answer.
+	URC string
+	// Delay is waited before each answer.
+	Delay time.Duration
+	// Split writes the answers a few bytes at a time, so that the lines are split across reads.
+	Split bool
+	// DisableCSIM answers ERROR to AT+CSIM.
+	DisableCSIM bool
+	// DisableLogicalChannels answers ERROR to AT+CCHO, AT+CGLA and AT+CCHC.
+	DisableLogicalChannels bool
+}
+
+// Modem is a fake AT modem serving a channel on a pseudo-terminal.
+type Modem struct {
+	channel apdu.SmartCardChannel
+	opts    Options
+	// port is the modem side of the pseudo-terminal, path is the device opened by the host.
+	// The modem keeps tty, the host side, open so that the port stays usable while the host reopens it.
+	port *os.File
+	tty  *os.File
+	path string
+	done chan struct{}
+}
+
+// Path returns the device of the pseudo-terminal, to open with at.New.
+func (m *Modem) Path() string {
+	return m.path
+}
+
+// Close stops the modem, closes the pseudo-terminal and disconnects the channel.
+func (m *Modem) Close() error {
+	err := m.port.Close()
+	<-m.done
+	_ = m.tty.Close()
+	if disconnectErr := m.channel.Disconnect(); err == nil {
+		err = disconnectErr
+	}
+	return err
+}
+
+func (m *Modem) serve() {
+	defer close(m.done)
+	reader := bufio.NewReader(m.port)
+	for {
+		line, err := reader.ReadString('\r')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+		if command == "" {
+			continue
+		}
+		if err := m.answer(command, m.handle(command)); err != nil {
+			return
+		}
+	}
+}
+
+// answer writes the answer of the command, with the noise of the options.
+func (m *Modem) answer(command string, lines []string) error {
+	time.Sleep(m.opts.Delay)
+	var answer strings.Builder
+	if m.opts.Echo {
+		answer.WriteString(command + "\r")
+	}
+	if m.opts.URC != "" {
+		answer.WriteString("\r\n" + m.opts.URC + "\r\n")
+	}
+	for _, line := range lines {
+		answer.WriteString("\r\n" + line + "\r\n")
+	}
+	data := []byte(answer.String())
+	if !m.opts.Split {
+		_, err := m.port.Write(data)
+		return err
+	}
+	for len(data) > 0 {
+		n := min(len(data), 3)
+		if _, err := m.port.Write(data[:n]); err != nil {
+			return err
+		}
+		data = data[n:]
+		time.Sleep(time.Millisecond)
+	}
+	return nil
+}
+
+// handle runs the command and returns the lines of its answer, ending with the final result code.
+func (m *Modem) handle(command string) []string {
+	name, arguments, _ := strings.Cut(strings.TrimPrefix(strings.ToUpper(command), "AT"), "=")
+	switch {
+	case name == "" || name == "E0" || name == "E1":
+		return []string{"OK"}
+	case name == "+CGSN":
+		return []string{m.opts.IMEI, "OK"}
+	case name == "+CSIM" && !m.opts.DisableCSIM:
+		if arguments == "?" {
+			return []string{"OK"}
+		}
+		return m.transmit("+CSIM", arguments)
+	case name == "+CCHO" && !m.opts.DisableLogicalChannels:
+		if arguments == "?" {
+			return []string{"OK"}
+		}
+		aid, err := hex.DecodeString(strings.Trim(arguments, `"`))
+		if err != nil {
+			return []string{"+CME ERROR: 50"}
+		}
+		channel, err := m.channel.OpenLogicalChannel(aid)
+		if err != nil {
+			return []string{"+CME ERROR: 4"}
+		}
+		return []string{strconv.Itoa(int(channel)), "OK"}
+	case name == "+CGLA" && !m.opts.DisableLogicalChannels:
+		if arguments == "?" {
+			return []string{"OK"}
+		}
+		session, arguments, ok := strings.Cut(arguments, ",")
+		if _, err := strconv.Atoi(session); !ok || err != nil {
+			return []string{"+CME ERROR: 50"}
+		}
+		return m.transmit("+CGLA", arguments)
+	case name == "+CCHC" && !m.opts.DisableLogicalChannels:
+		if arguments == "?" {
+			return []string{"OK"}
+		}
+		session, err := strconv.Atoi(arguments)
+		if err != nil {
+			return []string{"+CME ERROR: 50"}
+		}
+		if err := m.channel.CloseLogicalChannel(byte(session)); err != nil {
+			return []string{"+CME ERROR: 4"}
+		}
+		return []string{"OK"}
+	}
+	return []string{"ERROR"}
+}
+
+// transmit sends the <length>,"<command>" arguments of AT+CSIM and AT+CGLA to the channel.
+func (m *Modem) transmit(name, arguments string) []string {
+	length, data, ok := strings.Cut(arguments, ",")
+	data = strings.Trim(data, `"`)
+	command, err := hex.DecodeString(data)
+	if n, _ := strconv.Atoi(length); !ok || err != nil || n != len(data) {
+		return []string{"+CME ERROR: 50"}
+	}
+	response, err := m.channel.Transmit(context.Background(), command)
+	var statusErr *apdu.StatusWordError
+	if err != nil && (!errors.As(err, &statusErr) || len(response) < 2) {
+		return []string{"+CME ERROR: 4"}
+	}
+	return []string{fmt.Sprintf("%s: %d,\"%X\"", name, len(response)*2, response), "OK"}
+}
+
+// NewModem connects the channel, as a modem powers its SIM on, and serves it on a new pseudo-terminal.
+func NewModem(channel apdu.SmartCardChannel, opts *Options) (*Modem, error) {
+	if err := channel.Connect(); err != nil {
+		return nil, err
+	}
+	port, tty, err := openPTY()
+	if err != nil {
+		_ = channel.Disconnect()
+		return nil, fmt.Errorf("attest: open pseudo-terminal: %w", err)
+	}
+	m := &Modem{channel: channel, port: port, tty: tty, path: tty.Name(), done: make(chan struct{})}
+	if opts != nil {
+		m.opts = *opts
+	}
+	if m.opts.IMEI == "" {
+		m.opts.IMEI = "356938035643809"
+	}
+	go m.serve()
+	return m, nil
+}
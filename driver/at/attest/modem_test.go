@@ -0,0 +1,61 @@
+//go:build linux
+
+package attest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver/at"
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModem(t *testing.T) {
+	for name, opts := range map[string]*Options{
+		"logical channels": nil,
+		"csim":             {DisableLogicalChannels: true},
+		"noisy":            {Echo: true, URC: "+QIND: SMS DONE", Split: true, Delay: time.Millisecond},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			card := virtual.New()
+			modem, err := NewModem(card, opts)
+			require.NoError(t, err)
+			defer modem.Close()
+
+			// The port is opened twice, as a host reconnecting to the modem.
+			for range 2 {
+				channel, err := at.New(modem.Path(), nil)
+				require.NoError(t, err)
+				client, err := lpa.New(&lpa.Options{Channel: channel})
+				require.NoError(t, err)
+				eid, err := client.EID(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, card.EID, eid)
+				profiles, err := client.ListProfile(ctx, nil, nil)
+				require.NoError(t, err)
+				assert.Empty(t, profiles)
+				require.NoError(t, client.Close())
+			}
+		})
+	}
+}
+
+func TestModem_Timeout(t *testing.T) {
+	modem, err := NewModem(virtual.New(), &Options{Delay: 50 * time.Millisecond})
+	require.NoError(t, err)
+	defer modem.Close()
+
+	channel, err := at.New(modem.Path(), nil)
+	require.NoError(t, err)
+	require.NoError(t, channel.Connect())
+	defer channel.Disconnect()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = channel.Transmit(ctx, []byte{0x80, 0xAA, 0x00, 0x00})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,50 @@
+package attest
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY opens a pseudo-terminal in raw mode and returns its master and slave sides.
+func openPTY() (port *os.File, tty *os.File, err error) {
+	if port, err = os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0); err != nil {
+		return nil, nil, err
+	}
+	conn, err := port.SyscallConn()
+	if err != nil {
+		port.Close()
+		return nil, nil, err
+	}
+	var n uint32
+	var ioctlErr error
+	if err = conn.Control(func(fd uintptr) {
+		if ioctlErr = unix.IoctlSetPointerInt(int(fd), unix.TIOCSPTLCK, 0); ioctlErr != nil {
+			return
+		}
+		if n, ioctlErr = unix.IoctlGetUint32(int(fd), unix.TIOCGPTN); ioctlErr != nil {
+			return
+		}
+		// The modem answers are not echoed back nor translated, until the host configures the port.
+		var termios *unix.Termios
+		if termios, ioctlErr = unix.IoctlGetTermios(int(fd), unix.TCGETS); ioctlErr != nil {
+			return
+		}
+		termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+		termios.Oflag &^= unix.OPOST
+		termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+		ioctlErr = unix.IoctlSetTermios(int(fd), unix.TCSETS, termios)
+	}); err == nil {
+		err = ioctlErr
+	}
+	if err != nil {
+		port.Close()
+		return nil, nil, err
+	}
+	if tty, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|unix.O_NOCTTY, 0); err != nil {
+		port.Close()
+		return nil, nil, err
+	}
+	return port, tty, nil
+}
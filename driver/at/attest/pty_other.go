@@ -0,0 +1,12 @@
+//go:build !linux
+
+package attest
+
+import (
+	"errors"
+	"os"
+)
+
+func openPTY() (port *os.File, tty *os.File, err error) {
+	return nil, nil, errors.New("pseudo-terminals are only supported on Linux")
+}
@@ -0,0 +1,83 @@
+package at
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// FlowControl is the flow control of a serial port.
+type FlowControl int
+
+const (
+	FlowControlNone     FlowControl = iota
+	FlowControlHardware             // RTS/CTS
+	FlowControlSoftware             // XON/XOFF
+)
+
+// Options configure the serial port, they are set by the query of the at URIs:
+//
+//	at:///dev/ttyUSB2?baud=921600&flow=rtscts&timeout=1m&exclusive=true
+type Options struct {
+	// BaudRate defaults to 115200.
+	BaudRate int
+	// FlowControl defaults to none, the flow query is none, rtscts or xonxoff.
+	FlowControl FlowControl
+	// ReadTimeout bounds the wait for the answer of each command, so that a silent modem fails the command
+	// instead of blocking it when the context has no earlier deadline. It defaults to 30 seconds.
+	ReadTimeout time.Duration
+	// Exclusive takes the UUCP lock file of the device and sets TIOCEXCL,
+	// so that ModemManager and the other programs honoring them leave the port alone.
+	Exclusive bool
+	// LockDirectory is the directory of the UUCP lock files, it defaults to /var/lock.
+	LockDirectory string
+}
+
+func (o *Options) withDefaults() *Options {
+	opts := new(Options)
+	if o != nil {
+		*opts = *o
+	}
+	if opts.BaudRate == 0 {
+		opts.BaudRate = 115200
+	}
+	if opts.ReadTimeout == 0 {
+		opts.ReadTimeout = 30 * time.Second
+	}
+	if opts.LockDirectory == "" {
+		opts.LockDirectory = "/var/lock"
+	}
+	return opts
+}
+
+// parseOptions returns the options of the query of an at URI.
+func parseOptions(query url.Values) (*Options, error) {
+	opts := new(Options)
+	var err error
+	if value := query.Get("baud"); value != "" {
+		if opts.BaudRate, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("at: invalid baud %q", value)
+		}
+	}
+	switch value := query.Get("flow"); value {
+	case "", "none":
+	case "rtscts":
+		opts.FlowControl = FlowControlHardware
+	case "xonxoff":
+		opts.FlowControl = FlowControlSoftware
+	default:
+		return nil, fmt.Errorf("at: invalid flow %q, expected none, rtscts or xonxoff", value)
+	}
+	if value := query.Get("timeout"); value != "" {
+		if opts.ReadTimeout, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("at: invalid timeout %q: %w", value, err)
+		}
+	}
+	if value := query.Get("exclusive"); value != "" {
+		if opts.Exclusive, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("at: invalid exclusive %q: %w", value, err)
+		}
+	}
+	return opts, nil
+}
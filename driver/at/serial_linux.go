@@ -4,8 +4,15 @@
 package at
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"golang.org/x/sys/unix"
 )
@@ -13,39 +20,100 @@ import (
 type SerialPort struct {
 	f          *os.File
 	oldTermios *unix.Termios
+	exclusive  bool
+	lockFile   string
 }
 
-func Open(name string) (io.ReadWriteCloser, error) {
-	f, err := os.OpenFile(name, os.O_RDWR|unix.O_NOCTTY, 0666)
-	if err != nil {
+var baudRates = map[int]uint32{
+	1200:    unix.B1200,
+	2400:    unix.B2400,
+	4800:    unix.B4800,
+	9600:    unix.B9600,
+	19200:   unix.B19200,
+	38400:   unix.B38400,
+	57600:   unix.B57600,
+	115200:  unix.B115200,
+	230400:  unix.B230400,
+	460800:  unix.B460800,
+	921600:  unix.B921600,
+	1000000: unix.B1000000,
+	2000000: unix.B2000000,
+	3000000: unix.B3000000,
+	4000000: unix.B4000000,
+}
+
+// Open opens the serial port in raw mode, the options may be nil.
+func Open(name string, opts *Options) (io.ReadWriteCloser, error) {
+	opts = opts.withDefaults()
+	baudRate, ok := baudRates[opts.BaudRate]
+	if !ok {
+		return nil, fmt.Errorf("unsupported baud rate %d", opts.BaudRate)
+	}
+	sp := new(SerialPort)
+	var err error
+	if opts.Exclusive {
+		if sp.lockFile, err = lockUUCP(opts.LockDirectory, name); err != nil {
+			return nil, err
+		}
+	}
+	if sp.f, err = os.OpenFile(name, os.O_RDWR|unix.O_NOCTTY, 0666); err != nil {
+		sp.unlock()
 		return nil, err
 	}
-	sp := &SerialPort{f: f}
-	if err := sp.setTermios(unix.B115200); err != nil {
-		f.Close()
+	if err := sp.setTermios(baudRate, opts); err != nil {
+		sp.f.Close()
+		sp.unlock()
 		return nil, err
 	}
 	return sp, nil
 }
 
-func (sp *SerialPort) setTermios(baudRate uint32) error {
-	fd := int(sp.f.Fd())
-	var err error
-	if sp.oldTermios, err = unix.IoctlGetTermios(fd, unix.TCGETS); err != nil {
+// control runs f with the file descriptor of the port.
+// Unlike File.Fd, it keeps the port in non-blocking mode, which the read deadlines depend on.
+func (sp *SerialPort) control(f func(fd int) error) error {
+	conn, err := sp.f.SyscallConn()
+	if err != nil {
 		return err
 	}
-	t := unix.Termios{
-		Ispeed: baudRate,
-		Ospeed: baudRate,
+	var controlErr error
+	if err := conn.Control(func(fd uintptr) { controlErr = f(int(fd)) }); err != nil {
+		return err
 	}
-	t.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
-	t.Oflag &^= unix.OPOST
-	t.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
-	t.Cflag &^= unix.CSIZE | unix.PARENB
-	t.Cflag |= unix.CS8
-	t.Cc[unix.VMIN] = 1
-	t.Cc[unix.VTIME] = 0
-	return unix.IoctlSetTermios(fd, unix.TCSETS, &t)
+	return controlErr
+}
+
+func (sp *SerialPort) setTermios(baudRate uint32, opts *Options) error {
+	return sp.control(func(fd int) error {
+		var err error
+		if opts.Exclusive {
+			if err := unix.IoctlSetInt(fd, unix.TIOCEXCL, 0); err != nil {
+				return fmt.Errorf("TIOCEXCL: %w", err)
+			}
+			sp.exclusive = true
+		}
+		if sp.oldTermios, err = unix.IoctlGetTermios(fd, unix.TCGETS); err != nil {
+			return err
+		}
+		t := unix.Termios{
+			Ispeed: baudRate,
+			Ospeed: baudRate,
+		}
+		t.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+		t.Oflag &^= unix.OPOST
+		t.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+		t.Cflag &^= unix.CSIZE | unix.PARENB
+		// The speed is set by the CBAUD bits, TCSETS ignores Ispeed and Ospeed.
+		t.Cflag |= unix.CS8 | unix.CREAD | unix.CLOCAL | baudRate
+		switch opts.FlowControl {
+		case FlowControlHardware:
+			t.Cflag |= unix.CRTSCTS
+		case FlowControlSoftware:
+			t.Iflag |= unix.IXON | unix.IXOFF
+		}
+		t.Cc[unix.VMIN] = 1
+		t.Cc[unix.VTIME] = 0
+		return unix.IoctlSetTermios(fd, unix.TCSETS, &t)
+	})
 }
 
 func (sp *SerialPort) Read(buf []byte) (int, error) {
@@ -53,14 +121,71 @@ func (sp *SerialPort) Read(buf []byte) (int, error) {
 	return n, err
 }
 
+// SetReadDeadline sets the deadline of the pending and future reads, a zero time disables it.
+func (sp *SerialPort) SetReadDeadline(t time.Time) error {
+	return sp.f.SetReadDeadline(t)
+}
+
 func (sp *SerialPort) Write(data []byte) (int, error) {
 	n, err := sp.f.Write(data)
 	return n, err
 }
 
 func (sp *SerialPort) Close() error {
-	if err := unix.IoctlSetTermios(int(sp.f.Fd()), unix.TCSETS, sp.oldTermios); err != nil {
-		return err
+	err := sp.control(func(fd int) error {
+		if sp.exclusive {
+			_ = unix.IoctlSetInt(fd, unix.TIOCNXCL, 0)
+		}
+		return unix.IoctlSetTermios(fd, unix.TCSETS, sp.oldTermios)
+	})
+	if closeErr := sp.f.Close(); err == nil {
+		err = closeErr
+	}
+	sp.unlock()
+	return err
+}
+
+func (sp *SerialPort) unlock() {
+	if sp.lockFile != "" {
+		_ = os.Remove(sp.lockFile)
+		sp.lockFile = ""
+	}
+}
+
+// lockUUCP creates the UUCP lock file of the device, such as /var/lock/LCK..ttyUSB2, holding the PID of the process.
+// The lock file of a process that is no longer running is stale and replaced.
+func lockUUCP(directory, device string) (string, error) {
+	path := filepath.Join(directory, "LCK.."+filepath.Base(device))
+	for range 2 {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = fmt.Fprintf(file, "%10d\n", os.Getpid())
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				_ = os.Remove(path)
+				return "", fmt.Errorf("lock %s: %w", device, err)
+			}
+			return path, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", fmt.Errorf("lock %s: %w", device, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("lock %s: %w", device, err)
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil {
+			return "", fmt.Errorf("%s is locked by %s", device, path)
+		}
+		if err := unix.Kill(pid, 0); err == nil || errors.Is(err, unix.EPERM) {
+			return "", fmt.Errorf("%s is locked by process %d", device, pid)
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("lock %s: %w", device, err)
+		}
 	}
-	return sp.f.Close()
+	return "", fmt.Errorf("%s is locked by %s", device, path)
 }
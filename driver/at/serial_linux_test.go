@@ -0,0 +1,61 @@
+package at
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver/at/attest"
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestOpen(t *testing.T) {
+	modem, err := attest.NewModem(virtual.New(), nil)
+	require.NoError(t, err)
+	defer modem.Close()
+	lockDirectory := t.TempDir()
+	lockFile := filepath.Join(lockDirectory, "LCK.."+filepath.Base(modem.Path()))
+	opts := &Options{BaudRate: 9600, FlowControl: FlowControlHardware, Exclusive: true, LockDirectory: lockDirectory}
+
+	// A lock file of a process that is no longer running is replaced.
+	require.NoError(t, os.WriteFile(lockFile, []byte("1999999999\n"), 0644))
+	port, err := Open(modem.Path(), opts)
+	require.NoError(t, err)
+	data, err := os.ReadFile(lockFile)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%10d\n", os.Getpid()), string(data))
+
+	var termios *unix.Termios
+	require.NoError(t, port.(*SerialPort).control(func(fd int) (err error) {
+		termios, err = unix.IoctlGetTermios(fd, unix.TCGETS)
+		return err
+	}))
+	assert.Equal(t, uint32(unix.B9600), termios.Cflag&unix.CBAUD)
+	assert.NotZero(t, termios.Cflag&unix.CRTSCTS)
+	assert.Zero(t, termios.Lflag&unix.ICANON)
+
+	_, err = Open(modem.Path(), opts)
+	assert.EqualError(t, err, fmt.Sprintf("%s is locked by process %d", modem.Path(), os.Getpid()))
+	require.NoError(t, port.Close())
+	assert.NoFileExists(t, lockFile)
+
+	_, err = Open(modem.Path(), &Options{BaudRate: 12345})
+	assert.EqualError(t, err, "unsupported baud rate 12345")
+}
+
+func TestAT_ReadTimeout(t *testing.T) {
+	modem, err := attest.NewModem(virtual.New(), &attest.Options{Delay: 200 * time.Millisecond})
+	require.NoError(t, err)
+	defer modem.Close()
+
+	channel, err := New(modem.Path(), &Options{ReadTimeout: 20 * time.Millisecond})
+	require.NoError(t, err)
+	err = channel.Connect()
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	assert.ErrorContains(t, err, "no answer from the modem")
+}
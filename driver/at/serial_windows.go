@@ -13,13 +13,17 @@ import (
 )
 
 type SerialPort struct {
-	handle     windows.Handle
-	portName   string
-	readEvent  windows.Handle
-	writeEvent windows.Handle
+	handle      windows.Handle
+	portName    string
+	readEvent   windows.Handle
+	writeEvent  windows.Handle
+	readTimeout uint32
 }
 
-func Open(port string) (io.ReadWriteCloser, error) {
+// Open opens the serial port, the options may be nil.
+// The port is always opened exclusively, the lock file options do not apply on Windows.
+func Open(port string, opts *Options) (io.ReadWriteCloser, error) {
+	opts = opts.withDefaults()
 	comPort := windows.StringToUTF16Ptr("\\\\.\\" + port)
 	handle, err := windows.CreateFile(comPort,
 		windows.GENERIC_READ|windows.GENERIC_WRITE,
@@ -38,13 +42,20 @@ func Open(port string) (io.ReadWriteCloser, error) {
 		windows.CloseHandle(handle)
 		return nil, fmt.Errorf("GetCommState failed: %w", err)
 	}
-	dcb.BaudRate = 115200
+	dcb.BaudRate = uint32(opts.BaudRate)
 	dcb.ByteSize = 8
 	dcb.Parity = windows.NOPARITY
 	dcb.StopBits = windows.ONESTOPBIT
 	dcb.Flags = dcb.Flags | 0x00000001 // fBinary = 1
 	dcb.Flags &^= 0x00000002           // fParity = 0
 	dcb.Flags &^= (1 << 4) | (1 << 5)  // Clear DTR and RTS control bits before setting
+	dcb.Flags &^= (1 << 2) | (1 << 8) | (1 << 9) | (3 << 12)
+	switch opts.FlowControl {
+	case FlowControlHardware:
+		dcb.Flags |= (1 << 2) | (2 << 12) // fOutxCtsFlow = 1, fRtsControl = RTS_CONTROL_HANDSHAKE
+	case FlowControlSoftware:
+		dcb.Flags |= (1 << 8) | (1 << 9) // fOutX = 1, fInX = 1
+	}
 
 	if err := windows.SetCommState(handle, &dcb); err != nil {
 		windows.CloseHandle(handle)
@@ -76,10 +87,11 @@ func Open(port string) (io.ReadWriteCloser, error) {
 	writeEvent, _ := windows.CreateEvent(nil, 1, 0, nil)
 
 	return &SerialPort{
-		handle:     handle,
-		portName:   port,
-		readEvent:  readEvent,
-		writeEvent: writeEvent,
+		handle:      handle,
+		portName:    port,
+		readEvent:   readEvent,
+		writeEvent:  writeEvent,
+		readTimeout: uint32(opts.ReadTimeout.Milliseconds()),
 	}, nil
 }
 
@@ -92,7 +104,7 @@ func (sp *SerialPort) Read(p []byte) (int, error) {
 		return 0, fmt.Errorf("ReadFile failed: %w", err)
 	}
 
-	s, _ := windows.WaitForSingleObject(sp.readEvent, 5000) // increase timeout
+	s, _ := windows.WaitForSingleObject(sp.readEvent, sp.readTimeout)
 	switch s {
 	case uint32(windows.WAIT_OBJECT_0):
 		err = windows.GetOverlappedResult(sp.handle, &overlapped, &bytesRead, false)
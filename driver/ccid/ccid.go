@@ -1,11 +1,15 @@
 package ccid
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"slices"
 
 	"github.com/ElMostafaIdrassi/goscard"
 	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
 )
 
 type CCID interface {
@@ -15,10 +19,58 @@ type CCID interface {
 }
 
 type CCIDReader struct {
-	context goscard.Context
-	card    goscard.Card
-	channel byte
-	reader  string
+	context  goscard.Context
+	card     goscard.Card
+	channel  byte
+	reader   string
+	observer apdu.Observer
+}
+
+func init() {
+	driver.Register("pcsc", func(uri *url.URL) (apdu.SmartCardChannel, error) {
+		reader, err := New()
+		if err != nil {
+			return nil, err
+		}
+		if err := selectReader(reader, uri.Query().Get("reader")); err != nil {
+			_, _ = reader.(*CCIDReader).context.Release()
+			return nil, err
+		}
+		return reader, nil
+	})
+	driver.RegisterEnumerator("pcsc", func() ([]*url.URL, error) {
+		reader, err := New()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.(*CCIDReader).context.Release()
+		readers, err := reader.ListReaders()
+		if err != nil {
+			return nil, err
+		}
+		uris := make([]*url.URL, len(readers))
+		for i, name := range readers {
+			uris[i] = &url.URL{Scheme: "pcsc", RawQuery: url.Values{"reader": {name}}.Encode()}
+		}
+		return uris, nil
+	})
+}
+
+// selectReader sets the named reader, or the first one when the name is empty.
+func selectReader(reader CCID, name string) error {
+	readers, err := reader.ListReaders()
+	if err != nil {
+		return err
+	}
+	switch {
+	case name == "":
+		reader.SetReader(readers[0])
+	case slices.Contains(readers, name):
+		reader.SetReader(name)
+	default:
+		return fmt.Errorf("reader %q not found, available readers: %q", name, readers)
+	}
+	return nil
 }
 
 func New() (CCID, error) {
@@ -54,46 +106,57 @@ func (c *CCIDReader) Connect() error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Transmit([]byte{0x80, 0xAA, 0x00, 0x00, 0x0A, 0xA9, 0x08, 0x81, 0x00, 0x82, 0x01, 0x01, 0x83, 0x01, 0x07})
+	_, err = c.Transmit(context.Background(), []byte{0x80, 0xAA, 0x00, 0x00, 0x0A, 0xA9, 0x08, 0x81, 0x00, 0x82, 0x01, 0x01, 0x83, 0x01, 0x07})
 	return err
 }
 
 func (c *CCIDReader) Disconnect() error {
 	defer goscard.Finalize()
-	if _, err := c.card.Disconnect(goscard.SCardLeaveCard); err != nil {
-		return err
+	_, err := c.card.Disconnect(goscard.SCardLeaveCard)
+	if _, releaseErr := c.context.Release(); err == nil {
+		err = releaseErr
 	}
-	if _, err := c.context.Release(); err != nil {
-		return err
-	}
-	return nil
+	return err
 }
 
-func (c *CCIDReader) Transmit(command []byte) ([]byte, error) {
+func (c *CCIDReader) SetObserver(observer apdu.Observer) {
+	c.observer = observer
+}
+
+// Transmit sends the command to the card.
+// PC/SC cannot abort a pending transmission, so the context is only checked before sending.
+func (c *CCIDReader) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	r, _, err := c.card.Transmit(&goscard.SCardIoRequestT0, command, nil)
+	if c.observer != nil {
+		c.observer(command, r, err)
+	}
 	return r, err
 }
 
 func (c *CCIDReader) OpenLogicalChannel(AID []byte) (byte, error) {
-	channel, err := c.Transmit([]byte{0x00, 0x70, 0x00, 0x00, 0x01})
+	channel, err := c.Transmit(context.Background(), []byte{0x00, 0x70, 0x00, 0x00, 0x01})
 	if err != nil {
 		return 0, err
 	}
 	if channel[len(channel)-2] != 0x90 {
-		return 0, fmt.Errorf("open logical channel: %X", channel)
+		return 0, fmt.Errorf("open logical channel: %w", &apdu.StatusWordError{Command: []byte{0x00, 0x70, 0x00, 0x00, 0x01}, SW: apdu.Response(channel).SW()})
 	}
 	c.channel = channel[0]
-	sw, err := c.Transmit(append([]byte{c.channel, 0xA4, 0x04, 0x00, byte(len(AID))}, AID...))
+	command := append([]byte{c.channel, 0xA4, 0x04, 0x00, byte(len(AID))}, AID...)
+	sw, err := c.Transmit(context.Background(), command)
 	if err != nil {
 		return 0, err
 	}
 	if sw[len(sw)-2] != 0x90 && sw[len(sw)-2] != 0x61 {
-		return 0, fmt.Errorf("select AID: %X", sw)
+		return 0, fmt.Errorf("select AID: %w", &apdu.StatusWordError{Command: command, SW: apdu.Response(sw).SW()})
 	}
 	return c.channel, nil
 }
 
 func (c *CCIDReader) CloseLogicalChannel(channel byte) error {
-	_, err := c.Transmit([]byte{0x00, 0x70, 0x80, channel, 0x00})
+	_, err := c.Transmit(context.Background(), []byte{0x00, 0x70, 0x80, channel, 0x00})
 	return err
 }
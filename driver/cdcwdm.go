@@ -0,0 +1,33 @@
+package driver
+
+import (
+	"net"
+	"os"
+)
+
+// CDCWDMConn is a net.Conn on a cdc-wdm device, such as /dev/cdc-wdm0, each read and write carries one message.
+// The qmi and mbim drivers use it when qmi-proxy and mbim-proxy are not running.
+type CDCWDMConn struct {
+	*os.File
+}
+
+var _ net.Conn = (*CDCWDMConn)(nil)
+
+// OpenCDCWDM opens the device, File.Fd is never called so that the read deadlines keep working.
+func OpenCDCWDM(device string) (*CDCWDMConn, error) {
+	f, err := os.OpenFile(device, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &CDCWDMConn{File: f}, nil
+}
+
+type cdcWDMAddr string
+
+func (a cdcWDMAddr) Network() string { return "cdc-wdm" }
+
+func (a cdcWDMAddr) String() string { return string(a) }
+
+func (c *CDCWDMConn) LocalAddr() net.Addr { return cdcWDMAddr(c.Name()) }
+
+func (c *CDCWDMConn) RemoteAddr() net.Addr { return cdcWDMAddr(c.Name()) }
@@ -0,0 +1,27 @@
+package driver
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// iocWDMMaxCommand is IOCTL_WDM_MAX_COMMAND of the cdc-wdm driver, _IOR('H', 0xA0, __u16) on x86 and ARM.
+// Elsewhere the ioctl fails and MaxCommand returns 0.
+const iocWDMMaxCommand = 0x80024AA0
+
+// MaxCommand returns the largest message the device accepts, as reported by the cdc-wdm driver, 0 when it is unknown.
+func (c *CDCWDMConn) MaxCommand() uint32 {
+	conn, err := c.SyscallConn()
+	if err != nil {
+		return 0
+	}
+	var size uint16
+	var errno unix.Errno
+	if err := conn.Control(func(fd uintptr) {
+		_, _, errno = unix.Syscall(unix.SYS_IOCTL, fd, iocWDMMaxCommand, uintptr(unsafe.Pointer(&size)))
+	}); err != nil || errno != 0 {
+		return 0
+	}
+	return uint32(size)
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package driver
+
+// MaxCommand returns 0, the cdc-wdm devices only exist on Linux.
+func (c *CDCWDMConn) MaxCommand() uint32 {
+	return 0
+}
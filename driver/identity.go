@@ -0,0 +1,16 @@
+package driver
+
+import (
+	"context"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+)
+
+// DeviceIdentity is implemented by the channels of modems reporting the identity of the device the card is in.
+//
+// lpa.Client reads the IMEI from it, and the TAC from the IMEI, when the activation code or the discovery has none.
+type DeviceIdentity interface {
+	apdu.SmartCardChannel
+	// IMEI returns the digits of the IMEI of the modem.
+	IMEI(ctx context.Context) (string, error)
+}
@@ -0,0 +1,82 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrLocked is returned by Lock when another process holds the lock file until the timeout.
+var ErrLocked = errors.New("driver: the channel is locked by another process")
+
+// FileLock is an exclusive advisory lock on a file, held until Unlock or the exit of the process.
+type FileLock struct {
+	file *os.File
+}
+
+// LockPath returns the path of the lock file of the channel described by the URI, keyed by what the channel holds.
+// Processes using the same modem lock the same file, whatever the driver and the slot, as Connect switches the slot of the modem:
+// /tmp/euicc-dev-cdc-wdm0.lock for qmi:///dev/cdc-wdm0?slot=1 and mbim:///dev/cdc-wdm0?slot=2.
+// The PC/SC readers are keyed by their name, /tmp/euicc-pcsc-Identiv-uTrust-3700-F.lock for pcsc://?reader=Identiv+uTrust+3700+F.
+func LockPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("driver: invalid URI %q: %w", uri, err)
+	}
+	if _, err := Slot(u); err != nil {
+		return "", err
+	}
+	key := Resource(u)
+	if filepath.IsAbs(key) {
+		key = filepath.Clean(key)
+	}
+	if reader := u.Query().Get("reader"); u.Scheme == "pcsc" && reader != "" {
+		key += "-" + reader
+	}
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, key)
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool { return r == '-' }), "-")
+	return filepath.Join(os.TempDir(), "euicc-"+name+".lock"), nil
+}
+
+// Lock locks the file, creating it when it does not exist.
+// It waits up to the timeout for another process to unlock the file, and returns ErrLocked after.
+func Lock(path string, timeout time.Duration) (*FileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("driver: open lock file: %w", err)
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("driver: lock %s: %w", path, err)
+		}
+		if locked {
+			return &FileLock{file: file}, nil
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("%w: %s", ErrLocked, path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// Unlock releases the lock. The file is kept, removing it would let another process lock a file nobody else sees.
+func (l *FileLock) Unlock() error {
+	if err := unlock(l.file); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
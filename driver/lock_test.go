@@ -0,0 +1,49 @@
+package driver_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockPath(t *testing.T) {
+	tests := map[string]string{
+		"qmi:///dev/cdc-wdm0?slot=1":           "euicc-dev-cdc-wdm0.lock",
+		"qmi:///dev/cdc-wdm0?slot=2":           "euicc-dev-cdc-wdm0.lock",
+		"qmi:///dev/cdc-wdm0":                  "euicc-dev-cdc-wdm0.lock",
+		"mbim:///dev//cdc-wdm0?slot=2":         "euicc-dev-cdc-wdm0.lock",
+		"at:///dev/ttyUSB2":                    "euicc-dev-ttyUSB2.lock",
+		"qrtr://?slot=2":                       "euicc-qrtr.lock",
+		"pcsc://?reader=Identiv+uTrust+3700+F": "euicc-pcsc-Identiv-uTrust-3700-F.lock",
+		"pcsc://?reader=ACS+ACR39U":            "euicc-pcsc-ACS-ACR39U.lock",
+		"pcsc://":                              "euicc-pcsc.lock",
+	}
+	for uri, expected := range tests {
+		path, err := driver.LockPath(uri)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(os.TempDir(), expected), path, uri)
+	}
+	_, err := driver.LockPath("qmi:///dev/cdc-wdm0?slot=0")
+	assert.Error(t, err)
+}
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "euicc.lock")
+	lock, err := driver.Lock(path, 0)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = driver.Lock(path, 100*time.Millisecond)
+	assert.ErrorIs(t, err, driver.ErrLocked)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	require.NoError(t, lock.Unlock())
+	lock, err = driver.Lock(path, 0)
+	require.NoError(t, err)
+	require.NoError(t, lock.Unlock())
+}
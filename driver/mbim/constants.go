@@ -9,14 +9,13 @@ const (
 	MessageTypeClose          MessageType = 0x00000002
 	MessageTypeCommand        MessageType = 0x00000003
 	MessageTypeHostError      MessageType = 0x00000004
-	MessageTypeFunctionError  MessageType = 0x00000005
 	MessageTypeIndicateStatus MessageType = 0x00000007
 
 	// MBIM Response Message Types
 	MessageTypeOpenDone           MessageType = 0x80000001
 	MessageTypeCloseDone          MessageType = 0x80000002
 	MessageTypeCommandDone        MessageType = 0x80000003
-	MessageTypeFunctionErrorDone  MessageType = 0x80000005
+	MessageTypeFunctionError      MessageType = 0x80000004
 	MessageTypeIndicateStatusDone MessageType = 0x80000007
 )
 
@@ -59,10 +58,24 @@ const (
 const (
 	CIDProxyControlConfiguration = 0x00000001
 	CIDProxyControlVersion       = 0x00000002
+	CIDSysCaps                   = 0x00000005
 	CIDDeviceSlotMappings        = 0x00000007
 	CIDSlotInfoStatus            = 0x00000008
 )
 
+// MBIM UICC Slot States
+const (
+	MBIMUICCSlotStateUnknown              = 0x00000000
+	MBIMUICCSlotStateOffEmpty             = 0x00000001
+	MBIMUICCSlotStateOff                  = 0x00000002
+	MBIMUICCSlotStateEmpty                = 0x00000003
+	MBIMUICCSlotStateNotReady             = 0x00000004
+	MBIMUICCSlotStateActive               = 0x00000005
+	MBIMUICCSlotStateError                = 0x00000006
+	MBIMUICCSlotStateActiveEsim           = 0x00000007
+	MBIMUICCSlotStateActiveEsimNoProfiles = 0x00000008
+)
+
 // MBIM Subscriber Ready States
 const (
 	MBIMSubscriberReadyStateNotInitialized = 0x00000000
@@ -75,6 +88,12 @@ const (
 	MBIMSubscriberReadyStateNoEsimProfile  = 0x00000007
 )
 
+// MBIM Cellular Classes
+const (
+	MBIMCellularClassGSM  = 0x00000001
+	MBIMCellularClassCDMA = 0x00000002
+)
+
 // MBIM Command Types
 const (
 	CommandTypeQuery = 0x00000000
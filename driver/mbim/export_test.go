@@ -0,0 +1,10 @@
+package mbim
+
+// SetProxyAddress makes the driver reach mbim-proxy at the address until restore is called.
+func SetProxyAddress(address string) (restore func()) {
+	previous := proxyAddress
+	proxyAddress = address
+	return func() { proxyAddress = previous }
+}
+
+var ErrProxyUnavailable = errProxyUnavailable
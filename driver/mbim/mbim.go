@@ -1,17 +1,22 @@
 package mbim
 
 import (
+	"cmp"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"net/url"
 	"os"
+	"slices"
 	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
 )
 
 // MBIM implements the apdu.SmartCardChannel interface using MBIM protocol
@@ -21,47 +26,145 @@ type MBIM struct {
 	conn    net.Conn
 	txnID   uint32
 	channel uint32
+	// proxy is set when the device is reached through mbim-proxy, which opens and closes it and fragments the messages.
+	proxy bool
+	// maxControlTransfer is the largest message written to the device, larger commands are fragmented.
+	maxControlTransfer uint32
+	// opened is set once the session is opened, by Connect or by the first slot request.
+	opened bool
+	// restoreSlot and previousSlot map the slot mapped before Connect, 0-based, back on Disconnect.
+	restoreSlot  bool
+	previousSlot *uint8
 }
 
-// New creates a new MBIM proxy connection to the specified device
-func New(device string, slot uint8) (apdu.SmartCardChannel, error) {
+var (
+	_ driver.SlotSwitcher   = (*MBIM)(nil)
+	_ driver.DeviceIdentity = (*MBIM)(nil)
+)
+
+// Mode selects how the device is reached.
+type Mode int
+
+const (
+	// ModeAuto goes through mbim-proxy when it is running, and opens the device directly otherwise.
+	ModeAuto Mode = iota
+	// ModeProxy goes through the mbim-proxy of libmbim, which shares the device with ModemManager and the other libmbim clients.
+	ModeProxy
+	// ModeDirect opens the device, which only needs the cdc_mbim kernel driver.
+	// The device can only be opened by one program at a time.
+	ModeDirect
+)
+
+// Options configure the MBIM channel.
+type Options struct {
+	// Mode defaults to ModeAuto, the mode query of the mbim URIs is auto, proxy or direct.
+	Mode Mode
+	// RestoreSlot maps the slot mapped to the first executor before Connect back on Disconnect.
+	// The UICC commands only reach the card of the first executor, the logical slot of the channel is always 1.
+	RestoreSlot bool
+}
+
+// proxyAddress is the abstract Unix socket of mbim-proxy.
+var proxyAddress = "\x00mbim-proxy"
+
+// errProxyUnavailable is returned when mbim-proxy is not running, ModeAuto opens the device directly then.
+var errProxyUnavailable = errors.New("mbim-proxy is not running")
+
+func init() {
+	driver.Register("mbim", func(uri *url.URL) (apdu.SmartCardChannel, error) {
+		slot, err := driver.Slot(uri)
+		if err != nil {
+			return nil, err
+		}
+		if logicalSlot, err := driver.LogicalSlot(uri); err != nil {
+			return nil, err
+		} else if logicalSlot != 1 {
+			return nil, fmt.Errorf("mbim: invalid logical slot %d, the UICC commands only reach the logical slot 1", logicalSlot)
+		}
+		opts := new(Options)
+		if opts.RestoreSlot, err = driver.RestoreSlot(uri); err != nil {
+			return nil, err
+		}
+		switch mode := uri.Query().Get("mode"); mode {
+		case "", "auto":
+		case "proxy":
+			opts.Mode = ModeProxy
+		case "direct":
+			opts.Mode = ModeDirect
+		default:
+			return nil, fmt.Errorf("mbim: invalid mode %q, expected auto, proxy or direct", mode)
+		}
+		return New(driver.Device(uri, "/dev/cdc-wdm0"), slot, opts)
+	})
+	driver.RegisterEnumerator("mbim", func() ([]*url.URL, error) {
+		return driver.SlotURIs("mbim", driver.Devices("/dev/cdc-wdm*", "cdc_mbim"), func(device string) (apdu.SmartCardChannel, error) {
+			return New(device, 1, nil)
+		}), nil
+	})
+}
+
+// New creates a new MBIM connection to the specified device, the options may be nil.
+func New(device string, slot uint8, opts *Options) (apdu.SmartCardChannel, error) {
 	if slot == 0 {
 		return nil, fmt.Errorf("slot must be >= 1")
 	}
+	if opts == nil {
+		opts = new(Options)
+	}
 	m := &MBIM{
-		device: device,
-		slot:   slot - 1, // Convert to 0-based
+		device:      device,
+		slot:        slot - 1, // Convert to 0-based
+		restoreSlot: opts.RestoreSlot,
+	}
+	var err error
+	switch opts.Mode {
+	case ModeProxy:
+		err = m.connectToProxy()
+	case ModeDirect:
+		err = m.openDevice()
+	default:
+		if err = m.connectToProxy(); errors.Is(err, errProxyUnavailable) {
+			err = m.openDevice()
+		}
 	}
-	if err := m.connectToProxy(); err != nil {
+	if err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
+// openDevice opens the device without mbim-proxy, the session is opened and closed with MBIM_OPEN and MBIM_CLOSE.
+func (m *MBIM) openDevice() error {
+	conn, err := driver.OpenCDCWDM(m.device)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", m.device, err)
+	}
+	m.conn, m.maxControlTransfer = conn, cmp.Or(conn.MaxCommand(), DefaultMaxControlTransfer)
+	return nil
+}
+
 // connectToProxy establishes connection to mbim-proxy using abstract Unix socket
 func (m *MBIM) connectToProxy() error {
 	fd, err := syscall.Socket(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
 	if err != nil {
 		return fmt.Errorf("create socket: %w", err)
 	}
-	if err := syscall.Connect(fd, &syscall.SockaddrUnix{Name: "\x00mbim-proxy"}); err != nil {
+	if err := syscall.Connect(fd, &syscall.SockaddrUnix{Name: proxyAddress}); err != nil {
 		syscall.Close(fd)
-		return fmt.Errorf("connect to mbim-proxy: %w", err)
+		return fmt.Errorf("connect to mbim-proxy: %w: %w", errProxyUnavailable, err)
 	}
 	m.conn, err = net.FileConn(os.NewFile(uintptr(fd), "euicc-go-mbim-proxy"))
 	if err != nil {
 		return fmt.Errorf("create net.Conn: %w", err)
 	}
+	m.proxy = true
 	return nil
 }
 
 // Connect establishes MBIM session and opens device
 func (m *MBIM) Connect() error {
-	if err := m.configureProxy(); err != nil {
-		return fmt.Errorf("configure proxy: %w", err)
-	}
-	if err := m.openDevice(); err != nil {
-		return fmt.Errorf("open device: %w", err)
+	if err := m.open(); err != nil {
+		return err
 	}
 	if err := m.ensureSlotActivated(); err != nil {
 		return fmt.Errorf("ensure slot is activated: %w", err)
@@ -69,69 +172,210 @@ func (m *MBIM) Connect() error {
 	return nil
 }
 
-// ensureSlotActivated checks if the desired slot is activated and activates it if necessary
+// open configures the proxy and opens the session, once.
+func (m *MBIM) open() error {
+	if m.opened {
+		return nil
+	}
+	if m.proxy {
+		if err := m.configureProxy(); err != nil {
+			return fmt.Errorf("configure proxy: %w", err)
+		}
+	}
+	if err := m.openSession(); err != nil {
+		return fmt.Errorf("open device: %w", err)
+	}
+	m.opened = true
+	return nil
+}
+
+// ensureSlotActivated checks if the desired slot is mapped to the first executor and maps it if necessary
 func (m *MBIM) ensureSlotActivated() error {
-	slot, err := m.currentActivatedSlot()
+	mappings, err := m.slotMappings(context.Background())
 	if err != nil {
 		return err
 	}
-	if slot == m.slot {
+	previous := uint8(mappings[0].Slot)
+	if previous == m.slot {
 		return nil
 	}
-	if err := m.activateSlot(m.slot); err != nil {
+	if err := m.activateSlot(context.Background(), mappings, m.slot, 0); err != nil {
+		return err
+	}
+	// The slot is recorded before waiting, so that a card never becoming ready still gets its slot restored.
+	m.previousSlot = &previous
+	if err := m.waitForSlotActivation(context.Background(), m.slot); err != nil {
+		return errors.Join(err, m.restorePreviousSlot())
+	}
+	return nil
+}
+
+// Slots returns the status of the physical slots
+func (m *MBIM) Slots(ctx context.Context) ([]driver.SlotStatus, error) {
+	if err := m.open(); err != nil {
+		return nil, err
+	}
+	caps := SysCapsRequest{TransactionID: atomic.AddUint32(&m.txnID, 1)}
+	if err := m.transmit(ctx, caps.Request()); err != nil {
+		return nil, err
+	}
+	mappings, err := m.slotMappings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]driver.SlotStatus, caps.Response.NumberOfSlots)
+	for i := range slots {
+		request := SlotInfoStatusRequest{
+			TransactionID: atomic.AddUint32(&m.txnID, 1),
+			SlotIndex:     uint32(i),
+		}
+		if err := m.transmit(ctx, request.Request()); err != nil {
+			return nil, err
+		}
+		state := request.Response.State
+		slots[i] = driver.SlotStatus{
+			Slot: uint8(i + 1),
+			Active: state == MBIMUICCSlotStateActive ||
+				state == MBIMUICCSlotStateActiveEsim || state == MBIMUICCSlotStateActiveEsimNoProfiles,
+			CardPresent: state != MBIMUICCSlotStateUnknown &&
+				state != MBIMUICCSlotStateOffEmpty && state != MBIMUICCSlotStateEmpty,
+			EUICC: state == MBIMUICCSlotStateActiveEsim || state == MBIMUICCSlotStateActiveEsimNoProfiles,
+		}
+		for executor, mapping := range mappings {
+			if mapping.Slot == uint32(i) {
+				slots[i].LogicalSlot = uint8(executor + 1)
+			}
+		}
+	}
+	// The subscriber ready status reports the ICCID of the card of the first executor.
+	if slot := mappings[0].Slot; slot < uint32(len(slots)) && slots[slot].Active {
+		request := SubscriberReadyStatusRequest{TransactionID: atomic.AddUint32(&m.txnID, 1)}
+		if err := m.transmit(ctx, request.Request()); err != nil {
+			return nil, err
+		}
+		slots[slot].ICCID = request.Response.ICCID
+	}
+	return slots, nil
+}
+
+// IMEI reads the IMEI of the modem from the device ID of its capabilities
+func (m *MBIM) IMEI(ctx context.Context) (string, error) {
+	if err := m.open(); err != nil {
+		return "", err
+	}
+	request := DeviceCapsRequest{TransactionID: atomic.AddUint32(&m.txnID, 1)}
+	if err := m.transmit(ctx, request.Request()); err != nil {
+		return "", err
+	}
+	if request.Response.CellularClass&MBIMCellularClassGSM == 0 || request.Response.DeviceID == "" {
+		return "", errors.New("the modem has no IMEI")
+	}
+	return request.Response.DeviceID, nil
+}
+
+// SwitchSlot maps the physical slot to the executor of the logical slot and waits for the card to be ready
+func (m *MBIM) SwitchSlot(ctx context.Context, slot, logicalSlot uint8) error {
+	if slot == 0 || logicalSlot == 0 {
+		return fmt.Errorf("invalid slot %d or logical slot %d", slot, logicalSlot)
+	}
+	if err := m.open(); err != nil {
 		return err
 	}
-	return m.waitForSlotActivation()
+	mappings, err := m.slotMappings(ctx)
+	if err != nil {
+		return err
+	}
+	if int(logicalSlot) > len(mappings) {
+		return fmt.Errorf("logical slot %d not found, the modem has %d executors", logicalSlot, len(mappings))
+	}
+	if mappings[logicalSlot-1].Slot == uint32(slot-1) {
+		return nil
+	}
+	return m.switchSlot(ctx, mappings, slot-1, logicalSlot-1)
 }
 
-// currentActivatedSlot queries the current active slot mapping
-func (m *MBIM) currentActivatedSlot() (uint8, error) {
+// slotMappings queries the slots mapped to the executors of the modem, 0-based
+func (m *MBIM) slotMappings(ctx context.Context) ([]SlotMapping, error) {
 	request := DeviceSlotMappingsRequest{
 		TransactionID: atomic.AddUint32(&m.txnID, 1),
 		MapCount:      0, // Query operation
 	}
-	if err := request.Request().Transmit(m.conn); err != nil {
-		return 0, err
+	if err := m.transmit(ctx, request.Request()); err != nil {
+		return nil, err
 	}
 	if len(request.Response.SlotMappings) == 0 {
-		return 0, errors.New("no slot mappings found")
+		return nil, errors.New("no slot mappings found")
+	}
+	return request.Response.SlotMappings, nil
+}
+
+// switchSlot maps the slot to the executor and waits for the card to be ready, both 0-based.
+// The executor mapped to the slot before gets the slot of the executor, as the mappings cannot share a slot.
+func (m *MBIM) switchSlot(ctx context.Context, mappings []SlotMapping, slot, executor uint8) error {
+	if err := m.activateSlot(ctx, mappings, slot, executor); err != nil {
+		return err
 	}
-	return uint8(request.Response.SlotMappings[0].Slot), nil
+	return m.waitForSlotActivation(ctx, slot)
 }
 
-// activateSlot sets the device to use the specified slot
-func (m *MBIM) activateSlot(slot uint8) error {
+// activateSlot sets the mappings of the device with the slot mapped to the executor
+func (m *MBIM) activateSlot(ctx context.Context, mappings []SlotMapping, slot, executor uint8) error {
+	mappings = slices.Clone(mappings)
+	for i := range mappings {
+		if mappings[i].Slot == uint32(slot) {
+			mappings[i].Slot = mappings[executor].Slot
+		}
+	}
+	mappings[executor].Slot = uint32(slot)
 	request := DeviceSlotMappingsRequest{
 		TransactionID: atomic.AddUint32(&m.txnID, 1),
-		MapCount:      1,
-		SlotMappings: []SlotMapping{
-			{Slot: uint32(slot)},
-		},
+		MapCount:      uint32(len(mappings)),
+		SlotMappings:  mappings,
 	}
-	if err := request.Request().Transmit(m.conn); err != nil {
-		return err
-	}
-	return nil
+	return m.transmit(ctx, request.Request())
 }
 
 // waitForSlotActivation waits for the slot to become active by checking subscriber ready status
-func (m *MBIM) waitForSlotActivation() error {
+func (m *MBIM) waitForSlotActivation(ctx context.Context, slot uint8) error {
 	var err error
 	for range 10 {
 		request := SubscriberReadyStatusRequest{
 			TransactionID: atomic.AddUint32(&m.txnID, 1),
 		}
-		err = request.Request().Transmit(m.conn)
+		err = m.transmit(ctx, request.Request())
 		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
 			continue // Ignore errors, retry
 		}
 		readyState := request.Response.ReadyState
 		if readyState == MBIMSubscriberReadyStateInitialized || readyState == MBIMSubscriberReadyStateNoEsimProfile {
 			return nil
 		}
-		time.Sleep(500 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+	return fmt.Errorf("sim did not become available after slot %d activation err: %w", slot+1, err)
+}
+
+// restorePreviousSlot maps the slot mapped before Connect back to the first executor, without waiting for the card.
+func (m *MBIM) restorePreviousSlot() error {
+	if !m.restoreSlot || m.previousSlot == nil {
+		return nil
+	}
+	mappings, err := m.slotMappings(context.Background())
+	if err == nil {
+		err = m.activateSlot(context.Background(), mappings, *m.previousSlot, 0)
 	}
-	return fmt.Errorf("sim did not become available after slot %d activation err: %w", m.slot, err)
+	if err != nil {
+		return fmt.Errorf("restore slot %d: %w", *m.previousSlot+1, err)
+	}
+	m.previousSlot = nil
+	return nil
 }
 
 // configureProxy sends proxy configuration request with device path using the libmbim proxy protocol
@@ -141,19 +385,34 @@ func (m *MBIM) configureProxy() error {
 		DevicePath:    m.device,
 		Timeout:       30,
 	}
-	err := request.Request().Transmit(m.conn)
+	err := m.transmit(context.Background(), request.Request())
 	if err == io.EOF {
 		return fmt.Errorf("device %s is not connected", m.device)
 	}
 	return err
 }
 
-// openDevice sends MBIM Open message to establish connection
-func (m *MBIM) openDevice() error {
+// openSession sends MBIM Open message to establish connection
+func (m *MBIM) openSession() error {
 	request := OpenDeviceRequest{
+		TransactionID:      atomic.AddUint32(&m.txnID, 1),
+		MaxControlTransfer: m.maxControlTransfer,
+	}
+	return m.transmit(context.Background(), request.Request())
+}
+
+// closeSession sends MBIM Close message, so that the device can be opened again
+func (m *MBIM) closeSession() error {
+	request := CloseDeviceRequest{
 		TransactionID: atomic.AddUint32(&m.txnID, 1),
 	}
-	return request.Request().Transmit(m.conn)
+	return m.transmit(context.Background(), request.Request())
+}
+
+// transmit sends the request, split into fragments when it is larger than the device accepts.
+func (m *MBIM) transmit(ctx context.Context, request *Request) error {
+	request.MaxControlTransfer = m.maxControlTransfer
+	return request.Transmit(ctx, m.conn)
 }
 
 // OpenLogicalChannel opens a logical channel for the specified Application ID
@@ -164,7 +423,7 @@ func (m *MBIM) OpenLogicalChannel(AID []byte) (byte, error) {
 		SelectP2Arg:   0,
 		Group:         1,
 	}
-	if err := request.Request().Transmit(m.conn); err != nil {
+	if err := m.transmit(context.Background(), request.Request()); err != nil {
 		return 0, err
 	}
 	m.channel = request.Response.Channel
@@ -172,7 +431,7 @@ func (m *MBIM) OpenLogicalChannel(AID []byte) (byte, error) {
 }
 
 // Transmit implements apdu.SmartCardChannel.
-func (m *MBIM) Transmit(command []byte) ([]byte, error) {
+func (m *MBIM) Transmit(ctx context.Context, command []byte) ([]byte, error) {
 	request := TransmitAPDURequest{
 		TransactionID:   atomic.AddUint32(&m.txnID, 1),
 		Channel:         m.channel,
@@ -180,7 +439,7 @@ func (m *MBIM) Transmit(command []byte) ([]byte, error) {
 		ClassByteType:   0,
 		APDU:            command,
 	}
-	if err := request.Request().Transmit(m.conn); err != nil {
+	if err := m.transmit(ctx, request.Request()); err != nil {
 		return nil, err
 	}
 	sw := make([]byte, 2)
@@ -196,10 +455,19 @@ func (m *MBIM) CloseLogicalChannel(channel byte) error {
 		Channel:       uint32(channel),
 		Group:         1,
 	}
-	return request.Request().Transmit(m.conn)
+	return m.transmit(context.Background(), request.Request())
 }
 
-// Disconnect closes the MBIM connection and releases resources
+// Disconnect restores the previous slot when asked to, closes the MBIM connection and releases resources
 func (m *MBIM) Disconnect() error {
-	return m.conn.Close()
+	err := m.restorePreviousSlot()
+	if !m.proxy && m.opened {
+		if closeErr := m.closeSession(); err == nil && closeErr != nil {
+			err = fmt.Errorf("close device: %w", closeErr)
+		}
+	}
+	if closeErr := m.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
 }
@@ -0,0 +1,178 @@
+package mbim_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"github.com/KilimcininKorOglu/euicc-go/driver/mbim"
+	"github.com/KilimcininKorOglu/euicc-go/driver/mbim/mbimtest"
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer starts a fake mbim-proxy reached by the driver during the test.
+func newServer(t *testing.T, opts *mbimtest.Options) *mbimtest.Server {
+	server, err := mbimtest.NewServer(opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+	t.Cleanup(mbim.SetProxyAddress(server.Address()))
+	return server
+}
+
+func TestMBIM(t *testing.T) {
+	for name, opts := range map[string]*mbimtest.Options{
+		"active slot":     {ActiveSlot: 2},
+		"slot activation": {ActivationDelay: 2},
+		"fragments":       {ActiveSlot: 2, MaxControlTransfer: 64},
+		"indications":     {ActiveSlot: 2, Indications: true},
+		"ready status":    {Errors: map[mbimtest.Command]mbim.MBIMStatus{mbimtest.SubscriberReadyStatus: mbim.MBIMStatusBusy}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			card := virtual.New()
+			opts.Slots = []mbimtest.Slot{{}, {Card: card}}
+			server := newServer(t, opts)
+
+			channel, err := mbim.New("/dev/cdc-wdm0", 2, &mbim.Options{Mode: mbim.ModeProxy})
+			require.NoError(t, err)
+			client, err := lpa.New(&lpa.Options{Channel: channel})
+			if name == "ready status" {
+				assert.ErrorIs(t, err, mbim.MBIMStatusBusy)
+				assert.ErrorContains(t, err, "sim did not become available after slot 2 activation")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint8(2), server.ActiveSlot())
+			eid, err := client.EID(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, card.EID, eid)
+			info, err := client.EUICCInfo2(context.Background())
+			require.NoError(t, err)
+			assert.NotNil(t, info)
+			require.NoError(t, client.Close())
+		})
+	}
+}
+
+func TestMBIM_Error(t *testing.T) {
+	newServer(t, &mbimtest.Options{
+		Slots:  []mbimtest.Slot{{Card: virtual.New()}},
+		Errors: map[mbimtest.Command]mbim.MBIMStatus{mbimtest.UICCAPDU: mbim.MBIMStatusMsInvalidLogicalChannel},
+	})
+
+	channel, err := mbim.New("/dev/cdc-wdm0", 1, nil)
+	require.NoError(t, err)
+	require.NoError(t, channel.Connect())
+	defer channel.Disconnect()
+	_, err = channel.OpenLogicalChannel(virtual.ISDRApplicationAID)
+	require.NoError(t, err)
+	_, err = channel.Transmit(context.Background(), []byte{0x81, 0xE2, 0x91, 0x00, 0x03, 0xBF, 0x3E, 0x00})
+	assert.ErrorIs(t, err, mbim.MBIMStatusMsInvalidLogicalChannel)
+	_, err = channel.OpenLogicalChannel([]byte{0xA0, 0x00})
+	assert.ErrorIs(t, err, mbim.MBIMStatusMsSelectFailed)
+}
+
+func TestMBIM_Slots(t *testing.T) {
+	server := newServer(t, &mbimtest.Options{Slots: []mbimtest.Slot{
+		{Card: virtual.New(), ICCID: "8901410321234567890"},
+		{Card: virtual.New(), EUICC: true},
+		{},
+	}})
+
+	channel, err := mbim.New("/dev/cdc-wdm0", 2, &mbim.Options{Mode: mbim.ModeProxy})
+	require.NoError(t, err)
+	defer channel.Disconnect()
+	switcher := channel.(driver.SlotSwitcher)
+	slots, err := switcher.Slots(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []driver.SlotStatus{
+		{Slot: 1, LogicalSlot: 1, Active: true, CardPresent: true, ICCID: "8901410321234567890"},
+		{Slot: 2, CardPresent: true},
+		{Slot: 3},
+	}, slots)
+
+	require.NoError(t, switcher.SwitchSlot(context.Background(), 2, 1))
+	assert.Equal(t, uint8(2), server.ActiveSlot())
+	slots, err = switcher.Slots(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, driver.SlotStatus{Slot: 2, LogicalSlot: 1, Active: true, CardPresent: true, EUICC: true}, slots[1])
+	assert.ErrorIs(t, switcher.SwitchSlot(context.Background(), 4, 1), mbim.MBIMStatusInvalidParameters)
+	assert.ErrorContains(t, switcher.SwitchSlot(context.Background(), 1, 2), "logical slot 2 not found")
+}
+
+func TestMBIM_Executors(t *testing.T) {
+	server := newServer(t, &mbimtest.Options{
+		Slots:   []mbimtest.Slot{{Card: virtual.New()}, {Card: virtual.New()}},
+		Mapping: []uint8{1, 2},
+	})
+
+	channel, err := mbim.New("/dev/cdc-wdm0", 2, &mbim.Options{Mode: mbim.ModeProxy})
+	require.NoError(t, err)
+	require.NoError(t, channel.Connect())
+	defer channel.Disconnect()
+	// The slots of the executors are swapped, as they cannot share a slot.
+	assert.Equal(t, uint8(2), server.MappedSlot(1))
+	assert.Equal(t, uint8(1), server.MappedSlot(2))
+	require.NoError(t, channel.(driver.SlotSwitcher).SwitchSlot(context.Background(), 2, 2))
+	assert.Equal(t, uint8(1), server.MappedSlot(1))
+	assert.Equal(t, uint8(2), server.MappedSlot(2))
+}
+
+func TestMBIM_RestoreSlot(t *testing.T) {
+	for name, restore := range map[string]bool{"restore": true, "keep": false} {
+		t.Run(name, func(t *testing.T) {
+			server := newServer(t, &mbimtest.Options{Slots: []mbimtest.Slot{{Card: virtual.New()}, {Card: virtual.New()}}})
+
+			channel, err := mbim.New("/dev/cdc-wdm0", 2, &mbim.Options{Mode: mbim.ModeProxy, RestoreSlot: restore})
+			require.NoError(t, err)
+			require.NoError(t, channel.Connect())
+			assert.Equal(t, uint8(2), server.ActiveSlot())
+			require.NoError(t, channel.Disconnect())
+			if restore {
+				assert.Equal(t, uint8(1), server.ActiveSlot())
+			} else {
+				assert.Equal(t, uint8(2), server.ActiveSlot())
+			}
+		})
+	}
+}
+
+func TestMBIM_RestoreSlotNotReady(t *testing.T) {
+	server := newServer(t, &mbimtest.Options{
+		Slots:  []mbimtest.Slot{{Card: virtual.New()}, {Card: virtual.New()}},
+		Errors: map[mbimtest.Command]mbim.MBIMStatus{mbimtest.SubscriberReadyStatus: mbim.MBIMStatusBusy},
+	})
+
+	channel, err := mbim.New("/dev/cdc-wdm0", 2, &mbim.Options{Mode: mbim.ModeProxy, RestoreSlot: true})
+	require.NoError(t, err)
+	defer channel.Disconnect()
+	assert.ErrorContains(t, channel.Connect(), "sim did not become available after slot 2 activation")
+	assert.Equal(t, uint8(1), server.ActiveSlot())
+}
+
+func TestMBIM_IMEI(t *testing.T) {
+	newServer(t, &mbimtest.Options{Slots: []mbimtest.Slot{{Card: virtual.New()}}, IMEI: "490154203237518"})
+
+	channel, err := mbim.New("/dev/cdc-wdm0", 1, &mbim.Options{Mode: mbim.ModeProxy})
+	require.NoError(t, err)
+	defer channel.Disconnect()
+	imei, err := channel.(driver.DeviceIdentity).IMEI(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "490154203237518", imei)
+	require.NoError(t, channel.Connect())
+}
+
+func TestNew_Mode(t *testing.T) {
+	t.Cleanup(mbim.SetProxyAddress(filepath.Join(t.TempDir(), "mbim-proxy")))
+	device := filepath.Join(t.TempDir(), "cdc-wdm0")
+
+	_, err := mbim.New(device, 1, &mbim.Options{Mode: mbim.ModeProxy})
+	assert.ErrorIs(t, err, mbim.ErrProxyUnavailable)
+	// ModeAuto opens the device once mbim-proxy is found not to be running.
+	_, err = mbim.New(device, 1, nil)
+	assert.ErrorContains(t, err, "open "+device)
+	assert.NotErrorIs(t, err, mbim.ErrProxyUnavailable)
+}
@@ -0,0 +1,536 @@
+// Package mbimtest provides a fake mbim-proxy for testing the MBIM driver without a modem.
+//
+// The server listens on a Unix socket and answers the MBIM messages of the driver,
+// the proxy configuration, MBIM_OPEN and MBIM_CLOSE, the device and system capabilities, the slot mappings and status, the subscriber ready status
+// and the logical channels of the UICC low level access service, with the cards of its slots, usually virtual eUICCs:
+//
+//	server, err := mbimtest.NewServer(&mbimtest.Options{Slots: []mbimtest.Slot{{}, {Card: virtual.New()}}})
+//	defer server.Close()
+//
+// The driver reaches the server at server.Address() instead of the abstract socket of mbim-proxy.
+package mbimtest
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"unicode/utf16"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver/mbim"
+)
+
+// Command identifies an MBIM command, used as keys of Options.Errors.
+type Command struct {
+	Service [16]byte
+	CID     uint32
+}
+
+// The commands answered by the server.
+var (
+	ProxyConfiguration    = Command{mbim.ServiceMbimProxyControl, mbim.CIDProxyControlConfiguration}
+	DeviceCaps            = Command{mbim.ServiceBasicConnect, mbim.CIDDeviceCaps}
+	SysCaps               = Command{mbim.ServiceMsBasicConnectExtensions, mbim.CIDSysCaps}
+	DeviceSlotMappings    = Command{mbim.ServiceMsBasicConnectExtensions, mbim.CIDDeviceSlotMappings}
+	SlotInfoStatus        = Command{mbim.ServiceMsBasicConnectExtensions, mbim.CIDSlotInfoStatus}
+	SubscriberReadyStatus = Command{mbim.ServiceBasicConnect, mbim.CIDSubscriberReadyStatus}
+	UICCOpenChannel       = Command{mbim.ServiceMsUiccLowLevelAccess, mbim.CIDUiccOpenChannel}
+	UICCCloseChannel      = Command{mbim.ServiceMsUiccLowLevelAccess, mbim.CIDUiccCloseChannel}
+	UICCAPDU              = Command{mbim.ServiceMsUiccLowLevelAccess, mbim.CIDUiccAPDU}
+)
+
+// Slot is a physical slot of the modem.
+type Slot struct {
+	// Card is the card inserted in the slot, nil for an empty slot.
+	Card apdu.SmartCardChannel
+	// ICCID is reported by the subscriber ready status while the slot is mapped.
+	ICCID string
+	// EUICC reports the slot as an active eSIM in the slot status while it is mapped.
+	EUICC bool
+}
+
+// Options configure the server.
+type Options struct {
+	// Slots are the physical slots of the modem, at least one is required.
+	Slots []Slot
+	// ActiveSlot is the physical slot mapped to the executor of the modem, it defaults to 1.
+	ActiveSlot uint8
+	// Mapping is the physical slot mapped to each executor of the modem, it overrides ActiveSlot.
+	// The UICC commands and the subscriber ready status are answered by the card of the first executor.
+	Mapping []uint8
+	// ActivationDelay is the number of subscriber ready status requests answered with a SIM that is not initialized yet
+	// after a slot switch.
+	ActivationDelay int
+	// Errors makes the commands fail with the MBIM status, keyed by command.
+	Errors map[Command]mbim.MBIMStatus
+	// MaxControlTransfer splits the command-done messages larger than it into fragments, zero writes them whole.
+	MaxControlTransfer uint32
+	// Indications sends an MBIM_INDICATE_STATUS before each response, as a device shared with other clients does.
+	Indications bool
+	// IMEI is the device ID of the device capabilities of the GSM modem, it defaults to 356938035643809.
+	IMEI string
+}
+
+// Server is a fake mbim-proxy serving the cards of its slots on a Unix socket.
+type Server struct {
+	opts      Options
+	listener  net.Listener
+	directory string
+	wg        sync.WaitGroup
+
+	mutex   sync.Mutex
+	conns   map[net.Conn]struct{}
+	closed  bool
+	mapping []uint8
+	pending int
+}
+
+// message is a message of the host, the command fields are only set for MBIM_COMMAND.
+type message struct {
+	messageType   mbim.MessageType
+	transactionID uint32
+	command       Command
+	data          []byte
+}
+
+// NewServer connects the cards of the slots, as a modem powers its SIMs on, and serves them on a new Unix socket.
+func NewServer(opts *Options) (*Server, error) {
+	if opts == nil || len(opts.Slots) == 0 {
+		return nil, errors.New("mbimtest: at least one slot is required")
+	}
+	s := &Server{opts: *opts, conns: make(map[net.Conn]struct{})}
+	s.opts.IMEI = cmp.Or(s.opts.IMEI, "356938035643809")
+	s.mapping = []uint8{max(s.opts.ActiveSlot, 1)}
+	if len(s.opts.Mapping) > 0 {
+		s.mapping = slices.Clone(s.opts.Mapping)
+	}
+	var err error
+	for i, slot := range s.opts.Slots {
+		if slot.Card == nil {
+			continue
+		}
+		if err = slot.Card.Connect(); err != nil {
+			s.disconnect(i)
+			return nil, fmt.Errorf("mbimtest: connect the card of slot %d: %w", i+1, err)
+		}
+	}
+	if s.directory, err = os.MkdirTemp("", "mbimtest"); err != nil {
+		s.disconnect(len(s.opts.Slots))
+		return nil, err
+	}
+	if s.listener, err = net.Listen("unix", filepath.Join(s.directory, "mbim-proxy")); err != nil {
+		s.disconnect(len(s.opts.Slots))
+		_ = os.RemoveAll(s.directory)
+		return nil, err
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Address returns the path of the Unix socket of the server.
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+// ActiveSlot returns the physical slot mapped to the first executor of the modem.
+func (s *Server) ActiveSlot() uint8 {
+	return s.MappedSlot(1)
+}
+
+// MappedSlot returns the physical slot mapped to the executor, starting at 1, or 0 when the modem has no such executor.
+func (s *Server) MappedSlot(executor uint8) uint8 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if executor == 0 || int(executor) > len(s.mapping) {
+		return 0
+	}
+	return s.mapping[executor-1]
+}
+
+// Close stops the server, closes the connections and disconnects the cards.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.mutex.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mutex.Unlock()
+	s.wg.Wait()
+	_ = os.RemoveAll(s.directory)
+	s.disconnect(len(s.opts.Slots))
+	return err
+}
+
+// disconnect disconnects the cards of the first n slots.
+func (s *Server) disconnect(n int) {
+	for _, slot := range s.opts.Slots[:n] {
+		if slot.Card != nil {
+			_ = slot.Card.Disconnect()
+		}
+	}
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mutex.Unlock()
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn answers the messages of a client, the commands other than the proxy configuration
+// are answered with a function error until the client sends MBIM_OPEN.
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.conns, conn)
+		s.mutex.Unlock()
+		_ = conn.Close()
+	}()
+	var opened bool
+	for {
+		request, err := readMessage(conn)
+		if err != nil {
+			return
+		}
+		var response []byte
+		switch {
+		case request.messageType == mbim.MessageTypeOpen:
+			opened = true
+			response = request.done(mbim.MessageTypeOpenDone, mbim.MBIMStatusNone)
+		case request.messageType == mbim.MessageTypeClose:
+			opened = false
+			response = request.done(mbim.MessageTypeCloseDone, mbim.MBIMStatusNone)
+		case request.messageType == mbim.MessageTypeCommand && (opened || request.command == ProxyConfiguration):
+			status, information := s.handle(request)
+			response = request.commandDone(status, information)
+		default:
+			response = request.functionError(mbim.ProtocolErrorNotOpened)
+		}
+		if s.opts.Indications {
+			if _, err := conn.Write(request.indication()); err != nil {
+				return
+			}
+		}
+		for _, fragment := range s.fragments(response) {
+			if _, err := conn.Write(fragment); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readMessage reads a message of the host.
+func readMessage(r io.Reader) (*message, error) {
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	length := binary.LittleEndian.Uint32(header[4:8])
+	if length < 12 {
+		return nil, fmt.Errorf("mbimtest: invalid message length %d", length)
+	}
+	buf := make([]byte, length)
+	copy(buf, header)
+	if _, err := io.ReadFull(r, buf[12:]); err != nil {
+		return nil, err
+	}
+	m := &message{
+		messageType:   mbim.MessageType(binary.LittleEndian.Uint32(buf[0:4])),
+		transactionID: binary.LittleEndian.Uint32(buf[8:12]),
+	}
+	if m.messageType == mbim.MessageTypeCommand {
+		if len(buf) < 48 {
+			return nil, fmt.Errorf("mbimtest: command too short: %d bytes", len(buf))
+		}
+		copy(m.command.Service[:], buf[20:36])
+		m.command.CID = binary.LittleEndian.Uint32(buf[36:40])
+		m.data = buf[48:]
+	}
+	return m, nil
+}
+
+// header returns the header of a message answering the request, followed by the fields.
+func (m *message) header(messageType mbim.MessageType, fields ...any) []byte {
+	body := new(bytes.Buffer)
+	for _, field := range fields {
+		_ = binary.Write(body, binary.LittleEndian, field)
+	}
+	buf := binary.LittleEndian.AppendUint32(nil, uint32(messageType))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(12+body.Len()))
+	buf = binary.LittleEndian.AppendUint32(buf, m.transactionID)
+	return append(buf, body.Bytes()...)
+}
+
+func (m *message) done(messageType mbim.MessageType, status mbim.MBIMStatus) []byte {
+	return m.header(messageType, status)
+}
+
+func (m *message) functionError(code mbim.ProtocolError) []byte {
+	return m.header(mbim.MessageTypeFunctionError, code)
+}
+
+func (m *message) commandDone(status mbim.MBIMStatus, information []byte) []byte {
+	return m.header(mbim.MessageTypeCommandDone,
+		[2]uint32{1, 0}, m.command.Service, m.command.CID, status, uint32(len(information)), information)
+}
+
+// indication returns an MBIM_INDICATE_STATUS of the service of the request, with the transaction ID 0 of the indications.
+func (m *message) indication() []byte {
+	indication := &message{}
+	return indication.header(mbim.MessageTypeIndicateStatusDone,
+		[2]uint32{1, 0}, m.command.Service, m.command.CID, uint32(0))
+}
+
+// fragments splits a command-done message into fragments of at most MaxControlTransfer bytes.
+func (s *Server) fragments(message []byte) [][]byte {
+	size := int(s.opts.MaxControlTransfer)
+	if size == 0 || len(message) <= size || len(message) < 20 {
+		return [][]byte{message}
+	}
+	header, body := message[:20], message[20:]
+	chunk := size - len(header)
+	total := (len(body) + chunk - 1) / chunk
+	fragments := make([][]byte, 0, total)
+	for i := range total {
+		fragment := append(bytes.Clone(header), body[i*chunk:min((i+1)*chunk, len(body))]...)
+		binary.LittleEndian.PutUint32(fragment[4:8], uint32(len(fragment)))
+		binary.LittleEndian.PutUint32(fragment[12:16], uint32(total))
+		binary.LittleEndian.PutUint32(fragment[16:20], uint32(i))
+		fragments = append(fragments, fragment)
+	}
+	return fragments
+}
+
+// handle runs the command and returns the status and the information buffer of its response.
+func (s *Server) handle(m *message) (mbim.MBIMStatus, []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if status, ok := s.opts.Errors[m.command]; ok {
+		return status, nil
+	}
+	switch m.command {
+	case ProxyConfiguration:
+		return mbim.MBIMStatusNone, nil
+	case DeviceCaps:
+		return mbim.MBIMStatusNone, s.deviceCaps()
+	case SysCaps:
+		// NumberOfExecutors, NumberOfSlots, Concurrency and ModemId.
+		information := binary.LittleEndian.AppendUint32(nil, uint32(len(s.mapping)))
+		information = binary.LittleEndian.AppendUint32(information, uint32(len(s.opts.Slots)))
+		information = binary.LittleEndian.AppendUint32(information, 1)
+		return mbim.MBIMStatusNone, binary.LittleEndian.AppendUint64(information, 0)
+	case DeviceSlotMappings:
+		return s.slotMappings(m.data)
+	case SlotInfoStatus:
+		return s.slotInfoStatus(m.data)
+	case SubscriberReadyStatus:
+		return mbim.MBIMStatusNone, s.subscriberReadyStatus()
+	case UICCOpenChannel:
+		return s.openChannel(m.data)
+	case UICCCloseChannel:
+		card := s.opts.Slots[s.mapping[0]-1].Card
+		if len(m.data) < 8 || card == nil {
+			return mbim.MBIMStatusInvalidParameters, nil
+		}
+		if err := card.CloseLogicalChannel(byte(binary.LittleEndian.Uint32(m.data[0:4]))); err != nil {
+			return mbim.MBIMStatusMsInvalidLogicalChannel, nil
+		}
+		return mbim.MBIMStatusNone, binary.LittleEndian.AppendUint32(nil, 0x90)
+	case UICCAPDU:
+		return s.transmit(m.data)
+	}
+	return mbim.MBIMStatusNoDeviceSupport, nil
+}
+
+// deviceCaps returns the capabilities of a GSM modem, with the IMEI as device ID.
+func (s *Server) deviceCaps() []byte {
+	deviceID := new(bytes.Buffer)
+	_ = binary.Write(deviceID, binary.LittleEndian, utf16.Encode([]rune(s.opts.IMEI)))
+	// DeviceType, CellularClass, VoiceClass, SimClass, DataClass, SmsCaps, ControlCaps and MaxSessions,
+	// then the CustomDataClass, DeviceId, FirmwareInfo and HardwareInfo strings, only DeviceId is set.
+	information := binary.LittleEndian.AppendUint32(nil, 1)
+	information = binary.LittleEndian.AppendUint32(information, mbim.MBIMCellularClassGSM)
+	information = append(information, make([]byte, 24+8)...)
+	information = binary.LittleEndian.AppendUint32(information, 64)
+	information = binary.LittleEndian.AppendUint32(information, uint32(deviceID.Len()))
+	information = append(information, make([]byte, 16)...)
+	return append(information, deviceID.Bytes()...)
+}
+
+// slotMappings returns the slots mapped to the executors, after mapping the slots of a set request.
+// A set request maps every executor to a distinct slot.
+func (s *Server) slotMappings(data []byte) (mbim.MBIMStatus, []byte) {
+	if len(data) < 4 {
+		return mbim.MBIMStatusInvalidParameters, nil
+	}
+	if count := binary.LittleEndian.Uint32(data[0:4]); count > 0 {
+		if count != uint32(len(s.mapping)) || len(data) < 4+int(count)*8 {
+			return mbim.MBIMStatusInvalidParameters, nil
+		}
+		mapping := make([]uint8, count)
+		for i := range mapping {
+			offset := binary.LittleEndian.Uint32(data[4+i*8:])
+			if int(offset)+4 > len(data) {
+				return mbim.MBIMStatusInvalidParameters, nil
+			}
+			slot := binary.LittleEndian.Uint32(data[offset : offset+4])
+			if slot >= uint32(len(s.opts.Slots)) || slices.Contains(mapping, uint8(slot+1)) {
+				return mbim.MBIMStatusInvalidParameters, nil
+			}
+			mapping[i] = uint8(slot + 1)
+		}
+		if !slices.Equal(mapping, s.mapping) {
+			s.mapping, s.pending = mapping, s.opts.ActivationDelay
+		}
+	}
+	information := binary.LittleEndian.AppendUint32(nil, uint32(len(s.mapping)))
+	for i := range s.mapping {
+		information = binary.LittleEndian.AppendUint32(information, uint32(4+len(s.mapping)*8+i*4))
+		information = binary.LittleEndian.AppendUint32(information, 4)
+	}
+	for _, slot := range s.mapping {
+		information = binary.LittleEndian.AppendUint32(information, uint32(slot-1))
+	}
+	return mbim.MBIMStatusNone, information
+}
+
+// slotInfoStatus returns the state of the slot of the request, only the mapped slots are powered on.
+func (s *Server) slotInfoStatus(data []byte) (mbim.MBIMStatus, []byte) {
+	if len(data) < 4 {
+		return mbim.MBIMStatusInvalidParameters, nil
+	}
+	index := binary.LittleEndian.Uint32(data[0:4])
+	if index >= uint32(len(s.opts.Slots)) {
+		return mbim.MBIMStatusInvalidParameters, nil
+	}
+	slot, mapped := s.opts.Slots[index], slices.Contains(s.mapping, uint8(index+1))
+	var state uint32
+	switch {
+	case slot.Card == nil && mapped:
+		state = mbim.MBIMUICCSlotStateEmpty
+	case slot.Card == nil:
+		state = mbim.MBIMUICCSlotStateOffEmpty
+	case !mapped:
+		state = mbim.MBIMUICCSlotStateOff
+	case slot.EUICC:
+		state = mbim.MBIMUICCSlotStateActiveEsim
+	default:
+		state = mbim.MBIMUICCSlotStateActive
+	}
+	return mbim.MBIMStatusNone, binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint32(nil, index), state)
+}
+
+// subscriberReadyStatus returns the ready state and the ICCID of the mapped slot,
+// the SIM is not initialized while a switch is pending.
+func (s *Server) subscriberReadyStatus() []byte {
+	slot := s.opts.Slots[s.mapping[0]-1]
+	var state uint32 = mbim.MBIMSubscriberReadyStateInitialized
+	switch {
+	case slot.Card == nil:
+		state = mbim.MBIMSubscriberReadyStateSimNotInserted
+	case s.pending > 0:
+		s.pending--
+		state = mbim.MBIMSubscriberReadyStateNotInitialized
+	}
+	iccid := new(bytes.Buffer)
+	_ = binary.Write(iccid, binary.LittleEndian, utf16.Encode([]rune(slot.ICCID)))
+	var iccidOffset uint32
+	if iccid.Len() > 0 {
+		iccidOffset = 28
+	}
+	// ReadyState, SubscriberId, SimIccId, ReadyInfo and ElementCount, followed by the ICCID.
+	information := binary.LittleEndian.AppendUint32(nil, state)
+	information = binary.LittleEndian.AppendUint32(information, 0)
+	information = binary.LittleEndian.AppendUint32(information, 0)
+	information = binary.LittleEndian.AppendUint32(information, iccidOffset)
+	information = binary.LittleEndian.AppendUint32(information, uint32(iccid.Len()))
+	information = binary.LittleEndian.AppendUint32(information, 0)
+	information = binary.LittleEndian.AppendUint32(information, 0)
+	return append(information, iccid.Bytes()...)
+}
+
+// card returns the card of the slot mapped to the first executor, the SIM is not initialized while a switch is pending.
+func (s *Server) card() (apdu.SmartCardChannel, mbim.MBIMStatus) {
+	card := s.opts.Slots[s.mapping[0]-1].Card
+	switch {
+	case card == nil:
+		return nil, mbim.MBIMStatusSimNotInserted
+	case s.pending > 0:
+		return nil, mbim.MBIMStatusNotInitialized
+	}
+	return card, mbim.MBIMStatusNone
+}
+
+func (s *Server) openChannel(data []byte) (mbim.MBIMStatus, []byte) {
+	card, status := s.card()
+	if status != mbim.MBIMStatusNone {
+		return status, nil
+	}
+	if len(data) < 16 {
+		return mbim.MBIMStatusInvalidParameters, nil
+	}
+	size, offset := binary.LittleEndian.Uint32(data[0:4]), binary.LittleEndian.Uint32(data[4:8])
+	if int(offset+size) > len(data) {
+		return mbim.MBIMStatusInvalidParameters, nil
+	}
+	channel, err := card.OpenLogicalChannel(data[offset : offset+size])
+	if err != nil {
+		return mbim.MBIMStatusMsSelectFailed, nil
+	}
+	// Status, Channel and Response, which is empty.
+	information := binary.LittleEndian.AppendUint32(nil, 0x90)
+	information = binary.LittleEndian.AppendUint32(information, uint32(channel))
+	information = binary.LittleEndian.AppendUint32(information, 0)
+	return mbim.MBIMStatusNone, binary.LittleEndian.AppendUint32(information, 16)
+}
+
+// transmit sends the APDU to the card, the status word is returned in the status field with SW1 in its lowest byte.
+func (s *Server) transmit(data []byte) (mbim.MBIMStatus, []byte) {
+	card, status := s.card()
+	if status != mbim.MBIMStatusNone {
+		return status, nil
+	}
+	if len(data) < 20 {
+		return mbim.MBIMStatusInvalidParameters, nil
+	}
+	size, offset := binary.LittleEndian.Uint32(data[12:16]), binary.LittleEndian.Uint32(data[16:20])
+	if int(offset+size) > len(data) {
+		return mbim.MBIMStatusInvalidParameters, nil
+	}
+	response, err := card.Transmit(context.Background(), data[offset:offset+size])
+	var statusErr *apdu.StatusWordError
+	if err != nil && (!errors.As(err, &statusErr) || len(response) < 2) {
+		return mbim.MBIMStatusFailure, nil
+	}
+	sw := response[len(response)-2:]
+	response = response[:len(response)-2]
+	information := binary.LittleEndian.AppendUint32(nil, uint32(sw[0])|uint32(sw[1])<<8)
+	information = binary.LittleEndian.AppendUint32(information, uint32(len(response)))
+	information = binary.LittleEndian.AppendUint32(information, 12)
+	return mbim.MBIMStatusNone, append(information, response...)
+}
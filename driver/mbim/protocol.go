@@ -2,8 +2,10 @@ package mbim
 
 import (
 	"bytes"
+	"cmp"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"time"
 	"unicode/utf16"
 )
@@ -57,7 +59,9 @@ func (r *ProxyConfigResponse) UnmarshalBinary(data []byte) error { return nil }
 
 type OpenDeviceRequest struct {
 	TransactionID uint32
-	Response      *OpenDeviceResponse
+	// MaxControlTransfer is the largest message the host sends or receives, it defaults to DefaultMaxControlTransfer.
+	MaxControlTransfer uint32
+	Response           *OpenDeviceResponse
 }
 
 func (r *OpenDeviceRequest) Request() *Request {
@@ -72,7 +76,7 @@ func (r *OpenDeviceRequest) Request() *Request {
 
 func (r *OpenDeviceRequest) MarshalBinary() ([]byte, error) {
 	buf := make([]byte, 4)
-	binary.LittleEndian.PutUint32(buf, 4096)
+	binary.LittleEndian.PutUint32(buf, cmp.Or(r.MaxControlTransfer, DefaultMaxControlTransfer))
 	return buf, nil
 }
 
@@ -82,6 +86,31 @@ func (p *OpenDeviceResponse) UnmarshalBinary(data []byte) error { return nil }
 
 // endregion
 
+// region Close Device Request
+
+type CloseDeviceRequest struct {
+	TransactionID uint32
+	Response      *CloseDeviceResponse
+}
+
+func (r *CloseDeviceRequest) Request() *Request {
+	r.Response = new(CloseDeviceResponse)
+	return &Request{
+		MessageType:   MessageTypeClose,
+		TransactionID: r.TransactionID,
+		Command:       r,
+		Response:      r.Response,
+	}
+}
+
+func (r *CloseDeviceRequest) MarshalBinary() ([]byte, error) { return nil, nil }
+
+type CloseDeviceResponse struct{}
+
+func (p *CloseDeviceResponse) UnmarshalBinary(data []byte) error { return nil }
+
+// endregion
+
 // region Device Slot Mappings
 
 type DeviceSlotMappingsRequest struct {
@@ -161,6 +190,52 @@ func (r *DeviceSlotMappingsResponse) UnmarshalBinary(data []byte) error {
 
 // endregion
 
+// region Device Caps
+
+type DeviceCapsRequest struct {
+	TransactionID uint32
+	Response      *DeviceCapsResponse
+}
+
+func (r *DeviceCapsRequest) Request() *Request {
+	r.Response = new(DeviceCapsResponse)
+	return &Request{
+		MessageType:   MessageTypeCommand,
+		TransactionID: r.TransactionID,
+		Command: &Command{
+			FragmentTotal:   1,
+			FragmentCurrent: 0,
+			ServiceID:       ServiceBasicConnect,
+			CommandID:       CIDDeviceCaps,
+			CommandType:     CommandTypeQuery,
+			Data:            []byte{},
+		},
+		Response: r.Response,
+	}
+}
+
+// DeviceCapsResponse holds the device ID of the capabilities, the IMEI of the GSM modems and the ESN or MEID of the CDMA ones.
+type DeviceCapsResponse struct {
+	CellularClass uint32
+	DeviceID      string
+}
+
+func (r *DeviceCapsResponse) UnmarshalBinary(data []byte) error {
+	if len(data) < 48 {
+		return errors.New("device caps response data too short")
+	}
+	r.CellularClass = binary.LittleEndian.Uint32(data[4:8])
+	var err error
+	// The DeviceId string follows DeviceType, CellularClass, VoiceClass, SimClass, DataClass, SmsCaps,
+	// ControlCaps, MaxSessions and the CustomDataClass string.
+	if r.DeviceID, err = readString(data, 40); err != nil {
+		return fmt.Errorf("device caps response device ID: %w", err)
+	}
+	return nil
+}
+
+// endregion
+
 // region Subscriber Ready Status
 
 type SubscriberReadyStatusRequest struct {
@@ -188,6 +263,7 @@ func (r *SubscriberReadyStatusRequest) Request() *Request {
 
 type SubscriberReadyStatusResponse struct {
 	ReadyState uint32
+	ICCID      string
 }
 
 func (r *SubscriberReadyStatusResponse) UnmarshalBinary(data []byte) error {
@@ -195,6 +271,108 @@ func (r *SubscriberReadyStatusResponse) UnmarshalBinary(data []byte) error {
 		return errors.New("subscriber ready status response data too short")
 	}
 	r.ReadyState = binary.LittleEndian.Uint32(data[0:4])
+	// The SimIccId string follows the SubscriberId one, it is empty when no SIM is initialized.
+	if len(data) >= 20 {
+		var err error
+		if r.ICCID, err = readString(data, 12); err != nil {
+			return fmt.Errorf("subscriber ready status response ICCID: %w", err)
+		}
+	}
+	return nil
+}
+
+// readString reads the UTF-16 string of the offset and size pair at the position of the information buffer.
+func readString(data []byte, position int) (string, error) {
+	if len(data) < position+8 {
+		return "", errors.New("data too short")
+	}
+	offset, size := binary.LittleEndian.Uint32(data[position:]), binary.LittleEndian.Uint32(data[position+4:])
+	if uint64(offset)+uint64(size) > uint64(len(data)) {
+		return "", errors.New("string out of bounds")
+	}
+	s := make([]uint16, size/2)
+	binary.Read(bytes.NewReader(data[offset:offset+size]), binary.LittleEndian, s)
+	return string(utf16.Decode(s)), nil
+}
+
+// endregion
+
+// region System Capabilities
+
+type SysCapsRequest struct {
+	TransactionID uint32
+	Response      *SysCapsResponse
+}
+
+func (r *SysCapsRequest) Request() *Request {
+	r.Response = new(SysCapsResponse)
+	return &Request{
+		MessageType:   MessageTypeCommand,
+		TransactionID: r.TransactionID,
+		Command: &Command{
+			FragmentTotal:   1,
+			FragmentCurrent: 0,
+			ServiceID:       ServiceMsBasicConnectExtensions,
+			CommandID:       CIDSysCaps,
+			CommandType:     CommandTypeQuery,
+			Data:            []byte{},
+		},
+		Response: r.Response,
+	}
+}
+
+type SysCapsResponse struct {
+	NumberOfExecutors uint32
+	NumberOfSlots     uint32
+}
+
+func (r *SysCapsResponse) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("system capabilities response data too short")
+	}
+	r.NumberOfExecutors = binary.LittleEndian.Uint32(data[0:4])
+	r.NumberOfSlots = binary.LittleEndian.Uint32(data[4:8])
+	return nil
+}
+
+// endregion
+
+// region Slot Info Status
+
+type SlotInfoStatusRequest struct {
+	TransactionID uint32
+	SlotIndex     uint32
+	Response      *SlotInfoStatusResponse
+}
+
+func (r *SlotInfoStatusRequest) Request() *Request {
+	r.Response = new(SlotInfoStatusResponse)
+	return &Request{
+		MessageType:   MessageTypeCommand,
+		TransactionID: r.TransactionID,
+		Command: &Command{
+			FragmentTotal:   1,
+			FragmentCurrent: 0,
+			ServiceID:       ServiceMsBasicConnectExtensions,
+			CommandID:       CIDSlotInfoStatus,
+			CommandType:     CommandTypeQuery,
+			Data:            binary.LittleEndian.AppendUint32(nil, r.SlotIndex),
+		},
+		Response: r.Response,
+	}
+}
+
+type SlotInfoStatusResponse struct {
+	SlotIndex uint32
+	State     uint32
+}
+
+func (r *SlotInfoStatusResponse) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("slot info status response data too short")
+	}
+	r.SlotIndex = binary.LittleEndian.Uint32(data[0:4])
+	r.State = binary.LittleEndian.Uint32(data[4:8])
 	return nil
 }
 
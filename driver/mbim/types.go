@@ -2,6 +2,8 @@ package mbim
 
 import (
 	"bytes"
+	"cmp"
+	"context"
 	"encoding"
 	"encoding/binary"
 	"fmt"
@@ -10,14 +12,22 @@ import (
 	"time"
 )
 
+// DefaultMaxControlTransfer is the largest message exchanged with a device that does not report its own.
+const DefaultMaxControlTransfer = 4096
+
+// fragmentTimeout bounds the wait for each following fragment of a fragmented message.
+const fragmentTimeout = 5 * time.Second
+
 // Request represents a standard MBIM request
 type Request struct {
 	MessageType   MessageType
 	MessageLength uint32
 	TransactionID uint32
 	ReadTimeout   time.Duration
-	Command       encoding.BinaryMarshaler
-	Response      encoding.BinaryUnmarshaler
+	// MaxControlTransfer splits the command messages larger than it into fragments, zero writes them whole.
+	MaxControlTransfer uint32
+	Command            encoding.BinaryMarshaler
+	Response           encoding.BinaryUnmarshaler
 }
 
 func (r *Request) WriteTo(w net.Conn) (int, error) {
@@ -25,19 +35,47 @@ func (r *Request) WriteTo(w net.Conn) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	n, err := w.Write(data)
-	if err != nil {
-		return n, err
+	var n int
+	for _, fragment := range r.fragments(data) {
+		written, err := w.Write(fragment)
+		n += written
+		if err != nil {
+			return n, err
+		}
 	}
 	return n, nil
 }
 
-func (r *Request) ReadFrom(c net.Conn) (int, error) {
+// fragments splits a command message into the fragments written one at a time.
+// Each fragment repeats the header and the fragment header, followed by the next part of the message.
+func (r *Request) fragments(data []byte) [][]byte {
+	size := int(r.MaxControlTransfer)
+	if r.MessageType != MessageTypeCommand || size == 0 || len(data) <= size {
+		return [][]byte{data}
+	}
+	header, body := data[:20], data[20:]
+	chunk := size - len(header)
+	total := (len(body) + chunk - 1) / chunk
+	fragments := make([][]byte, 0, total)
+	for i := range total {
+		fragment := append(bytes.Clone(header), body[i*chunk:min((i+1)*chunk, len(body))]...)
+		binary.LittleEndian.PutUint32(fragment[4:8], uint32(len(fragment)))
+		binary.LittleEndian.PutUint32(fragment[12:16], uint32(total))
+		binary.LittleEndian.PutUint32(fragment[16:20], uint32(i))
+		fragments = append(fragments, fragment)
+	}
+	return fragments
+}
+
+func (r *Request) ReadFrom(ctx context.Context, c net.Conn) (int, error) {
 	if r.ReadTimeout == 0 {
 		r.ReadTimeout = 30 * time.Second
 	}
 	deadline := time.Now().Add(r.ReadTimeout)
 	for time.Now().Before(deadline) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		c.SetReadDeadline(time.Now().Add(1 * time.Second))
 
 		header := make([]byte, 12)
@@ -47,19 +85,26 @@ func (r *Request) ReadFrom(c net.Conn) (int, error) {
 			}
 			return 0, err
 		}
-
-		length := binary.LittleEndian.Uint32(header[4:8])
-		buf := make([]byte, length)
-		copy(buf[:12], header)
-		if _, err := io.ReadFull(c, buf[12:]); err != nil {
+		buf, err := readBody(c, header, cmp.Or(r.MaxControlTransfer, maxProxyMessage))
+		if err != nil {
 			return 0, err
 		}
 
-		messageType := binary.LittleEndian.Uint32(header[0:4])
+		messageType := MessageType(binary.LittleEndian.Uint32(header[0:4]))
 		transactionID := binary.LittleEndian.Uint32(header[8:12])
-		if messageType&^0x80000000 != uint32(r.MessageType) || transactionID != r.TransactionID {
+		if transactionID != r.TransactionID {
+			continue
+		}
+		// The device answers a message it cannot handle, such as a command sent before MBIM_OPEN, with a function error.
+		if messageType == MessageTypeFunctionError && len(buf) >= 16 {
+			return 0, fmt.Errorf("function error: %w", ProtocolError(binary.LittleEndian.Uint32(buf[12:16])))
+		}
+		if messageType&^0x80000000 != r.MessageType {
 			continue
 		}
+		if buf, err = r.reassemble(c, buf); err != nil {
+			return 0, err
+		}
 
 		response := CommandResponse{Response: r.Response}
 		if err := response.UnmarshalBinary(buf); err != nil {
@@ -70,12 +115,78 @@ func (r *Request) ReadFrom(c net.Conn) (int, error) {
 	return 0, fmt.Errorf("transaction ID %d not found in response", r.TransactionID)
 }
 
-// Transmit sends the MBIM message and waits for a response
-func (r *Request) Transmit(conn net.Conn) error {
+// maxProxyMessage bounds the messages read without MaxControlTransfer, mbim-proxy forwards them reassembled.
+const maxProxyMessage = 1 << 16
+
+// readBody reads the rest of the message of the header, which is at most limit bytes long.
+func readBody(c net.Conn, header []byte, limit uint32) ([]byte, error) {
+	length := binary.LittleEndian.Uint32(header[4:8])
+	if length < 12 || length > limit {
+		return nil, fmt.Errorf("invalid message length %d", length)
+	}
+	buf := make([]byte, length)
+	copy(buf[:12], header)
+	if _, err := io.ReadFull(c, buf[12:]); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// reassemble reads the following fragments of a command-done message and appends them to its first fragment.
+// A missing or out of sequence fragment is reported to the device with a host error, as the specification requires.
+func (r *Request) reassemble(c net.Conn, message []byte) ([]byte, error) {
+	if MessageType(binary.LittleEndian.Uint32(message[0:4])) != MessageTypeCommandDone || len(message) < 20 {
+		return message, nil
+	}
+	total := binary.LittleEndian.Uint32(message[12:16])
+	if binary.LittleEndian.Uint32(message[16:20]) != 0 {
+		return nil, r.hostError(c, ProtocolErrorFragmentOutOfSequence)
+	}
+	for current := uint32(1); current < total; current++ {
+		c.SetReadDeadline(time.Now().Add(fragmentTimeout))
+		header := make([]byte, 12)
+		if _, err := io.ReadFull(c, header); err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				return nil, r.hostError(c, ProtocolErrorTimeoutFragment)
+			}
+			return nil, err
+		}
+		fragment, err := readBody(c, header, cmp.Or(r.MaxControlTransfer, maxProxyMessage))
+		if err != nil {
+			return nil, err
+		}
+		if len(fragment) < 20 ||
+			!bytes.Equal(fragment[0:4], message[0:4]) ||
+			!bytes.Equal(fragment[8:16], message[8:16]) ||
+			binary.LittleEndian.Uint32(fragment[16:20]) != current {
+			return nil, r.hostError(c, ProtocolErrorFragmentOutOfSequence)
+		}
+		message = append(message, fragment[20:]...)
+	}
+	binary.LittleEndian.PutUint32(message[4:8], uint32(len(message)))
+	binary.LittleEndian.PutUint32(message[12:16], 1)
+	return message, nil
+}
+
+// hostError sends an MBIM_HOST_ERROR for the transaction and returns the error.
+func (r *Request) hostError(c net.Conn, code ProtocolError) error {
+	message := make([]byte, 16)
+	binary.LittleEndian.PutUint32(message[0:4], uint32(MessageTypeHostError))
+	binary.LittleEndian.PutUint32(message[4:8], uint32(len(message)))
+	binary.LittleEndian.PutUint32(message[8:12], r.TransactionID)
+	binary.LittleEndian.PutUint32(message[12:16], uint32(code))
+	if _, err := c.Write(message); err != nil {
+		return fmt.Errorf("host error: %w: %w", code, err)
+	}
+	return fmt.Errorf("host error: %w", code)
+}
+
+// Transmit sends the MBIM message and waits for a response until the read timeout or the end of the context
+func (r *Request) Transmit(ctx context.Context, conn net.Conn) error {
 	if _, err := r.WriteTo(conn); err != nil {
 		return err
 	}
-	if _, err := r.ReadFrom(conn); err != nil {
+	if _, err := r.ReadFrom(ctx, conn); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,123 @@
+package mbim
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readMessage reads a message written by the host.
+func readMessage(t *testing.T, conn net.Conn) []byte {
+	header := make([]byte, 12)
+	_, err := io.ReadFull(conn, header)
+	require.NoError(t, err)
+	message, err := readBody(conn, header, maxProxyMessage)
+	require.NoError(t, err)
+	return message
+}
+
+// commandDone returns the fragments of a command-done message answering the command, each at most size bytes.
+func commandDone(command []byte, information []byte, size int) [][]byte {
+	message := new(bytes.Buffer)
+	binary.Write(message, binary.LittleEndian, MessageTypeCommandDone)
+	binary.Write(message, binary.LittleEndian, uint32(48+len(information)))
+	message.Write(command[8:12])
+	binary.Write(message, binary.LittleEndian, [2]uint32{1, 0})
+	message.Write(command[20:40])
+	binary.Write(message, binary.LittleEndian, [2]uint32{uint32(MBIMStatusNone), uint32(len(information))})
+	message.Write(information)
+	request := Request{MessageType: MessageTypeCommand, MaxControlTransfer: uint32(size)}
+	return request.fragments(message.Bytes())
+}
+
+func TestRequest_Fragments(t *testing.T) {
+	host, device := net.Pipe()
+	defer host.Close()
+	apdu := bytes.Repeat([]byte{0xAB}, 150)
+	response := bytes.Repeat([]byte{0xCD}, 200)
+	go func() {
+		// The 218 bytes of the command are split in 5 fragments of at most 64 bytes, each with a header of 20 bytes.
+		var command []byte
+		for i := range 5 {
+			fragment := readMessage(t, device)
+			assert.LessOrEqual(t, len(fragment), 64)
+			assert.Equal(t, uint32(5), binary.LittleEndian.Uint32(fragment[12:16]))
+			assert.Equal(t, uint32(i), binary.LittleEndian.Uint32(fragment[16:20]))
+			if command == nil {
+				command = fragment
+			} else {
+				command = append(command, fragment[20:]...)
+			}
+		}
+		assert.Equal(t, apdu, command[68:])
+		information := binary.LittleEndian.AppendUint32(nil, 0x9000)
+		information = binary.LittleEndian.AppendUint32(information, uint32(len(response)))
+		information = binary.LittleEndian.AppendUint32(information, 12)
+		for _, fragment := range commandDone(command, append(information, response...), 64) {
+			device.Write(fragment)
+		}
+	}()
+
+	request := TransmitAPDURequest{TransactionID: 7, APDU: apdu}
+	r := request.Request()
+	r.MaxControlTransfer = 64
+	require.NoError(t, r.Transmit(context.Background(), host))
+	assert.Equal(t, uint32(0x9000), request.Response.Status)
+	assert.Equal(t, response, request.Response.Response)
+}
+
+func TestRequest_FunctionError(t *testing.T) {
+	host, device := net.Pipe()
+	defer host.Close()
+	go func() {
+		readMessage(t, device)
+		message := binary.LittleEndian.AppendUint32(nil, uint32(MessageTypeFunctionError))
+		message = binary.LittleEndian.AppendUint32(message, 16)
+		message = binary.LittleEndian.AppendUint32(message, 3)
+		message = binary.LittleEndian.AppendUint32(message, uint32(ProtocolErrorNotOpened))
+		device.Write(message)
+	}()
+
+	request := SubscriberReadyStatusRequest{TransactionID: 3}
+	err := request.Request().Transmit(context.Background(), host)
+	assert.ErrorIs(t, err, ProtocolErrorNotOpened)
+	assert.EqualError(t, err, "function error: Not Opened")
+}
+
+func TestRequest_FragmentOutOfSequence(t *testing.T) {
+	host, device := net.Pipe()
+	defer host.Close()
+	hostError := make(chan []byte, 1)
+	go func() {
+		command := readMessage(t, device)
+		fragments := commandDone(command, bytes.Repeat([]byte{0xCD}, 100), 64)
+		device.Write(fragments[0])
+		device.Write(fragments[2])
+		hostError <- readMessage(t, device)
+	}()
+
+	request := TransmitAPDURequest{TransactionID: 5}
+	err := request.Request().Transmit(context.Background(), host)
+	assert.ErrorIs(t, err, ProtocolErrorFragmentOutOfSequence)
+	message := <-hostError
+	assert.Equal(t, uint32(MessageTypeHostError), binary.LittleEndian.Uint32(message[0:4]))
+	assert.Equal(t, uint32(5), binary.LittleEndian.Uint32(message[8:12]))
+	assert.Equal(t, uint32(ProtocolErrorFragmentOutOfSequence), binary.LittleEndian.Uint32(message[12:16]))
+}
+
+func TestReadBody_TooLong(t *testing.T) {
+	host, device := net.Pipe()
+	defer host.Close()
+	defer device.Close()
+	header := make([]byte, 12)
+	binary.LittleEndian.PutUint32(header[0:4], uint32(MessageTypeCommandDone))
+	binary.LittleEndian.PutUint32(header[4:8], DefaultMaxControlTransfer+1)
+	_, err := readBody(host, header, DefaultMaxControlTransfer)
+	assert.EqualError(t, err, "invalid message length 4097")
+}
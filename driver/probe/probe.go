@@ -0,0 +1,224 @@
+// Package probe discovers the eUICCs reachable on this host.
+//
+// It asks every registered driver for its candidate channels, such as every slot of every /dev/cdc-wdm* modem,
+// the /dev/ttyUSB* and /dev/ttyACM* serial ports, the QRTR slots and the PC/SC readers,
+// selects the ISD-R on each of them and reads the EID.
+// The drivers to try must be imported, for example:
+//
+//	import (
+//		_ "github.com/KilimcininKorOglu/euicc-go/driver/at"
+//		_ "github.com/KilimcininKorOglu/euicc-go/driver/ccid"
+//		_ "github.com/KilimcininKorOglu/euicc-go/driver/mbim"
+//		_ "github.com/KilimcininKorOglu/euicc-go/driver/qmi"
+//		"github.com/KilimcininKorOglu/euicc-go/driver/probe"
+//	)
+//
+//	euiccs, err := probe.Discover(ctx, nil)
+package probe
+
+import (
+	"cmp"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+)
+
+// EUICC is an eUICC found by Discover.
+type EUICC struct {
+	// Driver is the scheme of the driver reaching the eUICC, such as qmi or pcsc.
+	Driver string
+	// Device is the device path of the modem, or the name of the PC/SC reader.
+	// It is empty for QRTR.
+	Device string
+	// Slot is the SIM slot of the modem holding the eUICC, 0 for the drivers without slots.
+	Slot uint8
+	// EID is the eUICC identifier in hex.
+	EID string
+	// URI opens the channel of the eUICC with driver.Open.
+	URI string
+}
+
+// Options configure Discover.
+type Options struct {
+	// Drivers are the schemes of the drivers to try, all the registered drivers when empty.
+	Drivers []string
+	// Timeout bounds the time spent on each candidate, 10 seconds when zero.
+	Timeout time.Duration
+	// Logger logs the candidates that failed at debug level, slog.Default() when nil.
+	Logger *slog.Logger
+}
+
+func (opts *Options) setDefaults() {
+	if len(opts.Drivers) == 0 {
+		opts.Drivers = driver.Schemes()
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+}
+
+// errTimeout is the cause of the context of a candidate which took longer than the timeout.
+var errTimeout = errors.New("probe: candidate timed out")
+
+// Discover tries the candidates of the drivers and returns the eUICCs that answered, sorted by driver, device and slot.
+// The same eUICC is listed once per driver reaching it, for example through both qmi and at.
+//
+// The candidates of a device are tried one after the other, and the devices in parallel.
+// A candidate failing, such as a slot without an eUICC or a serial port of the modem not answering AT commands, is skipped.
+// Once a candidate times out the remaining candidates of its device are skipped, as the device is still busy.
+// Discover returns the error of the context once it is done, with the eUICCs found so far.
+func Discover(ctx context.Context, opts *Options) ([]*EUICC, error) {
+	if opts == nil {
+		opts = new(Options)
+	}
+	o := *opts
+	o.setDefaults()
+
+	var devices [][]*url.URL
+	index := make(map[string]int)
+	for _, scheme := range o.Drivers {
+		uris, err := driver.Enumerate(scheme)
+		if err != nil {
+			o.Logger.Debug("[Probe] enumeration failed", "driver", scheme, "error", err)
+			continue
+		}
+		for _, uri := range uris {
+			resource := driver.Resource(uri)
+			i, ok := index[resource]
+			if !ok {
+				i = len(devices)
+				index[resource] = i
+				devices = append(devices, nil)
+			}
+			devices[i] = append(devices[i], uri)
+		}
+	}
+
+	var mutex sync.Mutex
+	var euiccs []*EUICC
+	var wg sync.WaitGroup
+	for _, uris := range devices {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, uri := range uris {
+				euicc, err := o.try(ctx, uri)
+				if err != nil {
+					o.Logger.Debug("[Probe] candidate failed", "uri", uri.String(), "error", err)
+					if errors.Is(err, errTimeout) || ctx.Err() != nil {
+						return
+					}
+					continue
+				}
+				mutex.Lock()
+				euiccs = append(euiccs, euicc)
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	slices.SortFunc(euiccs, func(a, b *EUICC) int {
+		return cmp.Or(
+			strings.Compare(a.Driver, b.Driver),
+			strings.Compare(a.Device, b.Device),
+			cmp.Compare(a.Slot, b.Slot),
+		)
+	})
+	return euiccs, ctx.Err()
+}
+
+// try opens the candidate, reads the EID of its ISD-R and closes it.
+// Opening a channel takes no context, so it runs in its own goroutine which is left behind when the candidate times out.
+func (o *Options) try(ctx context.Context, uri *url.URL) (*EUICC, error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, o.Timeout, errTimeout)
+	defer cancel()
+	type result struct {
+		eid []byte
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		eid, err := o.readEID(ctx, uri)
+		done <- result{eid, err}
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	case r := <-done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		return Describe(uri, r.eid)
+	}
+}
+
+// Describe returns the eUICC with the EID reached through the URI.
+func Describe(uri *url.URL, eid []byte) (*EUICC, error) {
+	euicc := &EUICC{
+		Driver: uri.Scheme,
+		Device: driver.Device(uri, uri.Query().Get("reader")),
+		EID:    strings.ToUpper(hex.EncodeToString(eid)),
+		URI:    uri.String(),
+	}
+	if uri.Query().Has("slot") {
+		var err error
+		if euicc.Slot, err = driver.Slot(uri); err != nil {
+			return nil, err
+		}
+	}
+	return euicc, nil
+}
+
+// readEID reads the EID through the candidate, holding the lock file of its channel.
+// The slot switched by the channel is mapped back on Disconnect, so that probing leaves the modems as they were.
+func (o *Options) readEID(ctx context.Context, uri *url.URL) ([]byte, error) {
+	lockFile, err := driver.LockPath(uri.String())
+	if err != nil {
+		return nil, err
+	}
+	lock, err := driver.Lock(lockFile, o.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+	channel, err := driver.Open(restoring(uri).String())
+	if err != nil {
+		return nil, err
+	}
+	client, err := lpa.New(&lpa.Options{
+		Channel: channel,
+		AID:     lpa.GSMAISDRApplicationAID,
+		Logger:  o.Logger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("select ISD-R: %w", err)
+	}
+	defer client.Close()
+	return client.EID(ctx)
+}
+
+// restoring returns the URI with restore=true when it has a slot.
+func restoring(uri *url.URL) *url.URL {
+	query := uri.Query()
+	if !query.Has("slot") || query.Has("restore") {
+		return uri
+	}
+	query.Set("restore", "true")
+	restored := *uri
+	restored.RawQuery = query.Encode()
+	return &restored
+}
@@ -0,0 +1,114 @@
+package probe_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"github.com/KilimcininKorOglu/euicc-go/driver/probe"
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hangingChannel is the channel of a device which never answers.
+type hangingChannel struct {
+	*virtual.EUICC
+	release chan struct{}
+}
+
+func (c *hangingChannel) Connect() error {
+	<-c.release
+	return errors.New("no answer")
+}
+
+func TestDiscover(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	var mutex sync.Mutex
+	opened := make(map[string]int)
+	driver.Register("probetest", func(uri *url.URL) (apdu.SmartCardChannel, error) {
+		// The slots are mapped back once probed.
+		query := uri.Query()
+		assert.Equal(t, "true", query.Get("restore"))
+		query.Del("restore")
+		uri.RawQuery = query.Encode()
+		mutex.Lock()
+		opened[uri.String()]++
+		mutex.Unlock()
+		switch uri.String() {
+		case "probetest:///dev/modem0?slot=1":
+			return virtual.New(), nil
+		case "probetest:///dev/modem0?slot=2":
+			// A physical SIM, without ISD-R.
+			euicc := virtual.New()
+			euicc.AID = []byte{0xA0, 0x00, 0x00, 0x00, 0x87}
+			return euicc, nil
+		case "probetest:///dev/modem1?slot=1":
+			return &hangingChannel{EUICC: virtual.New(), release: release}, nil
+		case "probetest:///dev/modem1?slot=2":
+			return virtual.New(), nil
+		}
+		return nil, errors.New("no such device")
+	})
+	driver.RegisterEnumerator("probetest", func() ([]*url.URL, error) {
+		return driver.URIs("probetest", []string{"/dev/modem0", "/dev/modem1", "/dev/modem2"}, 1, 2), nil
+	})
+
+	euiccs, err := probe.Discover(context.Background(), &probe.Options{
+		Drivers: []string{"probetest"},
+		Timeout: 100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Len(t, euiccs, 1)
+	assert.Equal(t, &probe.EUICC{
+		Driver: "probetest",
+		Device: "/dev/modem0",
+		Slot:   1,
+		EID:    "89049032000000000000000000000001",
+		URI:    "probetest:///dev/modem0?slot=1",
+	}, euiccs[0])
+	assert.Equal(t, 1, opened["probetest:///dev/modem0?slot=2"])
+	assert.Equal(t, 1, opened["probetest:///dev/modem2?slot=2"])
+	// The device is still busy with the candidate which timed out.
+	assert.Zero(t, opened["probetest:///dev/modem1?slot=2"])
+}
+
+func TestDiscover_Locked(t *testing.T) {
+	opened := make(chan string, 1)
+	driver.Register("probetest-locked", func(uri *url.URL) (apdu.SmartCardChannel, error) {
+		opened <- uri.String()
+		return virtual.New(), nil
+	})
+	driver.RegisterEnumerator("probetest-locked", func() ([]*url.URL, error) {
+		return driver.URIs("probetest-locked", []string{"/dev/modem0"}, 1), nil
+	})
+	lockFile, err := driver.LockPath("probetest-locked:///dev/modem0?slot=1")
+	require.NoError(t, err)
+	lock, err := driver.Lock(lockFile, time.Second)
+	require.NoError(t, err)
+	defer lock.Unlock()
+
+	euiccs, err := probe.Discover(context.Background(), &probe.Options{
+		Drivers: []string{"probetest-locked"},
+		Timeout: 100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, euiccs)
+	assert.Empty(t, opened)
+}
+
+func TestDiscover_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	euiccs, err := probe.Discover(ctx, &probe.Options{Drivers: []string{"probetest-canceled"}})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, euiccs)
+}
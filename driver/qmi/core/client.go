@@ -1,35 +1,46 @@
 package core
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver"
 )
 
 // QMIClient implements the apdu.SmartCardChannel interface using QMI protocol
 type QMIClient struct {
 	Transport Transport
-	Slot      uint8
-	ClientID  uint8
-	TxnID     uint32
-	channel   byte
+	// Slot is the physical slot of the card, Connect maps it to LogicalSlot.
+	Slot uint8
+	// LogicalSlot is the logical slot the APDUs are sent to, it defaults to 1.
+	LogicalSlot uint8
+	// RestoreSlot maps the physical slot mapped before Connect back to the logical slot in RestorePreviousSlot.
+	RestoreSlot bool
+	ClientID    uint8
+	TxnID       uint32
+	channel     byte
+	// previousSlot is the physical slot mapped to the logical slot before Connect, 0 when Connect did not switch it.
+	previousSlot uint8
 }
 
-// Connect establishes QMI session and allocates UIM client ID
+// Connect maps the physical slot to the logical slot
 func (q *QMIClient) Connect() error {
-	if err := q.ensureSlotActivated(); err != nil {
-		return err
-	}
-	// In QMI mode, we need to keep the SIM slot set to 1, because once the
-	// configured slot becomes active, it will be assigned as slot 1.
-	q.Slot = 1
-	return nil
+	return q.ensureSlotActivated()
+}
+
+// logicalSlot returns the logical slot the APDUs are sent to.
+func (q *QMIClient) logicalSlot() uint8 {
+	return cmp.Or(q.LogicalSlot, 1)
 }
 
-// ensureSlotActivated checks if the desired slot is activated and activates it if necessary
+// ensureSlotActivated checks if the desired slot is mapped to the logical slot and switches it if necessary
 func (q *QMIClient) ensureSlotActivated() error {
-	slot, err := q.currentActivatedSlot()
+	status, err := q.slotStatus(context.Background())
 	if err != nil {
 		// Some older devices do not support the GetSlotStatusRequest QMI command
 		if errors.Is(err, QMIErrorNotSupported) {
@@ -37,56 +48,137 @@ func (q *QMIClient) ensureSlotActivated() error {
 		}
 		return err
 	}
-	if slot == q.Slot {
+	previous := status.MappedSlot(q.logicalSlot())
+	if previous == q.Slot {
 		return nil
 	}
-	if err := q.switchSlot(); err != nil {
+	if err := q.switchSlot(context.Background(), q.Slot, q.logicalSlot()); err != nil {
+		// The physical slot is already mapped to the logical slot
+		if errors.Is(err, QMIErrorNoEffect) {
+			return nil
+		}
+		return err
+	}
+	// The slot is recorded before waiting, so that a card never becoming ready still gets its slot restored.
+	q.previousSlot = previous
+	if err := q.waitForSlotActivation(context.Background(), q.Slot, q.logicalSlot()); err != nil {
+		return errors.Join(err, q.RestorePreviousSlot())
+	}
+	return nil
+}
+
+// RestorePreviousSlot maps the physical slot mapped before Connect back to the logical slot when RestoreSlot is set.
+// It does not wait for the card to be ready, the channel is disconnected right after.
+func (q *QMIClient) RestorePreviousSlot() error {
+	if !q.RestoreSlot || q.previousSlot == 0 {
+		return nil
+	}
+	if err := q.switchSlot(context.Background(), q.previousSlot, q.logicalSlot()); err != nil {
+		return fmt.Errorf("restore slot %d: %w", q.previousSlot, err)
+	}
+	q.previousSlot = 0
+	return nil
+}
+
+// Slots returns the status of the physical slots
+func (q *QMIClient) Slots(ctx context.Context) ([]driver.SlotStatus, error) {
+	status, err := q.slotStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]driver.SlotStatus, len(status.Slots))
+	for i, slot := range status.Slots {
+		slots[i] = driver.SlotStatus{
+			Slot:        uint8(i + 1),
+			Active:      slot.SlotState == UIMSlotStateActive,
+			CardPresent: slot.CardState == UIMPhysicalCardStatePresent,
+			EUICC:       slot.EUICC,
+			ICCID:       decodeICCID(slot.ICCID),
+			EID:         fmt.Sprintf("%X", slot.EID),
+		}
+		if slots[i].Active {
+			slots[i].LogicalSlot = slot.LogicalSlot
+		}
+	}
+	return slots, nil
+}
+
+// SwitchSlot maps the physical slot to the logical slot and waits for the card to be ready
+func (q *QMIClient) SwitchSlot(ctx context.Context, slot, logicalSlot uint8) error {
+	if slot == 0 || logicalSlot == 0 {
+		return fmt.Errorf("invalid slot %d or logical slot %d", slot, logicalSlot)
+	}
+	if err := q.switchSlot(ctx, slot, logicalSlot); err != nil {
+		// The physical slot is already mapped to the logical slot
+		if errors.Is(err, QMIErrorNoEffect) {
+			return nil
+		}
 		return err
 	}
-	return q.waitForSlotActivation()
+	return q.waitForSlotActivation(ctx, slot, logicalSlot)
 }
 
-// waitForSlotActivation waits for the specified slot to be activated
-func (q *QMIClient) waitForSlotActivation() error {
+// waitForSlotActivation waits for the card of the logical slot to be ready
+func (q *QMIClient) waitForSlotActivation(ctx context.Context, slot, logicalSlot uint8) error {
 	var err error
 	for range 10 {
 		request := GetCardStatusRequest{
 			ClientID:      q.ClientID,
 			TransactionID: uint16(atomic.AddUint32(&q.TxnID, 1)),
 		}
-		err = q.Transport.Transmit(request.Request())
+		err = q.Transport.Transmit(ctx, request.Request())
 		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
 			continue
 		}
-		if request.Response.Ready() {
+		if request.Response.Ready(logicalSlot) {
 			return nil
 		}
-		time.Sleep(500 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
 	}
-	return fmt.Errorf("sim did not become available after slot %d activation err: %w", q.Slot, err)
+	return fmt.Errorf("sim did not become available after slot %d activation err: %w", slot, err)
 }
 
-// currentActivatedSlot returns the currently active logical slot
-func (q *QMIClient) currentActivatedSlot() (uint8, error) {
+// slotStatus returns the status of the physical slots
+func (q *QMIClient) slotStatus(ctx context.Context) (*GetSlotStatusResponse, error) {
 	request := GetSlotStatusRequest{
 		ClientID:      q.ClientID,
 		TransactionID: uint16(atomic.AddUint32(&q.TxnID, 1)),
 	}
-	if err := q.Transport.Transmit(request.Request()); err != nil {
-		return 0, err
+	if err := q.Transport.Transmit(ctx, request.Request()); err != nil {
+		return nil, err
 	}
-	return request.Response.ActivatedSlot, nil
+	return request.Response, nil
 }
 
-// switchSlot switches to the specified logical and physical slot
-func (q *QMIClient) switchSlot() error {
+// switchSlot maps the physical slot to the logical slot
+func (q *QMIClient) switchSlot(ctx context.Context, slot, logicalSlot uint8) error {
 	request := SwitchSlotRequest{
 		ClientID:      q.ClientID,
 		TransactionID: uint16(atomic.AddUint32(&q.TxnID, 1)),
-		LogicalSlot:   1,
-		PhysicalSlot:  uint32(q.Slot),
+		LogicalSlot:   logicalSlot,
+		PhysicalSlot:  uint32(slot),
+	}
+	return q.Transport.Transmit(ctx, request.Request())
+}
+
+// decodeICCID returns the digits of the ICCID, BCD-coded with swapped nibbles and padded with F.
+func decodeICCID(iccid []byte) string {
+	var digits strings.Builder
+	for _, b := range iccid {
+		for _, digit := range []byte{b & 0x0F, b >> 4} {
+			if digit <= 9 {
+				digits.WriteByte('0' + digit)
+			}
+		}
 	}
-	return q.Transport.Transmit(request.Request())
+	return digits.String()
 }
 
 // OpenLogicalChannel opens a logical channel with the specified AID
@@ -94,10 +186,10 @@ func (q *QMIClient) OpenLogicalChannel(AID []byte) (byte, error) {
 	request := OpenLogicalChannelRequest{
 		ClientID:      q.ClientID,
 		TransactionID: uint16(atomic.AddUint32(&q.TxnID, 1)),
-		Slot:          q.Slot,
+		Slot:          q.logicalSlot(),
 		AID:           AID,
 	}
-	if err := q.Transport.Transmit(request.Request()); err != nil {
+	if err := q.Transport.Transmit(context.Background(), request.Request()); err != nil {
 		return 0, err
 	}
 	q.channel = request.Response.Channel
@@ -110,21 +202,21 @@ func (q *QMIClient) CloseLogicalChannel(channel byte) error {
 		ClientID:      q.ClientID,
 		TransactionID: uint16(atomic.AddUint32(&q.TxnID, 1)),
 		Channel:       channel,
-		Slot:          q.Slot,
+		Slot:          q.logicalSlot(),
 	}
-	return q.Transport.Transmit(request.Request())
+	return q.Transport.Transmit(context.Background(), request.Request())
 }
 
 // Transmit sends an APDU command (basic channel implementation)
-func (q *QMIClient) Transmit(command []byte) ([]byte, error) {
+func (q *QMIClient) Transmit(ctx context.Context, command []byte) ([]byte, error) {
 	request := TransmitAPDURequest{
 		ClientID:      q.ClientID,
 		TransactionID: uint16(atomic.AddUint32(&q.TxnID, 1)),
-		Slot:          q.Slot,
+		Slot:          q.logicalSlot(),
 		Channel:       q.channel,
 		Command:       command,
 	}
-	if err := q.Transport.Transmit(request.Request()); err != nil {
+	if err := q.Transport.Transmit(ctx, request.Request()); err != nil {
 		return nil, err
 	}
 	return request.Response.Response, nil
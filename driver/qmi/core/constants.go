@@ -5,6 +5,7 @@ type ServiceType uint8
 
 const (
 	QMIServiceControl ServiceType = 0x00 // Control service
+	QMIServiceDMS     ServiceType = 0x02 // Device management service
 	QMIServiceUIM     ServiceType = 0x0B // UIM service
 )
 
@@ -26,6 +27,9 @@ const (
 	QMICtlCmdReleaseClientID  MessageID = 0x0023
 	QMICtlInternalProxyOpen   MessageID = 0xFF00
 
+	// DMS service commands
+	QMIDMSGetDeviceSerialNumbers MessageID = 0x0025
+
 	// UIM service commands
 	QMIUIMSendAPDU            MessageID = 0x003B
 	QMIUIMOpenLogicalChannel  MessageID = 0x0042
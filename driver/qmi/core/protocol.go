@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"cmp"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -39,7 +40,9 @@ func (r *InternalOpenResponse) UnmarshalResponse(TLVs *TLVs) error { return nil
 
 type AllocateClientIDRequest struct {
 	TransactionID uint16
-	Response      *AllocateClientIDResponse
+	// Service is the service of the client ID, it defaults to QMIServiceUIM.
+	Service  ServiceType
+	Response *AllocateClientIDResponse
 }
 
 func (r *AllocateClientIDRequest) Request() *Request {
@@ -49,7 +52,7 @@ func (r *AllocateClientIDRequest) Request() *Request {
 		MessageID:     QMICtlCmdAllocateClientID,
 		ServiceType:   QMIServiceControl,
 		Value: TLVs{
-			{Type: 0x01, Len: 1, Value: []byte{byte(QMIServiceUIM)}},
+			{Type: 0x01, Len: 1, Value: []byte{byte(cmp.Or(r.Service, QMIServiceUIM))}},
 		},
 		Response: r.Response,
 	}
@@ -74,7 +77,9 @@ func (r *AllocateClientIDResponse) UnmarshalResponse(TLVs *TLVs) error {
 type ReleaseClientIDRequest struct {
 	ClientID      uint8
 	TransactionID uint16
-	Response      *ReleaseClientIDResponse
+	// Service is the service of the client ID, it defaults to QMIServiceUIM.
+	Service  ServiceType
+	Response *ReleaseClientIDResponse
 }
 
 func (r *ReleaseClientIDRequest) Request() *Request {
@@ -84,7 +89,7 @@ func (r *ReleaseClientIDRequest) Request() *Request {
 		MessageID:     QMICtlCmdReleaseClientID,
 		ServiceType:   QMIServiceControl,
 		Value: TLVs{
-			{Type: 0x01, Len: 2, Value: []byte{byte(QMIServiceUIM), r.ClientID}},
+			{Type: 0x01, Len: 2, Value: []byte{byte(cmp.Or(r.Service, QMIServiceUIM)), r.ClientID}},
 		},
 		Response: r.Response,
 	}
@@ -104,6 +109,43 @@ func (r *ReleaseClientIDResponse) UnmarshalResponse(TLVs *TLVs) error {
 
 // endregion
 
+// region Get Device Serial Numbers Request
+
+type GetDeviceSerialNumbersRequest struct {
+	ClientID      uint8
+	TransactionID uint16
+	Response      *GetDeviceSerialNumbersResponse
+}
+
+func (r *GetDeviceSerialNumbersRequest) Request() *Request {
+	r.Response = new(GetDeviceSerialNumbersResponse)
+	return &Request{
+		ClientID:      r.ClientID,
+		TransactionID: r.TransactionID,
+		MessageID:     QMIDMSGetDeviceSerialNumbers,
+		ServiceType:   QMIServiceDMS,
+		Response:      r.Response,
+	}
+}
+
+// GetDeviceSerialNumbersResponse holds the serial numbers reported by the modem, empty when it has none of the kind.
+type GetDeviceSerialNumbersResponse struct {
+	ESN  string
+	IMEI string
+	MEID string
+}
+
+func (r *GetDeviceSerialNumbersResponse) UnmarshalResponse(TLVs *TLVs) error {
+	for t, serial := range map[uint8]*string{0x10: &r.ESN, 0x11: &r.IMEI, 0x12: &r.MEID} {
+		if value, ok := TLVs.Find(t); ok {
+			*serial = string(value.Value)
+		}
+	}
+	return nil
+}
+
+// endregion
+
 // region Switch Slot Request
 
 type SwitchSlotRequest struct {
@@ -166,7 +208,11 @@ type Slot struct {
 	CardState   UIMPhysicalCardState
 	SlotState   UIMSlotState
 	LogicalSlot uint8
-	ICCID       [10]byte
+	// ICCID is BCD-coded with swapped nibbles, as stored in EF.ICCID.
+	ICCID []byte
+	// EUICC and EID are only reported by the modems supporting the physical slot information and slot EID TLVs.
+	EUICC bool
+	EID   []byte
 }
 
 func (r *GetSlotStatusResponse) UnmarshalResponse(TLVs *TLVs) error {
@@ -185,17 +231,45 @@ func (r *GetSlotStatusResponse) UnmarshalResponse(TLVs *TLVs) error {
 		binary.Read(buf, binary.LittleEndian, &slot.LogicalSlot)
 		var iccidLen uint8
 		binary.Read(buf, binary.LittleEndian, &iccidLen)
-		if iccidLen > 0 {
-			binary.Read(buf, binary.LittleEndian, &slot.ICCID)
-		}
+		slot.ICCID = bytes.Clone(buf.Next(int(iccidLen)))
 		if slot.SlotState == UIMSlotStateActive {
 			r.ActivatedSlot = uint8(i + 1)
 		}
 		r.Slots = append(r.Slots, slot)
 	}
+	// The physical slot information holds the card protocol, the number of applications, the ATR and the eUICC flag.
+	if value, ok := TLVs.Find(0x11); ok {
+		buf := bytes.NewBuffer(value.Value)
+		count, _ := buf.ReadByte()
+		for i := range min(int(count), len(r.Slots)) {
+			buf.Next(5)
+			atrLen, _ := buf.ReadByte()
+			buf.Next(int(atrLen))
+			euicc, _ := buf.ReadByte()
+			r.Slots[i].EUICC = euicc != 0
+		}
+	}
+	if value, ok := TLVs.Find(0x12); ok {
+		buf := bytes.NewBuffer(value.Value)
+		count, _ := buf.ReadByte()
+		for i := range min(int(count), len(r.Slots)) {
+			eidLen, _ := buf.ReadByte()
+			r.Slots[i].EID = bytes.Clone(buf.Next(int(eidLen)))
+		}
+	}
 	return nil
 }
 
+// MappedSlot returns the physical slot mapped to the logical slot, or 0 when no active slot is mapped to it.
+func (r *GetSlotStatusResponse) MappedSlot(logicalSlot uint8) uint8 {
+	for i, slot := range r.Slots {
+		if slot.SlotState == UIMSlotStateActive && slot.LogicalSlot == logicalSlot {
+			return uint8(i + 1)
+		}
+	}
+	return 0
+}
+
 // endregion
 
 // region Get Card Status Request
@@ -265,21 +339,28 @@ func (r *GetCardStatusResponse) UnmarshalResponse(TLVs *TLVs) error {
 			binary.Read(buf, binary.LittleEndian, &app.State)
 			card.Applications = append(card.Applications, app)
 
-			buf.Next(28)
+			// The personalization state, feature and retries, the AID and the PIN states and retries.
+			buf.Next(4)
+			aidLen, _ := buf.ReadByte()
+			buf.Next(int(aidLen) + 7)
 		}
 		r.Cards = append(r.Cards, card)
 	}
 	return nil
 }
 
-func (r *GetCardStatusResponse) Ready() bool {
-	for _, card := range r.Cards {
-		if card.State == UIMCardStatusPresent {
-			for _, app := range card.Applications {
-				if app.Type == UIMCardApplicationTypeUSIM && app.State == UIMCardApplicationStateReady {
-					return true
-				}
-			}
+// Ready reports whether the USIM application of the card of the logical slot is ready.
+func (r *GetCardStatusResponse) Ready(logicalSlot uint8) bool {
+	if logicalSlot == 0 || int(logicalSlot) > len(r.Cards) {
+		return false
+	}
+	card := r.Cards[logicalSlot-1]
+	if card.State != UIMCardStatusPresent {
+		return false
+	}
+	for _, app := range card.Applications {
+		if app.Type == UIMCardApplicationTypeUSIM && app.State == UIMCardApplicationStateReady {
+			return true
 		}
 	}
 	return false
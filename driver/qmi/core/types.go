@@ -1,6 +1,9 @@
 package core
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Request struct {
 	ClientID      uint8
@@ -17,5 +20,7 @@ type ResponseUnmarshaler interface {
 }
 
 type Transport interface {
-	Transmit(request *Request) error
+	// Transmit sends the request and waits for its response until the read timeout of the request
+	// or the end of the context.
+	Transmit(ctx context.Context, request *Request) error
 }
@@ -0,0 +1,10 @@
+package qmi
+
+// SetProxyAddress makes the driver reach qmi-proxy at the address until restore is called.
+func SetProxyAddress(address string) (restore func()) {
+	previous := proxyAddress
+	proxyAddress = address
+	return func() { proxyAddress = previous }
+}
+
+var ErrProxyUnavailable = errProxyUnavailable
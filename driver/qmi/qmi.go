@@ -1,14 +1,18 @@
 package qmi
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"net/url"
 	"os"
 	"sync/atomic"
 	"syscall"
 
 	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
 	"github.com/KilimcininKorOglu/euicc-go/driver/qmi/core"
 	transport "github.com/KilimcininKorOglu/euicc-go/driver/qmi/transport/qmi"
 )
@@ -20,22 +24,108 @@ type QMI struct {
 	device string
 }
 
-// New creates a new QMI connection to the specified device
-func New(device string, slot uint8) (apdu.SmartCardChannel, error) {
-	conn, err := newQMIConn()
-	if err != nil {
+// Mode selects how the device is reached.
+type Mode int
+
+const (
+	// ModeAuto goes through qmi-proxy when it is running, and opens the device directly otherwise.
+	ModeAuto Mode = iota
+	// ModeProxy goes through the qmi-proxy of libqmi, which shares the device with ModemManager and the other libqmi clients.
+	ModeProxy
+	// ModeDirect opens the device, the other programs using it without qmi-proxy may take the responses of the channel.
+	ModeDirect
+)
+
+// Options configure the QMI channel.
+type Options struct {
+	// Mode defaults to ModeAuto, the mode query of the qmi URIs is auto, proxy or direct.
+	// The QRTR channels ignore it.
+	Mode Mode
+	// LogicalSlot is the logical slot the slot is mapped to on Connect, it defaults to 1.
+	LogicalSlot uint8
+	// RestoreSlot maps the slot mapped before Connect back to the logical slot on Disconnect.
+	RestoreSlot bool
+}
+
+var (
+	_ driver.SlotSwitcher   = (*QMI)(nil)
+	_ driver.SlotSwitcher   = (*QRTR)(nil)
+	_ driver.DeviceIdentity = (*QMI)(nil)
+	_ driver.DeviceIdentity = (*QRTR)(nil)
+)
+
+// proxyAddress is the abstract Unix socket of qmi-proxy.
+var proxyAddress = "\x00qmi-proxy"
+
+// errProxyUnavailable is returned when qmi-proxy is not running, ModeAuto opens the device directly then.
+var errProxyUnavailable = errors.New("qmi-proxy is not running")
+
+func init() {
+	driver.Register("qmi", func(uri *url.URL) (apdu.SmartCardChannel, error) {
+		slot, err := driver.Slot(uri)
+		if err != nil {
+			return nil, err
+		}
+		opts, err := slotOptions(uri)
+		if err != nil {
+			return nil, err
+		}
+		switch mode := uri.Query().Get("mode"); mode {
+		case "", "auto":
+		case "proxy":
+			opts.Mode = ModeProxy
+		case "direct":
+			opts.Mode = ModeDirect
+		default:
+			return nil, fmt.Errorf("qmi: invalid mode %q, expected auto, proxy or direct", mode)
+		}
+		return New(driver.Device(uri, "/dev/cdc-wdm0"), slot, opts)
+	})
+	driver.RegisterEnumerator("qmi", func() ([]*url.URL, error) {
+		return driver.SlotURIs("qmi", driver.Devices("/dev/cdc-wdm*", "qmi_wwan"), func(device string) (apdu.SmartCardChannel, error) {
+			return New(device, 1, nil)
+		}), nil
+	})
+}
+
+// slotOptions returns the options holding the logical and restore queries of the URI.
+func slotOptions(uri *url.URL) (*Options, error) {
+	var opts Options
+	var err error
+	if opts.LogicalSlot, err = driver.LogicalSlot(uri); err != nil {
+		return nil, err
+	}
+	if opts.RestoreSlot, err = driver.RestoreSlot(uri); err != nil {
 		return nil, err
 	}
+	return &opts, nil
+}
+
+// New creates a new QMI connection to the specified device, the options may be nil.
+func New(device string, slot uint8, opts *Options) (apdu.SmartCardChannel, error) {
+	if opts == nil {
+		opts = new(Options)
+	}
 	q := &QMI{
-		conn:   conn,
 		device: device,
 		QMIClient: core.QMIClient{
-			Transport: transport.New(conn),
-			Slot:      slot,
+			Slot:        slot,
+			LogicalSlot: opts.LogicalSlot,
+			RestoreSlot: opts.RestoreSlot,
 		},
 	}
-	if err := q.openProxyConnection(); err != nil {
-		q.conn.Close()
+	var err error
+	switch opts.Mode {
+	case ModeProxy:
+		err = q.openProxy()
+	case ModeDirect:
+		err = q.openDevice()
+	default:
+		if err = q.openProxy(); errors.Is(err, errProxyUnavailable) {
+			err = q.openDevice()
+		}
+	}
+	if err != nil {
 		return nil, err
 	}
 	if err := q.allocateClientID(); err != nil {
@@ -45,15 +135,39 @@ func New(device string, slot uint8) (apdu.SmartCardChannel, error) {
 	return q, nil
 }
 
+// openProxy connects to qmi-proxy and asks it to open the device.
+func (q *QMI) openProxy() error {
+	conn, err := newQMIConn()
+	if err != nil {
+		return err
+	}
+	q.conn, q.Transport = conn, transport.New(conn)
+	if err := q.openProxyConnection(); err != nil {
+		q.conn.Close()
+		return err
+	}
+	return nil
+}
+
+// openDevice opens the device without qmi-proxy, QMUX messages are read and written on it as on the proxy socket.
+func (q *QMI) openDevice() error {
+	conn, err := driver.OpenCDCWDM(q.device)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", q.device, err)
+	}
+	q.conn, q.Transport = conn, transport.New(conn)
+	return nil
+}
+
 // newQMIConn establishes connection to qmi-proxy
 func newQMIConn() (net.Conn, error) {
 	fd, err := syscall.Socket(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
 	if err != nil {
 		return nil, fmt.Errorf("create socket: %w", err)
 	}
-	if err := syscall.Connect(fd, &syscall.SockaddrUnix{Name: "\x00qmi-proxy"}); err != nil {
+	if err := syscall.Connect(fd, &syscall.SockaddrUnix{Name: proxyAddress}); err != nil {
 		syscall.Close(fd)
-		return nil, fmt.Errorf("connect to qmi-proxy: %w", err)
+		return nil, fmt.Errorf("connect to qmi-proxy: %w: %w", errProxyUnavailable, err)
 	}
 	conn, err := net.FileConn(os.NewFile(uintptr(fd), "euicc-go-qmi-proxy"))
 	if err != nil {
@@ -68,7 +182,7 @@ func (q *QMI) openProxyConnection() error {
 		TransactionID: uint16(atomic.AddUint32(&q.TxnID, 1)),
 		DevicePath:    []byte(q.device),
 	}
-	err := q.Transport.Transmit(request.Request())
+	err := q.Transport.Transmit(context.Background(), request.Request())
 	if err == io.EOF {
 		return fmt.Errorf("device %s is not connected", q.device)
 	}
@@ -80,7 +194,7 @@ func (q *QMI) allocateClientID() error {
 	request := core.AllocateClientIDRequest{
 		TransactionID: uint16(atomic.AddUint32(&q.TxnID, 1)),
 	}
-	err := q.Transport.Transmit(request.Request())
+	err := q.Transport.Transmit(context.Background(), request.Request())
 	if err == io.EOF {
 		return fmt.Errorf("device %s doesn't support QMI protocol", q.device)
 	}
@@ -97,13 +211,47 @@ func (q *QMI) releaseClientID() error {
 		ClientID:      q.ClientID,
 		TransactionID: uint16(atomic.AddUint32(&q.TxnID, 1)),
 	}
-	return q.Transport.Transmit(request.Request())
+	return q.Transport.Transmit(context.Background(), request.Request())
 }
 
-// Disconnect releases the client ID and closes the connection
-func (q *QMI) Disconnect() error {
-	if err := q.releaseClientID(); err != nil {
-		return err
+// IMEI reads the IMEI of the modem with the DMS service, through a client ID allocated for the request.
+func (q *QMI) IMEI(ctx context.Context) (string, error) {
+	allocate := core.AllocateClientIDRequest{
+		TransactionID: uint16(atomic.AddUint32(&q.TxnID, 1)),
+		Service:       core.QMIServiceDMS,
+	}
+	if err := q.Transport.Transmit(ctx, allocate.Request()); err != nil {
+		return "", fmt.Errorf("allocate DMS client ID: %w", err)
 	}
-	return q.conn.Close()
+	request := core.GetDeviceSerialNumbersRequest{
+		ClientID:      allocate.Response.ClientID,
+		TransactionID: uint16(atomic.AddUint32(&q.TxnID, 1)),
+	}
+	err := q.Transport.Transmit(ctx, request.Request())
+	release := core.ReleaseClientIDRequest{
+		ClientID:      allocate.Response.ClientID,
+		TransactionID: uint16(atomic.AddUint32(&q.TxnID, 1)),
+		Service:       core.QMIServiceDMS,
+	}
+	if releaseErr := q.Transport.Transmit(ctx, release.Request()); err == nil {
+		err = releaseErr
+	}
+	if err != nil {
+		return "", err
+	}
+	return imei(request.Response)
+}
+
+// imei returns the IMEI of the serial numbers, the modems of CDMA networks may have none.
+func imei(serials *core.GetDeviceSerialNumbersResponse) (string, error) {
+	if serials.IMEI == "" {
+		return "", errors.New("the modem has no IMEI")
+	}
+	return serials.IMEI, nil
+}
+
+// Disconnect restores the previous slot when asked to, releases the client ID and closes the connection
+// The connection is closed even when the slot cannot be restored or the client ID released.
+func (q *QMI) Disconnect() error {
+	return errors.Join(q.RestorePreviousSlot(), q.releaseClientID(), q.conn.Close())
 }
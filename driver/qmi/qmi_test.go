@@ -0,0 +1,194 @@
+package qmi_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"github.com/KilimcininKorOglu/euicc-go/driver/qmi"
+	"github.com/KilimcininKorOglu/euicc-go/driver/qmi/core"
+	"github.com/KilimcininKorOglu/euicc-go/driver/qmi/qmitest"
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer starts a fake qmi-proxy reached by the driver during the test.
+func newServer(t *testing.T, opts *qmitest.Options) *qmitest.Server {
+	server, err := qmitest.NewServer(opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+	t.Cleanup(qmi.SetProxyAddress(server.Address()))
+	return server
+}
+
+func TestQMI(t *testing.T) {
+	for name, opts := range map[string]*qmitest.Options{
+		"active slot":      {ActiveSlot: 2},
+		"slot activation":  {ActivationDelay: 2},
+		"slot status":      {ActiveSlot: 2, Errors: map[core.MessageID]core.QMIError{core.QMIUIMGetSlotStatus: core.QMIErrorNotSupported}},
+		"indications":      {ActiveSlot: 2, Indications: true},
+		"card status fail": {Errors: map[core.MessageID]core.QMIError{core.QMIUIMGetCardStatus: core.QMIErrorDeviceNotReady}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			card := virtual.New()
+			opts.Slots = []qmitest.Slot{{}, {Card: card}}
+			server := newServer(t, opts)
+
+			channel, err := qmi.New("/dev/cdc-wdm0", 2, &qmi.Options{Mode: qmi.ModeProxy})
+			require.NoError(t, err)
+			client, err := lpa.New(&lpa.Options{Channel: channel})
+			if name == "card status fail" {
+				assert.ErrorIs(t, err, core.QMIErrorDeviceNotReady)
+				assert.ErrorContains(t, err, "sim did not become available after slot 2 activation")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint8(2), server.ActiveSlot())
+			eid, err := client.EID(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, card.EID, eid)
+			require.NoError(t, client.Close())
+		})
+	}
+}
+
+func TestQMI_Error(t *testing.T) {
+	newServer(t, &qmitest.Options{
+		Slots:  []qmitest.Slot{{Card: virtual.New()}},
+		Errors: map[core.MessageID]core.QMIError{core.QMIUIMSendAPDU: core.QMIErrorInsufficientResources},
+	})
+
+	channel, err := qmi.New("/dev/cdc-wdm0", 1, nil)
+	require.NoError(t, err)
+	require.NoError(t, channel.Connect())
+	defer channel.Disconnect()
+	_, err = channel.OpenLogicalChannel(virtual.ISDRApplicationAID)
+	require.NoError(t, err)
+	_, err = channel.Transmit(context.Background(), []byte{0x81, 0xE2, 0x91, 0x00, 0x03, 0xBF, 0x3E, 0x00})
+	assert.ErrorIs(t, err, core.QMIErrorInsufficientResources)
+	_, err = channel.OpenLogicalChannel([]byte{0xA0, 0x00})
+	assert.ErrorIs(t, err, core.QMIErrorSimFileNotFound)
+}
+
+func TestQMI_Slots(t *testing.T) {
+	card := virtual.New()
+	server := newServer(t, &qmitest.Options{Slots: []qmitest.Slot{
+		{Card: virtual.New(), ICCID: []byte{0x98, 0x10, 0x14, 0x30, 0x12, 0x32, 0x54, 0x76, 0x98, 0xF0}},
+		{Card: card, EUICC: true, EID: card.EID},
+		{},
+	}})
+
+	channel, err := qmi.New("/dev/cdc-wdm0", 2, &qmi.Options{Mode: qmi.ModeProxy})
+	require.NoError(t, err)
+	defer channel.Disconnect()
+	switcher := channel.(driver.SlotSwitcher)
+	slots, err := switcher.Slots(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []driver.SlotStatus{
+		{Slot: 1, LogicalSlot: 1, Active: true, CardPresent: true, ICCID: "8901410321234567890"},
+		{Slot: 2, CardPresent: true, EUICC: true, EID: "89049032000000000000000000000001"},
+		{Slot: 3},
+	}, slots)
+
+	require.NoError(t, switcher.SwitchSlot(context.Background(), 2, 1))
+	assert.Equal(t, uint8(2), server.ActiveSlot())
+	require.NoError(t, switcher.SwitchSlot(context.Background(), 2, 1))
+	assert.ErrorIs(t, switcher.SwitchSlot(context.Background(), 4, 1), core.QMIErrorInvalidArgument)
+}
+
+func TestQMI_LogicalSlot(t *testing.T) {
+	card := virtual.New()
+	server := newServer(t, &qmitest.Options{
+		Slots:   []qmitest.Slot{{Card: card}, {Card: virtual.New()}},
+		Mapping: []uint8{2, 1},
+	})
+
+	channel, err := qmi.New("/dev/cdc-wdm0", 1, &qmi.Options{Mode: qmi.ModeProxy, LogicalSlot: 2})
+	require.NoError(t, err)
+	client, err := lpa.New(&lpa.Options{Channel: channel})
+	require.NoError(t, err)
+	// The physical slot 1 is already mapped to the logical slot 2, the mapping is kept.
+	assert.Equal(t, uint8(2), server.MappedSlot(1))
+	assert.Equal(t, uint8(1), server.MappedSlot(2))
+	eid, err := client.EID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, card.EID, eid)
+	require.NoError(t, client.Close())
+}
+
+func TestQMI_RestoreSlot(t *testing.T) {
+	for name, restore := range map[string]bool{"restore": true, "keep": false} {
+		t.Run(name, func(t *testing.T) {
+			server := newServer(t, &qmitest.Options{Slots: []qmitest.Slot{{Card: virtual.New()}, {Card: virtual.New()}}})
+
+			channel, err := qmi.New("/dev/cdc-wdm0", 2, &qmi.Options{Mode: qmi.ModeProxy, RestoreSlot: restore})
+			require.NoError(t, err)
+			require.NoError(t, channel.Connect())
+			assert.Equal(t, uint8(2), server.ActiveSlot())
+			require.NoError(t, channel.Disconnect())
+			if restore {
+				assert.Equal(t, uint8(1), server.ActiveSlot())
+			} else {
+				assert.Equal(t, uint8(2), server.ActiveSlot())
+			}
+		})
+	}
+}
+
+func TestQMI_RestoreSlotNotReady(t *testing.T) {
+	server := newServer(t, &qmitest.Options{
+		Slots:  []qmitest.Slot{{Card: virtual.New()}, {Card: virtual.New()}},
+		Errors: map[core.MessageID]core.QMIError{core.QMIUIMGetCardStatus: core.QMIErrorDeviceNotReady},
+	})
+
+	channel, err := qmi.New("/dev/cdc-wdm0", 2, &qmi.Options{Mode: qmi.ModeProxy, RestoreSlot: true})
+	require.NoError(t, err)
+	defer channel.Disconnect()
+	assert.ErrorIs(t, channel.Connect(), core.QMIErrorDeviceNotReady)
+	assert.Equal(t, uint8(1), server.ActiveSlot())
+}
+
+func TestQMI_DisconnectReleaseError(t *testing.T) {
+	newServer(t, &qmitest.Options{
+		Slots:  []qmitest.Slot{{Card: virtual.New()}},
+		Errors: map[core.MessageID]core.QMIError{core.QMICtlCmdReleaseClientID: core.QMIErrorInternal},
+	})
+
+	channel, err := qmi.New("/dev/cdc-wdm0", 1, &qmi.Options{Mode: qmi.ModeProxy})
+	require.NoError(t, err)
+	assert.ErrorIs(t, channel.Disconnect(), core.QMIErrorInternal)
+	// The connection is closed anyway.
+	_, err = channel.Transmit(context.Background(), []byte{0x00, 0x70, 0x00, 0x00, 0x01})
+	assert.ErrorIs(t, err, net.ErrClosed)
+}
+
+func TestQMI_IMEI(t *testing.T) {
+	newServer(t, &qmitest.Options{Slots: []qmitest.Slot{{Card: virtual.New()}}, IMEI: "490154203237518"})
+
+	channel, err := qmi.New("/dev/cdc-wdm0", 1, &qmi.Options{Mode: qmi.ModeProxy})
+	require.NoError(t, err)
+	defer channel.Disconnect()
+	imei, err := channel.(driver.DeviceIdentity).IMEI(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "490154203237518", imei)
+	// The DMS client ID is released, the UIM one still works.
+	require.NoError(t, channel.Connect())
+	_, err = channel.OpenLogicalChannel(virtual.ISDRApplicationAID)
+	require.NoError(t, err)
+}
+
+func TestNew_Mode(t *testing.T) {
+	t.Cleanup(qmi.SetProxyAddress(filepath.Join(t.TempDir(), "qmi-proxy")))
+	device := filepath.Join(t.TempDir(), "cdc-wdm0")
+
+	_, err := qmi.New(device, 1, &qmi.Options{Mode: qmi.ModeProxy})
+	assert.ErrorIs(t, err, qmi.ErrProxyUnavailable)
+	// qmi.ModeAuto opens the device once qmi-proxy is found not to be running.
+	_, err = qmi.New(device, 1, nil)
+	assert.ErrorContains(t, err, "open "+device)
+	assert.NotErrorIs(t, err, qmi.ErrProxyUnavailable)
+}
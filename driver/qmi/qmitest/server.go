@@ -0,0 +1,466 @@
+// Package qmitest provides a fake qmi-proxy for testing the QMI driver without a modem.
+//
+// The server listens on a Unix socket and answers the QMI requests of the driver,
+// the client IDs, the serial numbers of the DMS service, the slot and card status, the slot switch and the logical channels of the UIM service,
+// with the cards of its slots, usually virtual eUICCs:
+//
+//	server, err := qmitest.NewServer(&qmitest.Options{Slots: []qmitest.Slot{{}, {Card: virtual.New()}}})
+//	defer server.Close()
+//
+// The driver reaches the server at server.Address() instead of the abstract socket of qmi-proxy.
+package qmitest
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver/qmi/core"
+)
+
+// usimAID is the AID of the USIM application reported by the card status, the driver expects 16 bytes.
+var usimAID = []byte{0xA0, 0x00, 0x00, 0x00, 0x87, 0x10, 0x02, 0xFF, 0xFF, 0xFF, 0xFF, 0x89, 0x06, 0x19, 0x00, 0x00}
+
+// Slot is a physical slot of the modem.
+type Slot struct {
+	// Card is the card inserted in the slot, nil for an empty slot.
+	Card apdu.SmartCardChannel
+	// ICCID is the BCD-coded ICCID reported by the slot status, up to 10 bytes.
+	ICCID []byte
+	// EUICC and EID are reported by the physical slot information and the slot EID of the slot status.
+	EUICC bool
+	EID   []byte
+}
+
+// Options configure the server.
+type Options struct {
+	// Slots are the physical slots of the modem, at least one is required.
+	Slots []Slot
+	// ActiveSlot is the physical slot mapped to the logical slot 1, it defaults to 1.
+	ActiveSlot uint8
+	// Mapping is the physical slot mapped to each logical slot, 0 for none, it overrides ActiveSlot.
+	Mapping []uint8
+	// ActivationDelay is the number of card status requests answered with a card that is not ready yet after a slot switch.
+	ActivationDelay int
+	// Errors makes the requests fail with the QMI error, keyed by message ID.
+	Errors map[core.MessageID]core.QMIError
+	// Indications sends an indication without result TLV before each response, as a device shared with other clients does.
+	Indications bool
+	// IMEI is reported by the serial numbers of the DMS service, it defaults to 356938035643809.
+	IMEI string
+}
+
+// Server is a fake qmi-proxy serving the cards of its slots on a Unix socket.
+type Server struct {
+	opts      Options
+	listener  net.Listener
+	directory string
+	wg        sync.WaitGroup
+
+	mutex    sync.Mutex
+	conns    map[net.Conn]struct{}
+	closed   bool
+	mapping  []uint8
+	pending  int
+	clientID uint8
+}
+
+// message is a QMUX message of the control or UIM service.
+type message struct {
+	serviceType   core.ServiceType
+	clientID      uint8
+	transactionID uint16
+	messageID     core.MessageID
+	value         core.TLVs
+}
+
+// NewServer connects the cards of the slots, as a modem powers its SIMs on, and serves them on a new Unix socket.
+func NewServer(opts *Options) (*Server, error) {
+	if opts == nil || len(opts.Slots) == 0 {
+		return nil, errors.New("qmitest: at least one slot is required")
+	}
+	s := &Server{opts: *opts, conns: make(map[net.Conn]struct{})}
+	s.opts.IMEI = cmp.Or(s.opts.IMEI, "356938035643809")
+	s.mapping = []uint8{max(s.opts.ActiveSlot, 1)}
+	if len(s.opts.Mapping) > 0 {
+		s.mapping = append([]uint8(nil), s.opts.Mapping...)
+	}
+	var err error
+	for i, slot := range s.opts.Slots {
+		if slot.Card == nil {
+			continue
+		}
+		if err = slot.Card.Connect(); err != nil {
+			s.disconnect(i)
+			return nil, fmt.Errorf("qmitest: connect the card of slot %d: %w", i+1, err)
+		}
+	}
+	if s.directory, err = os.MkdirTemp("", "qmitest"); err != nil {
+		s.disconnect(len(s.opts.Slots))
+		return nil, err
+	}
+	if s.listener, err = net.Listen("unix", filepath.Join(s.directory, "qmi-proxy")); err != nil {
+		s.disconnect(len(s.opts.Slots))
+		_ = os.RemoveAll(s.directory)
+		return nil, err
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Address returns the path of the Unix socket of the server.
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+// ActiveSlot returns the physical slot mapped to the logical slot 1.
+func (s *Server) ActiveSlot() uint8 {
+	return s.MappedSlot(1)
+}
+
+// MappedSlot returns the physical slot mapped to the logical slot, 0 for none.
+func (s *Server) MappedSlot(logicalSlot uint8) uint8 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if logicalSlot == 0 || int(logicalSlot) > len(s.mapping) {
+		return 0
+	}
+	return s.mapping[logicalSlot-1]
+}
+
+// Close stops the server, closes the connections and disconnects the cards.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.mutex.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mutex.Unlock()
+	s.wg.Wait()
+	_ = os.RemoveAll(s.directory)
+	s.disconnect(len(s.opts.Slots))
+	return err
+}
+
+// disconnect disconnects the cards of the first n slots.
+func (s *Server) disconnect(n int) {
+	for _, slot := range s.opts.Slots[:n] {
+		if slot.Card != nil {
+			_ = slot.Card.Disconnect()
+		}
+	}
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mutex.Unlock()
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.conns, conn)
+		s.mutex.Unlock()
+		_ = conn.Close()
+	}()
+	for {
+		request, err := readMessage(conn)
+		if err != nil {
+			return
+		}
+		value := s.handle(request)
+		if s.opts.Indications {
+			indication := core.TLVs{tlv(0x10, []byte{0x00})}
+			if _, err := conn.Write(request.marshal(core.QMIMessageTypeIndication, indication)); err != nil {
+				return
+			}
+		}
+		if _, err := conn.Write(request.marshal(core.QMIMessageTypeResponse, value)); err != nil {
+			return
+		}
+	}
+}
+
+// readMessage reads a QMUX request.
+func readMessage(r io.Reader) (*message, error) {
+	header := make([]byte, 3)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, int(binary.LittleEndian.Uint16(header[1:3]))+1)
+	copy(buf, header)
+	if _, err := io.ReadFull(r, buf[3:]); err != nil {
+		return nil, err
+	}
+	m := &message{serviceType: core.ServiceType(buf[4]), clientID: buf[5]}
+	var value []byte
+	switch {
+	case m.serviceType == core.QMIServiceControl && len(buf) >= 12:
+		m.transactionID = uint16(buf[7])
+		m.messageID = core.MessageID(binary.LittleEndian.Uint16(buf[8:10]))
+		value = buf[12:]
+	case len(buf) >= 13:
+		m.transactionID = binary.LittleEndian.Uint16(buf[7:9])
+		m.messageID = core.MessageID(binary.LittleEndian.Uint16(buf[9:11]))
+		value = buf[13:]
+	default:
+		return nil, fmt.Errorf("qmitest: message too short: %d bytes", len(buf))
+	}
+	for len(value) >= 3 {
+		n := int(binary.LittleEndian.Uint16(value[1:3]))
+		if len(value) < 3+n {
+			return nil, errors.New("qmitest: TLV too short")
+		}
+		m.value = append(m.value, core.TLV{Type: value[0], Len: uint16(n), Value: value[3 : 3+n]})
+		value = value[3+n:]
+	}
+	return m, nil
+}
+
+// marshal returns the response or indication to the request, carrying the TLVs.
+func (m *message) marshal(messageType core.MessageType, value core.TLVs) []byte {
+	sdu := new(bytes.Buffer)
+	_, _ = value.WriteTo(sdu)
+	header := new(bytes.Buffer)
+	header.WriteByte(byte(messageType))
+	if m.serviceType == core.QMIServiceControl {
+		header.WriteByte(byte(m.transactionID))
+	} else {
+		_ = binary.Write(header, binary.LittleEndian, m.transactionID)
+	}
+	_ = binary.Write(header, binary.LittleEndian, m.messageID)
+	_ = binary.Write(header, binary.LittleEndian, uint16(sdu.Len()))
+	header.Write(sdu.Bytes())
+	buf := new(bytes.Buffer)
+	buf.WriteByte(core.QMUXHeaderIfType)
+	_ = binary.Write(buf, binary.LittleEndian, uint16(header.Len()+5))
+	buf.WriteByte(0x80)
+	buf.WriteByte(byte(m.serviceType))
+	buf.WriteByte(m.clientID)
+	buf.Write(header.Bytes())
+	return buf.Bytes()
+}
+
+// result returns the result TLV of the QMI error, followed by the value of a successful response.
+func result(err error, value ...core.TLV) core.TLVs {
+	var qmiErr core.QMIError
+	if err != nil && !errors.As(err, &qmiErr) {
+		qmiErr = core.QMIErrorInternal
+	}
+	if qmiErr != core.QMIErrorNone {
+		return core.TLVs{{Type: 0x02, Len: 4, Value: []byte{byte(core.QMIResultFailure), 0, byte(qmiErr), byte(qmiErr >> 8)}}}
+	}
+	return append(core.TLVs{{Type: 0x02, Len: 4, Value: make([]byte, 4)}}, value...)
+}
+
+func tlv(t uint8, value []byte) core.TLV {
+	return core.TLV{Type: t, Len: uint16(len(value)), Value: value}
+}
+
+// handle runs the request and returns the TLVs of its response.
+func (s *Server) handle(m *message) core.TLVs {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err, ok := s.opts.Errors[m.messageID]; ok {
+		return result(err)
+	}
+	switch m.messageID {
+	case core.QMICtlInternalProxyOpen:
+		if _, ok := m.value.Find(0x01); !ok {
+			return result(core.QMIErrorMissingArgument)
+		}
+		return result(nil)
+	case core.QMICtlCmdAllocateClientID:
+		value, ok := m.value.Find(0x01)
+		if !ok || len(value.Value) != 1 {
+			return result(core.QMIErrorMissingArgument)
+		}
+		s.clientID++
+		return result(nil, tlv(0x01, []byte{value.Value[0], s.clientID}))
+	case core.QMICtlCmdReleaseClientID:
+		value, ok := m.value.Find(0x01)
+		if !ok {
+			return result(core.QMIErrorMissingArgument)
+		}
+		return result(nil, tlv(0x01, value.Value))
+	case core.QMIDMSGetDeviceSerialNumbers:
+		if m.serviceType != core.QMIServiceDMS {
+			break
+		}
+		return result(nil, tlv(0x11, []byte(s.opts.IMEI)))
+	case core.QMIUIMGetSlotStatus:
+		return result(nil, s.slotStatus()...)
+	case core.QMIUIMSwitchSlot:
+		return result(s.switchSlot(m))
+	case core.QMIUIMGetCardStatus:
+		return result(nil, tlv(0x10, s.cardStatus()))
+	case core.QMIUIMOpenLogicalChannel:
+		card, err := s.card(m)
+		if err != nil {
+			return result(err)
+		}
+		value, ok := m.value.Find(0x10)
+		if !ok || len(value.Value) < 1 || len(value.Value) != 1+int(value.Value[0]) {
+			return result(core.QMIErrorMissingArgument)
+		}
+		channel, err := card.OpenLogicalChannel(value.Value[1:])
+		if err != nil {
+			return result(core.QMIErrorSimFileNotFound)
+		}
+		return result(nil, tlv(0x10, []byte{channel}))
+	case core.QMIUIMCloseLogicalChannel:
+		card, err := s.card(m)
+		if err != nil {
+			return result(err)
+		}
+		value, ok := m.value.Find(0x11)
+		if !ok || len(value.Value) != 1 {
+			return result(core.QMIErrorMissingArgument)
+		}
+		if err := card.CloseLogicalChannel(value.Value[0]); err != nil {
+			return result(core.QMIErrorInvalidArgument)
+		}
+		return result(nil)
+	case core.QMIUIMSendAPDU:
+		card, err := s.card(m)
+		if err != nil {
+			return result(err)
+		}
+		value, ok := m.value.Find(0x02)
+		if !ok || len(value.Value) < 2 || len(value.Value) != 2+int(binary.LittleEndian.Uint16(value.Value)) {
+			return result(core.QMIErrorMissingArgument)
+		}
+		response, err := card.Transmit(context.Background(), value.Value[2:])
+		var statusErr *apdu.StatusWordError
+		if err != nil && (!errors.As(err, &statusErr) || len(response) < 2) {
+			return result(core.QMIErrorInternal)
+		}
+		return result(nil, tlv(0x10, append(binary.LittleEndian.AppendUint16(nil, uint16(len(response))), response...)))
+	}
+	return result(core.QMIErrorInvalidQmiCommand)
+}
+
+// logicalSlot returns the logical slot the physical slot is mapped to, 0 for none.
+func (s *Server) logicalSlot(slot uint8) uint8 {
+	for i, mapped := range s.mapping {
+		if mapped == slot {
+			return uint8(i + 1)
+		}
+	}
+	return 0
+}
+
+// slotStatus returns the physical slot status, the physical slot information and the slot EIDs.
+func (s *Server) slotStatus() core.TLVs {
+	status := []byte{byte(len(s.opts.Slots))}
+	information := []byte{byte(len(s.opts.Slots))}
+	eids := []byte{byte(len(s.opts.Slots))}
+	for i, slot := range s.opts.Slots {
+		cardState, slotState := core.UIMPhysicalCardStateAbsent, core.UIMSlotStateInactive
+		if slot.Card != nil {
+			cardState = core.UIMPhysicalCardStatePresent
+		}
+		logicalSlot := s.logicalSlot(uint8(i + 1))
+		if logicalSlot != 0 {
+			slotState = core.UIMSlotStateActive
+		}
+		status = binary.LittleEndian.AppendUint32(status, uint32(cardState))
+		status = binary.LittleEndian.AppendUint32(status, uint32(slotState))
+		status = append(status, logicalSlot, byte(len(slot.ICCID)))
+		status = append(status, slot.ICCID...)
+		// The card protocol, the number of applications and an empty ATR precede the eUICC flag.
+		information = append(information, 0, 0, 0, 0, 0, 0, 0)
+		if slot.EUICC {
+			information[len(information)-1] = 1
+		}
+		eids = append(eids, byte(len(slot.EID)))
+		eids = append(eids, slot.EID...)
+	}
+	return core.TLVs{tlv(0x10, status), tlv(0x11, information), tlv(0x12, eids)}
+}
+
+// switchSlot maps the logical slot of the request to its physical slot, swapping the mappings when the physical slot is mapped to another logical slot.
+func (s *Server) switchSlot(m *message) error {
+	logicalSlot, ok := m.value.Find(0x01)
+	physicalSlot, ok2 := m.value.Find(0x02)
+	if !ok || !ok2 || len(logicalSlot.Value) != 1 || len(physicalSlot.Value) != 4 {
+		return core.QMIErrorMissingArgument
+	}
+	logical, slot := logicalSlot.Value[0], binary.LittleEndian.Uint32(physicalSlot.Value)
+	if logical == 0 || int(logical) > len(s.mapping) || slot == 0 || slot > uint32(len(s.opts.Slots)) {
+		return core.QMIErrorInvalidArgument
+	}
+	if uint8(slot) == s.mapping[logical-1] {
+		return core.QMIErrorNoEffect
+	}
+	if other := s.logicalSlot(uint8(slot)); other != 0 {
+		s.mapping[other-1] = s.mapping[logical-1]
+	}
+	s.mapping[logical-1], s.pending = uint8(slot), s.opts.ActivationDelay
+	return nil
+}
+
+// cardStatus returns the card status of the logical slots, the USIM applications are not ready while a switch is pending.
+func (s *Server) cardStatus() []byte {
+	buf := append(make([]byte, 8), byte(len(s.mapping)))
+	state := core.UIMCardApplicationStateReady
+	if s.pending > 0 {
+		s.pending--
+		state = core.UIMCardApplicationStateDetected
+	}
+	for _, slot := range s.mapping {
+		if slot == 0 || s.opts.Slots[slot-1].Card == nil {
+			buf = append(buf, byte(core.UIMCardStatusAbsent), 0, 0, 0, 0, 0)
+			continue
+		}
+		buf = append(buf, byte(core.UIMCardStatusPresent), 0, 0, 0, 0, 1)
+		buf = append(buf, byte(core.UIMCardApplicationTypeUSIM), byte(state), 0, 0, 0, 0, byte(len(usimAID)))
+		buf = append(buf, usimAID...)
+		buf = append(buf, 0, 0, 0, 0, 0, 0, 0)
+	}
+	return buf
+}
+
+// card returns the card of the logical slot of the request.
+func (s *Server) card(m *message) (apdu.SmartCardChannel, error) {
+	value, ok := m.value.Find(0x01)
+	if !ok || len(value.Value) != 1 {
+		return nil, core.QMIErrorMissingArgument
+	}
+	logical := value.Value[0]
+	if logical == 0 || int(logical) > len(s.mapping) || s.mapping[logical-1] == 0 {
+		return nil, core.QMIErrorInvalidArgument
+	}
+	card := s.opts.Slots[s.mapping[logical-1]-1].Card
+	if card == nil || s.pending > 0 {
+		return nil, core.QMIErrorDeviceNotReady
+	}
+	return card, nil
+}
@@ -2,15 +2,19 @@ package qmi
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"os"
+	"sync/atomic"
 	"time"
 	"unsafe"
 
 	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
 	"github.com/KilimcininKorOglu/euicc-go/driver/qmi/core"
 	transport "github.com/KilimcininKorOglu/euicc-go/driver/qmi/transport/qrtr"
 	"golang.org/x/sys/unix"
@@ -82,8 +86,36 @@ type QRTR struct {
 	core.QMIClient
 }
 
-// NewQRTR creates a new QRTR connection to the UIM service
-func NewQRTR(slot uint8) (apdu.SmartCardChannel, error) {
+func init() {
+	driver.Register("qrtr", func(uri *url.URL) (apdu.SmartCardChannel, error) {
+		slot, err := driver.Slot(uri)
+		if err != nil {
+			return nil, err
+		}
+		opts, err := slotOptions(uri)
+		if err != nil {
+			return nil, err
+		}
+		return NewQRTR(slot, opts)
+	})
+	driver.RegisterEnumerator("qrtr", func() ([]*url.URL, error) {
+		fd, err := unix.Socket(unix.AF_QIPCRTR, unix.SOCK_DGRAM, 0)
+		if err != nil {
+			// The kernel has no QRTR support, the modem is not an on-chip Qualcomm one.
+			return nil, nil
+		}
+		unix.Close(fd)
+		return driver.SlotURIs("qrtr", []string{""}, func(string) (apdu.SmartCardChannel, error) {
+			return NewQRTR(1, nil)
+		}), nil
+	})
+}
+
+// NewQRTR creates a new QRTR connection to the UIM service, the options may be nil.
+func NewQRTR(slot uint8, opts *Options) (apdu.SmartCardChannel, error) {
+	if opts == nil {
+		opts = new(Options)
+	}
 	conn, err := newQRTRConn()
 	if err != nil {
 		return nil, err
@@ -91,8 +123,10 @@ func NewQRTR(slot uint8) (apdu.SmartCardChannel, error) {
 	q := &QRTR{
 		conn: conn,
 		QMIClient: core.QMIClient{
-			Transport: transport.New(conn),
-			Slot:      slot,
+			Transport:   transport.New(conn),
+			Slot:        slot,
+			LogicalSlot: opts.LogicalSlot,
+			RestoreSlot: opts.RestoreSlot,
 		},
 	}
 	q.conn.Service, err = q.findService(core.QMIServiceUIM)
@@ -146,7 +180,32 @@ func (c *QRTR) sendControlPacket(serviceType core.ServiceType) error {
 	return err
 }
 
+// IMEI reads the IMEI of the modem with the DMS service, reached with its own QRTR socket.
+func (c *QRTR) IMEI(ctx context.Context) (string, error) {
+	conn, err := newQRTRConn()
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	dms := &QRTR{conn: conn}
+	if conn.Service, err = dms.findService(core.QMIServiceDMS); err != nil {
+		return "", err
+	}
+	request := core.GetDeviceSerialNumbersRequest{
+		TransactionID: uint16(atomic.AddUint32(&c.TxnID, 1)),
+	}
+	if err := transport.New(conn).Transmit(ctx, request.Request()); err != nil {
+		return "", err
+	}
+	return imei(request.Response)
+}
+
+// Disconnect restores the previous slot when asked to and closes the connection
 func (c *QRTR) Disconnect() error {
+	if err := c.RestorePreviousSlot(); err != nil {
+		c.conn.Close()
+		return err
+	}
 	return c.conn.Close()
 }
 
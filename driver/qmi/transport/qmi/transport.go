@@ -2,6 +2,7 @@ package qmi
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -71,12 +72,15 @@ func (t *Transport) bytes(r *core.Request) ([]byte, error) {
 }
 
 // Read reads a response from the connection and unmarshals it into the Request's Response field
-func (t *Transport) Read(c net.Conn, r *core.Request) (int, error) {
+func (t *Transport) Read(ctx context.Context, c net.Conn, r *core.Request) (int, error) {
 	if r.ReadTimeout == 0 {
 		r.ReadTimeout = 30 * time.Second
 	}
 	deadline := time.Now().Add(r.ReadTimeout)
 	for time.Now().Before(deadline) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		c.SetReadDeadline(time.Now().Add(1 * time.Second))
 
 		header := make([]byte, 3)
@@ -94,13 +98,21 @@ func (t *Transport) Read(c net.Conn, r *core.Request) (int, error) {
 			return 0, err
 		}
 
+		// The error of the result TLV is only returned once the message is known to answer the request,
+		// the header is parsed before it.
 		var response Response
-		if err := response.UnmarshalBinary(buf[:length]); err != nil {
-			return 0, err
+		err := response.UnmarshalBinary(buf[:length])
+		// The indications and the responses to other requests are skipped,
+		// the device opened without qmi-proxy delivers the messages of every client.
+		if response.MessageType == core.QMIMessageTypeIndication || response.ServiceType != r.ServiceType || response.MessageID != r.MessageID {
+			continue
 		}
-		if r.ClientID != response.ClientID && response.TransactionID != r.TransactionID {
+		if r.ClientID != response.ClientID || response.TransactionID != t.transactionID(r) {
 			continue
 		}
+		if err != nil {
+			return 0, err
+		}
 		if err := r.Response.UnmarshalResponse(&response.Value); err != nil {
 			return 0, err
 		}
@@ -109,7 +121,16 @@ func (t *Transport) Read(c net.Conn, r *core.Request) (int, error) {
 	return 0, fmt.Errorf("timed out waiting for response for transaction ID %d", r.TransactionID)
 }
 
-func (t *Transport) Transmit(request *core.Request) error {
+// transactionID returns the transaction ID of the request as it is on the wire,
+// the control service only carries the low byte of the counter.
+func (t *Transport) transactionID(r *core.Request) uint16 {
+	if r.ServiceType == core.QMIServiceControl {
+		return uint16(uint8(r.TransactionID))
+	}
+	return r.TransactionID
+}
+
+func (t *Transport) Transmit(ctx context.Context, request *core.Request) error {
 	bs, err := t.bytes(request)
 	if err != nil {
 		return err
@@ -117,6 +138,6 @@ func (t *Transport) Transmit(request *core.Request) error {
 	if _, err = t.conn.Write(bs); err != nil {
 		return err
 	}
-	_, err = t.Read(t.conn, request)
+	_, err = t.Read(ctx, t.conn, request)
 	return err
 }
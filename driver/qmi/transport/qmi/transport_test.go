@@ -0,0 +1,83 @@
+package qmi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver/qmi/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type resultResponse struct{}
+
+func (resultResponse) UnmarshalResponse(*core.TLVs) error { return nil }
+
+// response returns the response of the service to the client, failing with the error when it is not zero.
+func response(t *testing.T, serviceType core.ServiceType, clientID uint8, transactionID uint16, qmiErr core.QMIError) []byte {
+	result := []byte{0x00, 0x00, 0x00, 0x00}
+	if qmiErr != 0 {
+		result = []byte{byte(core.QMIResultFailure), 0x00, byte(qmiErr), byte(qmiErr >> 8)}
+	}
+	bs, err := new(Transport).bytes(&core.Request{
+		ClientID:      clientID,
+		TransactionID: transactionID,
+		ServiceType:   serviceType,
+		MessageID:     0x0038,
+		Value:         core.TLVs{{Type: 0x02, Len: 4, Value: result}},
+	})
+	require.NoError(t, err)
+	bs[6] = byte(core.QMIMessageTypeResponse)
+	return bs
+}
+
+func TestTransport_SkipsOtherClients(t *testing.T) {
+	conn, device := net.Pipe()
+	defer conn.Close()
+	defer device.Close()
+	go func() {
+		buf := make([]byte, 256)
+		if _, err := device.Read(buf); err != nil {
+			return
+		}
+		// The device opened without qmi-proxy delivers the response of another client with the same transaction ID.
+		_, _ = device.Write(response(t, core.QMIServiceUIM, 2, 7, core.QMIErrorNotSupported))
+		_, _ = device.Write(response(t, core.QMIServiceUIM, 1, 6, core.QMIErrorNotSupported))
+		_, _ = device.Write(response(t, core.QMIServiceUIM, 1, 7, 0))
+	}()
+	err := New(conn).Transmit(context.Background(), &core.Request{
+		ClientID:      1,
+		TransactionID: 7,
+		ServiceType:   core.QMIServiceUIM,
+		MessageID:     0x0038,
+		Response:      resultResponse{},
+	})
+	assert.NoError(t, err)
+}
+
+func TestTransport_ControlTransactionID(t *testing.T) {
+	conn, device := net.Pipe()
+	defer conn.Close()
+	defer device.Close()
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			if _, err := device.Read(buf); err != nil {
+				return
+			}
+			// The control service echoes the 8-bit transaction ID of the request.
+			_, _ = device.Write(response(t, core.QMIServiceControl, 0, uint16(buf[7]), 0))
+		}
+	}()
+	transport := New(conn)
+	for transactionID := range uint16(300) {
+		err := transport.Transmit(context.Background(), &core.Request{
+			TransactionID: transactionID + 1,
+			ServiceType:   core.QMIServiceControl,
+			MessageID:     0x0038,
+			Response:      resultResponse{},
+		})
+		require.NoError(t, err, "transaction %d", transactionID+1)
+	}
+}
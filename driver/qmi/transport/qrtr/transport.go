@@ -2,9 +2,12 @@ package qrtr
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net"
+	"os"
 	"time"
 
 	"github.com/KilimcininKorOglu/euicc-go/driver/qmi/core"
@@ -33,14 +36,22 @@ func (t *Transport) bytes(r *core.Request) ([]byte, error) {
 }
 
 // Read reads a response from the connection and unmarshals it into the Request's Response field
-func (t *Transport) Read(c net.Conn, r *core.Request) (int, error) {
+func (t *Transport) Read(ctx context.Context, c net.Conn, r *core.Request) (int, error) {
 	if r.ReadTimeout == 0 {
 		r.ReadTimeout = 30 * time.Second
 	}
 	deadline := time.Now().Add(r.ReadTimeout)
 	for time.Now().Before(deadline) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		c.SetReadDeadline(time.Now().Add(1 * time.Second))
+
 		buf := make([]byte, 512)
 		n, err := c.Read(buf)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			continue
+		}
 		if err != nil {
 			return 0, err
 		}
@@ -60,7 +71,7 @@ func (t *Transport) Read(c net.Conn, r *core.Request) (int, error) {
 	return 0, fmt.Errorf("timed out waiting for response for transaction ID %d", r.TransactionID)
 }
 
-func (t *Transport) Transmit(request *core.Request) error {
+func (t *Transport) Transmit(ctx context.Context, request *core.Request) error {
 	bs, err := t.bytes(request)
 	if err != nil {
 		return err
@@ -68,6 +79,6 @@ func (t *Transport) Transmit(request *core.Request) error {
 	if _, err = t.conn.Write(bs); err != nil {
 		return err
 	}
-	_, err = t.Read(t.conn, request)
+	_, err = t.Read(ctx, t.conn, request)
 	return err
 }
@@ -0,0 +1,186 @@
+package driver
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+)
+
+// Opener opens the channel described by the URI, the scheme of the URI selects the driver:
+//
+//	qmi:///dev/cdc-wdm0?slot=1
+//	qrtr://?slot=1
+//	mbim:///dev/cdc-wdm0?slot=2
+//	at:///dev/ttyUSB2
+//	pcsc://?reader=Identiv+uTrust+3700+F
+type Opener func(uri *url.URL) (apdu.SmartCardChannel, error)
+
+// Enumerator lists the URIs of the channels a driver may reach on this host, such as one URI per slot of every modem.
+// The URIs are candidates for discovery, opening them may still fail.
+type Enumerator func() ([]*url.URL, error)
+
+var (
+	openersMutex sync.RWMutex
+	openers      = make(map[string]Opener)
+	enumerators  = make(map[string]Enumerator)
+)
+
+// Register makes a driver available under the scheme, drivers register themselves in their init function.
+// It panics if the scheme is already registered or the opener is nil.
+func Register(scheme string, opener Opener) {
+	openersMutex.Lock()
+	defer openersMutex.Unlock()
+	if opener == nil {
+		panic("driver: Register opener is nil")
+	}
+	if _, dup := openers[scheme]; dup {
+		panic("driver: Register called twice for scheme " + scheme)
+	}
+	openers[scheme] = opener
+}
+
+// RegisterEnumerator makes the candidates of the driver registered under the scheme discoverable.
+// It panics if the scheme already has an enumerator or the enumerator is nil.
+func RegisterEnumerator(scheme string, enumerator Enumerator) {
+	openersMutex.Lock()
+	defer openersMutex.Unlock()
+	if enumerator == nil {
+		panic("driver: RegisterEnumerator enumerator is nil")
+	}
+	if _, dup := enumerators[scheme]; dup {
+		panic("driver: RegisterEnumerator called twice for scheme " + scheme)
+	}
+	enumerators[scheme] = enumerator
+}
+
+// Enumerate returns the candidate URIs of the driver registered under the scheme,
+// or nil when the driver has no enumerator.
+func Enumerate(scheme string) ([]*url.URL, error) {
+	openersMutex.RLock()
+	enumerator, ok := enumerators[scheme]
+	openersMutex.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+	return enumerator()
+}
+
+// Schemes returns the sorted schemes of the registered drivers.
+func Schemes() []string {
+	openersMutex.RLock()
+	defer openersMutex.RUnlock()
+	schemes := make([]string, 0, len(openers))
+	for scheme := range openers {
+		schemes = append(schemes, scheme)
+	}
+	slices.Sort(schemes)
+	return schemes
+}
+
+// Open opens the channel described by the URI with the driver registered under its scheme.
+// The driver package must be imported for it to be registered, for example:
+//
+//	import _ "github.com/KilimcininKorOglu/euicc-go/driver/qmi"
+func Open(uri string) (apdu.SmartCardChannel, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("driver: invalid URI %q: %w", uri, err)
+	}
+	openersMutex.RLock()
+	opener, ok := openers[u.Scheme]
+	openersMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("driver: unknown scheme %q (forgotten import?)", u.Scheme)
+	}
+	return opener(u)
+}
+
+// Device returns the device of the URI: the path of qmi:///dev/cdc-wdm0,
+// or the host or opaque part of URIs such as at://COM3 and at:COM3.
+// It returns fallback when the URI has no device.
+func Device(uri *url.URL, fallback string) string {
+	switch {
+	case uri.Opaque != "":
+		return uri.Opaque
+	case uri.Host != "" && uri.Path != "":
+		return uri.Host + uri.Path
+	case uri.Host != "":
+		return uri.Host
+	case uri.Path != "":
+		return uri.Path
+	}
+	return fallback
+}
+
+// Resource returns the key of what the channel of the URI holds while it is open, such as the modem device.
+// Channels with the same resource, like two slots of one modem, must not be used at the same time.
+// The PC/SC readers share a single resource, as the PC/SC library is released when a reader is disconnected.
+func Resource(uri *url.URL) string {
+	if uri.Scheme == "pcsc" {
+		return uri.Scheme
+	}
+	if device := Device(uri, ""); device != "" {
+		return device
+	}
+	return uri.Scheme
+}
+
+// Slot returns the slot query parameter of the URI, 1 when it is not set.
+func Slot(uri *url.URL) (uint8, error) {
+	value := uri.Query().Get("slot")
+	if value == "" {
+		return 1, nil
+	}
+	slot, err := strconv.ParseUint(value, 10, 8)
+	if err != nil || slot == 0 {
+		return 0, fmt.Errorf("driver: invalid slot %q", value)
+	}
+	return uint8(slot), nil
+}
+
+// URIs returns the URI of every slot of every device, for enumerators of modems with several slots.
+func URIs(scheme string, devices []string, slots ...uint8) []*url.URL {
+	var uris []*url.URL
+	for _, device := range devices {
+		for _, slot := range slots {
+			uris = append(uris, &url.URL{
+				Scheme:   scheme,
+				Path:     device,
+				RawQuery: url.Values{"slot": {strconv.Itoa(int(slot))}}.Encode(),
+			})
+		}
+	}
+	return uris
+}
+
+// Devices returns the devices matching the pattern, such as /dev/cdc-wdm*, bound to one of the kernel drivers.
+// The kernel driver is read from sysfs, devices whose driver is unknown are kept so that they are still tried.
+func Devices(pattern string, kernelDrivers ...string) []string {
+	matches, _ := filepath.Glob(pattern)
+	devices := matches[:0]
+	for _, device := range matches {
+		kernelDriver := KernelDriver(device)
+		if kernelDriver == "" || len(kernelDrivers) == 0 || slices.Contains(kernelDrivers, kernelDriver) {
+			devices = append(devices, device)
+		}
+	}
+	return devices
+}
+
+// KernelDriver returns the name of the kernel driver bound to the character device,
+// such as qmi_wwan or cdc_mbim for /dev/cdc-wdm0 and option for /dev/ttyUSB2, or an empty string when it is unknown.
+func KernelDriver(device string) string {
+	for _, class := range []string{"usbmisc", "tty"} {
+		link, err := os.Readlink(filepath.Join("/sys/class", class, filepath.Base(device), "device", "driver"))
+		if err == nil {
+			return filepath.Base(link)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package driver_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	var opened *url.URL
+	driver.Register("test", func(uri *url.URL) (apdu.SmartCardChannel, error) {
+		opened = uri
+		return virtual.New(), nil
+	})
+	assert.Contains(t, driver.Schemes(), "test")
+	assert.Panics(t, func() { driver.Register("test", func(*url.URL) (apdu.SmartCardChannel, error) { return nil, nil }) })
+
+	channel, err := driver.Open("test:///dev/cdc-wdm0?slot=2")
+	require.NoError(t, err)
+	assert.IsType(t, &virtual.EUICC{}, channel)
+	assert.Equal(t, "/dev/cdc-wdm0", driver.Device(opened, ""))
+	slot, err := driver.Slot(opened)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(2), slot)
+
+	_, err = driver.Open("unknown:///dev/ttyUSB2")
+	assert.EqualError(t, err, `driver: unknown scheme "unknown" (forgotten import?)`)
+}
+
+func TestDevice(t *testing.T) {
+	tests := map[string]string{
+		"at:///dev/ttyUSB2": "/dev/ttyUSB2",
+		"at://COM3":         "COM3",
+		"at:COM3":           "COM3",
+		"qmi://?slot=1":     "/dev/cdc-wdm0",
+	}
+	for uri, expected := range tests {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		assert.Equal(t, expected, driver.Device(u, "/dev/cdc-wdm0"), uri)
+	}
+}
+
+func TestSlot(t *testing.T) {
+	tests := map[string]uint8{
+		"qmi:///dev/cdc-wdm0":         1,
+		"qmi:///dev/cdc-wdm0?slot=2":  2,
+		"mbim:///dev/cdc-wdm0?slot=0": 0,
+		"qrtr://?slot=256":            0,
+	}
+	for uri, expected := range tests {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		slot, err := driver.Slot(u)
+		if expected == 0 {
+			assert.Error(t, err, uri)
+			continue
+		}
+		require.NoError(t, err, uri)
+		assert.Equal(t, expected, slot, uri)
+	}
+}
+
+func TestEnumerate(t *testing.T) {
+	driver.RegisterEnumerator("enumerated", func() ([]*url.URL, error) {
+		return driver.URIs("enumerated", []string{"/dev/cdc-wdm0", "/dev/cdc-wdm1"}, 1, 2), nil
+	})
+	assert.Panics(t, func() { driver.RegisterEnumerator("enumerated", func() ([]*url.URL, error) { return nil, nil }) })
+
+	uris, err := driver.Enumerate("enumerated")
+	require.NoError(t, err)
+	var actual []string
+	for _, uri := range uris {
+		actual = append(actual, uri.String())
+	}
+	assert.Equal(t, []string{
+		"enumerated:///dev/cdc-wdm0?slot=1",
+		"enumerated:///dev/cdc-wdm0?slot=2",
+		"enumerated:///dev/cdc-wdm1?slot=1",
+		"enumerated:///dev/cdc-wdm1?slot=2",
+	}, actual)
+
+	uris, err = driver.Enumerate("unknown")
+	require.NoError(t, err)
+	assert.Nil(t, uris)
+}
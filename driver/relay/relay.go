@@ -0,0 +1,279 @@
+// Package relay forwards a smart card channel over the network.
+//
+// A Server exposes a local channel, such as a modem or a PC/SC reader of a test rack,
+// and the relay driver opens it from another host:
+//
+//	relay://rack1.example.com:8720
+//	relay+ws://rack1.example.com:8080/relay
+//	relay+wss://rack1.example.com/relay
+//
+// The relay scheme speaks the protocol over TCP, relay+ws and relay+wss over a WebSocket.
+// Each call of the channel is a Request answered by a Response, encoded as JSON: one per line over TCP,
+// and one per text message over a WebSocket. The protocol has no authentication,
+// the relay is meant to be reached through a VPN, an SSH tunnel or a TLS terminating proxy.
+package relay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"golang.org/x/net/websocket"
+)
+
+type Operation string
+
+const (
+	OperationConnect             Operation = "connect"
+	OperationDisconnect          Operation = "disconnect"
+	OperationOpenLogicalChannel  Operation = "open"
+	OperationCloseLogicalChannel Operation = "close"
+	OperationTransmit            Operation = "transmit"
+)
+
+// Request is a call of the channel sent by the client.
+type Request struct {
+	Operation Operation       `json:"op"`
+	AID       sgp22.HexString `json:"aid,omitempty"`
+	Channel   byte            `json:"channel,omitempty"`
+	Command   sgp22.HexString `json:"command,omitempty"`
+}
+
+// Response is the result of a Request.
+// SW is set when the error is an apdu.StatusWordError, so that the client returns one too.
+type Response struct {
+	Channel  byte            `json:"channel,omitempty"`
+	Response sgp22.HexString `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	SW       uint16          `json:"sw,omitempty"`
+}
+
+func newResponse(err error) *Response {
+	response := new(Response)
+	if err != nil {
+		response.Error = err.Error()
+		var statusErr *apdu.StatusWordError
+		if errors.As(err, &statusErr) {
+			response.SW = statusErr.SW
+		}
+	}
+	return response
+}
+
+func (r *Response) err(command []byte) error {
+	if r.Error == "" {
+		return nil
+	}
+	if r.SW == 0 {
+		return errors.New(r.Error)
+	}
+	statusErr := &apdu.StatusWordError{Command: command, SW: r.SW}
+	if statusErr.Error() == r.Error {
+		return statusErr
+	}
+	return &remoteError{message: r.Error, err: statusErr}
+}
+
+// remoteError keeps the message of the server for an error the client rebuilds.
+type remoteError struct {
+	message string
+	err     error
+}
+
+func (e *remoteError) Error() string { return e.message }
+
+func (e *remoteError) Unwrap() error { return e.err }
+
+// codec sends and receives the messages of a connection.
+type codec interface {
+	send(v any) error
+	receive(v any) error
+}
+
+// lineCodec encodes the messages as JSON lines, for TCP connections.
+type lineCodec struct {
+	encoder *json.Encoder
+	decoder *json.Decoder
+}
+
+func newLineCodec(conn net.Conn) *lineCodec {
+	return &lineCodec{encoder: json.NewEncoder(conn), decoder: json.NewDecoder(bufio.NewReader(conn))}
+}
+
+func (c *lineCodec) send(v any) error    { return c.encoder.Encode(v) }
+func (c *lineCodec) receive(v any) error { return c.decoder.Decode(v) }
+
+// webSocketCodec encodes the messages as JSON text messages.
+type webSocketCodec struct{ conn *websocket.Conn }
+
+func (c *webSocketCodec) send(v any) error    { return websocket.JSON.Send(c.conn, v) }
+func (c *webSocketCodec) receive(v any) error { return websocket.JSON.Receive(c.conn, v) }
+
+func init() {
+	for _, scheme := range []string{"relay", "relay+ws", "relay+wss"} {
+		driver.Register(scheme, func(uri *url.URL) (apdu.SmartCardChannel, error) {
+			if uri.Host == "" {
+				return nil, fmt.Errorf("relay: the URI has no host")
+			}
+			return New(uri), nil
+		})
+	}
+}
+
+// lifecycleTimeout bounds the calls taking no context, Connect waits for the server
+// while another client uses its channel, see Server.BusyTimeout.
+const lifecycleTimeout = time.Minute
+
+// Relay is an apdu.SmartCardChannel forwarding the calls to a Server.
+type Relay struct {
+	uri    *url.URL
+	mutex  sync.Mutex
+	conn   net.Conn
+	codec  codec
+	broken error
+}
+
+// New returns a channel relayed by the server of the URI, it connects to the server on Connect.
+func New(uri *url.URL) *Relay {
+	return &Relay{uri: uri}
+}
+
+func (r *Relay) dial() error {
+	var err error
+	switch r.uri.Scheme {
+	case "relay+ws", "relay+wss":
+		location := *r.uri
+		location.Scheme = location.Scheme[len("relay+"):]
+		origin := &url.URL{Scheme: "http", Host: location.Host}
+		var config *websocket.Config
+		if config, err = websocket.NewConfig(location.String(), origin.String()); err != nil {
+			return err
+		}
+		config.Dialer = &net.Dialer{Timeout: lifecycleTimeout}
+		var conn *websocket.Conn
+		if conn, err = websocket.DialConfig(config); err != nil {
+			return fmt.Errorf("relay: %w", err)
+		}
+		r.conn, r.codec = conn, &webSocketCodec{conn: conn}
+	default:
+		if r.conn, err = net.DialTimeout("tcp", r.uri.Host, lifecycleTimeout); err != nil {
+			return fmt.Errorf("relay: %w", err)
+		}
+		r.codec = newLineCodec(r.conn)
+	}
+	r.broken = nil
+	return nil
+}
+
+// call sends the request and receives its response.
+// An interrupted call leaves the connection out of step with the server, it is closed and the channel is unusable.
+func (r *Relay) call(ctx context.Context, request *Request) (*Response, error) {
+	if r.conn == nil {
+		return nil, errors.New("relay: not connected")
+	}
+	if r.broken != nil {
+		return nil, r.broken
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	deadline, _ := ctx.Deadline()
+	_ = r.conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { _ = r.conn.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+	var response Response
+	err := r.codec.send(request)
+	if err == nil {
+		err = r.codec.receive(&response)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		r.broken = fmt.Errorf("relay: connection lost: %w", err)
+		_ = r.conn.Close()
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (r *Relay) lifecycle(request *Request) (*Response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout)
+	defer cancel()
+	return r.call(ctx, request)
+}
+
+// Connect connects to the server, which connects its channel.
+func (r *Relay) Connect() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.dial(); err != nil {
+		return err
+	}
+	response, err := r.lifecycle(&Request{Operation: OperationConnect})
+	if err == nil {
+		err = response.err(nil)
+	}
+	if err != nil {
+		_ = r.conn.Close()
+		r.conn = nil
+	}
+	return err
+}
+
+// Disconnect disconnects the channel of the server and closes the connection.
+func (r *Relay) Disconnect() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.conn == nil {
+		return nil
+	}
+	response, err := r.lifecycle(&Request{Operation: OperationDisconnect})
+	if err == nil {
+		err = response.err(nil)
+	}
+	if closeErr := r.conn.Close(); r.broken == nil && err == nil {
+		err = closeErr
+	}
+	r.conn = nil
+	return err
+}
+
+func (r *Relay) OpenLogicalChannel(AID []byte) (byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	response, err := r.lifecycle(&Request{Operation: OperationOpenLogicalChannel, AID: AID})
+	if err != nil {
+		return 0, err
+	}
+	return response.Channel, response.err(nil)
+}
+
+func (r *Relay) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	response, err := r.call(ctx, &Request{Operation: OperationTransmit, Command: command})
+	if err != nil {
+		return nil, err
+	}
+	return response.Response, response.err(command)
+}
+
+func (r *Relay) CloseLogicalChannel(channel byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	response, err := r.lifecycle(&Request{Operation: OperationCloseLogicalChannel, Channel: channel})
+	if err != nil {
+		return err
+	}
+	return response.err(nil)
+}
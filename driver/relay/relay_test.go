@@ -0,0 +1,119 @@
+package relay
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusChannel returns an apdu.StatusWordError for the unknown instructions, as the modem drivers do.
+type statusChannel struct{ *virtual.EUICC }
+
+func (c *statusChannel) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	response, err := c.EUICC.Transmit(ctx, command)
+	if err == nil && apdu.Response(response).SW() == 0x6D00 {
+		return response, &apdu.StatusWordError{Command: command, SW: 0x6D00}
+	}
+	return response, err
+}
+
+// listen serves the channel over TCP and returns the relay URI of the server.
+func listen(t *testing.T, server *Server) *url.URL {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go server.Serve(listener)
+	return &url.URL{Scheme: "relay", Host: listener.Addr().String()}
+}
+
+// listenWebSocket serves the channel over a WebSocket and returns the relay+ws URI of the server.
+func listenWebSocket(t *testing.T, server *Server) *url.URL {
+	http := httptest.NewServer(server.Handler())
+	t.Cleanup(http.Close)
+	uri, err := url.Parse(strings.Replace(http.URL, "http://", "relay+ws://", 1) + "/relay")
+	require.NoError(t, err)
+	return uri
+}
+
+func TestRelay(t *testing.T) {
+	for name, listen := range map[string]func(*testing.T, *Server) *url.URL{"tcp": listen, "websocket": listenWebSocket} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			card := virtual.New()
+			uri := listen(t, NewServer(card, nil))
+
+			for range 2 {
+				client, err := lpa.New(&lpa.Options{Channel: New(uri)})
+				require.NoError(t, err)
+				eid, err := client.EID(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, card.EID, eid)
+				profiles, err := client.ListProfile(ctx, nil, nil)
+				require.NoError(t, err)
+				assert.Empty(t, profiles)
+				require.NoError(t, client.Close())
+			}
+		})
+	}
+}
+
+func TestRelay_StatusWordError(t *testing.T) {
+	card := virtual.New()
+	channel := New(listen(t, NewServer(&statusChannel{card}, nil)))
+	require.NoError(t, channel.Connect())
+	defer channel.Disconnect()
+
+	command := []byte{0x80, 0x10, 0x00, 0x00}
+	response, err := channel.Transmit(context.Background(), command)
+	assert.Equal(t, []byte{0x6D, 0x00}, response)
+	var statusErr *apdu.StatusWordError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, uint16(0x6D00), statusErr.SW)
+	assert.Equal(t, command, statusErr.Command)
+
+	_, err = channel.OpenLogicalChannel([]byte{0xA0})
+	assert.EqualError(t, err, "select AID: application not found")
+}
+
+func TestServer_Busy(t *testing.T) {
+	card := virtual.New()
+	uri := listen(t, NewServer(card, &ServerOptions{BusyTimeout: 50 * time.Millisecond}))
+
+	first := New(uri)
+	require.NoError(t, first.Connect())
+	second := New(uri)
+	assert.EqualError(t, second.Connect(), ErrBusy.Error())
+	require.NoError(t, first.Disconnect())
+	require.NoError(t, second.Connect())
+	require.NoError(t, second.Disconnect())
+}
+
+func TestServer_ClientGone(t *testing.T) {
+	card := virtual.New()
+	uri := listen(t, NewServer(card, &ServerOptions{BusyTimeout: time.Second}))
+
+	gone := New(uri)
+	require.NoError(t, gone.Connect())
+	channel, err := gone.OpenLogicalChannel(card.AID)
+	require.NoError(t, err)
+	assert.Equal(t, byte(1), channel)
+	require.NoError(t, gone.conn.Close())
+
+	client := New(uri)
+	require.NoError(t, client.Connect())
+	defer client.Disconnect()
+	channel, err = client.OpenLogicalChannel(card.AID)
+	require.NoError(t, err)
+	assert.Equal(t, byte(1), channel)
+	require.NoError(t, client.CloseLogicalChannel(channel))
+}
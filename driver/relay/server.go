@@ -0,0 +1,201 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"golang.org/x/net/websocket"
+)
+
+// ErrBusy is returned to a client connecting while another client uses the channel, until the busy timeout.
+var ErrBusy = errors.New("relay: the channel is used by another client")
+
+// ServerOptions configure the server.
+type ServerOptions struct {
+	// LockFile is locked while a client is connected, so that the local processes using the channel wait for it,
+	// see driver.LockPath. The channel is not locked when empty.
+	LockFile string
+	// BusyTimeout is how long a client waits for the channel used by another client, it defaults to 30 seconds.
+	BusyTimeout time.Duration
+	// Logger logs the sessions, it defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// Server exposes a channel to the clients of the relay driver, one client at a time.
+type Server struct {
+	channel     apdu.SmartCardChannel
+	lockFile    string
+	busyTimeout time.Duration
+	logger      *slog.Logger
+	// session is held by the connected client.
+	session chan struct{}
+}
+
+// NewServer creates a server relaying the channel, it is connected by the clients.
+func NewServer(channel apdu.SmartCardChannel, opts *ServerOptions) *Server {
+	if opts == nil {
+		opts = new(ServerOptions)
+	}
+	s := &Server{
+		channel:     channel,
+		lockFile:    opts.LockFile,
+		busyTimeout: opts.BusyTimeout,
+		logger:      opts.Logger,
+		session:     make(chan struct{}, 1),
+	}
+	if s.busyTimeout == 0 {
+		s.busyTimeout = 30 * time.Second
+	}
+	if s.logger == nil {
+		s.logger = slog.Default()
+	}
+	return s
+}
+
+// Serve accepts the TCP connections of the relay scheme on the listener, until the listener is closed.
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeConn serves a TCP connection of the relay scheme, and closes it.
+func (s *Server) ServeConn(conn net.Conn) {
+	defer conn.Close()
+	s.serve(context.Background(), conn.RemoteAddr().String(), newLineCodec(conn))
+}
+
+// Handler returns the HTTP handler of the relay+ws and relay+wss schemes.
+// It accepts the WebSocket connections of any origin.
+func (s *Server) Handler() http.Handler {
+	return websocket.Server{Handler: func(conn *websocket.Conn) {
+		request := conn.Request()
+		s.serve(request.Context(), request.RemoteAddr, &webSocketCodec{conn: conn})
+	}}
+}
+
+// session is the state of a connected client.
+type session struct {
+	lock     *driver.FileLock
+	channels map[byte]struct{}
+}
+
+func (s *Server) serve(ctx context.Context, remote string, codec codec) {
+	logger := s.logger.With("remote", remote)
+	var current *session
+	// The channel is disconnected when the client goes away without disconnecting it,
+	// closing the logical channels it left open first.
+	defer func() {
+		if current != nil {
+			for channel := range current.channels {
+				_ = s.channel.CloseLogicalChannel(channel)
+			}
+			s.disconnect(current)
+			logger.Warn("relay: client gone without disconnecting")
+		}
+	}()
+	for {
+		var request Request
+		if err := codec.receive(&request); err != nil {
+			return
+		}
+		response := s.handle(ctx, &current, &request)
+		if response.Error != "" {
+			logger.Debug("relay: request failed", "op", request.Operation, "error", response.Error)
+		}
+		if err := codec.send(response); err != nil {
+			return
+		}
+		if request.Operation == OperationDisconnect && response.Error == "" {
+			logger.Info("relay: client disconnected")
+			return
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, current **session, request *Request) *Response {
+	if request.Operation == OperationConnect {
+		if *current != nil {
+			return newResponse(errors.New("relay: already connected"))
+		}
+		var err error
+		*current, err = s.connect(ctx)
+		return newResponse(err)
+	}
+	if *current == nil {
+		return newResponse(errors.New("relay: not connected"))
+	}
+	switch request.Operation {
+	case OperationDisconnect:
+		err := s.disconnect(*current)
+		*current = nil
+		return newResponse(err)
+	case OperationOpenLogicalChannel:
+		channel, err := s.channel.OpenLogicalChannel(request.AID)
+		if err == nil {
+			(*current).channels[channel] = struct{}{}
+		}
+		response := newResponse(err)
+		response.Channel = channel
+		return response
+	case OperationCloseLogicalChannel:
+		delete((*current).channels, request.Channel)
+		return newResponse(s.channel.CloseLogicalChannel(request.Channel))
+	case OperationTransmit:
+		data, err := s.channel.Transmit(ctx, request.Command)
+		response := newResponse(err)
+		response.Response = data
+		return response
+	}
+	return newResponse(fmt.Errorf("relay: unknown operation %q", request.Operation))
+}
+
+// connect waits for the channel, locks it and connects it.
+func (s *Server) connect(ctx context.Context) (*session, error) {
+	timer := time.NewTimer(s.busyTimeout)
+	defer timer.Stop()
+	select {
+	case s.session <- struct{}{}:
+	case <-timer.C:
+		return nil, ErrBusy
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	current := &session{channels: make(map[byte]struct{})}
+	var err error
+	if s.lockFile != "" {
+		if current.lock, err = driver.Lock(s.lockFile, s.busyTimeout); err != nil {
+			<-s.session
+			return nil, err
+		}
+	}
+	if err = s.channel.Connect(); err != nil {
+		s.release(current)
+		return nil, err
+	}
+	return current, nil
+}
+
+func (s *Server) disconnect(current *session) error {
+	err := s.channel.Disconnect()
+	s.release(current)
+	return err
+}
+
+func (s *Server) release(current *session) {
+	if current.lock != nil {
+		_ = current.lock.Unlock()
+	}
+	<-s.session
+}
@@ -0,0 +1,321 @@
+// Package replay records the APDU session of a smart card channel and replays it without the card.
+//
+// A session is recorded once against the hardware:
+//
+//	recorder, err := replay.Create(channel, "testdata/session.jsonl")
+//	client, err := lpa.New(&lpa.Options{Channel: recorder})
+//
+// and served back in a unit test:
+//
+//	replayer, err := replay.Open("testdata/session.jsonl")
+//	client, err := lpa.New(&lpa.Options{Channel: replayer})
+//
+// The recording is a JSON Lines file, one Entry per line.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+// ErrDivergence is returned by the replayer when the session differs from the recording.
+var ErrDivergence = errors.New("replay: session diverged from the recording")
+
+type Operation string
+
+const (
+	OperationConnect             Operation = "connect"
+	OperationDisconnect          Operation = "disconnect"
+	OperationOpenLogicalChannel  Operation = "open"
+	OperationCloseLogicalChannel Operation = "close"
+	OperationTransmit            Operation = "transmit"
+)
+
+// Entry is a single call on the channel.
+// Internal entries are the APDUs a driver sends on its own during the preceding operation,
+// they are recorded for reference and skipped on replay.
+type Entry struct {
+	Operation Operation       `json:"op"`
+	Internal  bool            `json:"internal,omitempty"`
+	AID       sgp22.HexString `json:"aid,omitempty"`
+	Channel   byte            `json:"channel,omitempty"`
+	Command   sgp22.HexString `json:"command,omitempty"`
+	Response  sgp22.HexString `json:"response,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+func (e *Entry) err() error {
+	if e.Error == "" {
+		return nil
+	}
+	return errors.New(e.Error)
+}
+
+// region Recorder
+
+// Recorder is an apdu.SmartCardChannel writing every call on the underlying channel to a recording.
+type Recorder struct {
+	channel  apdu.SmartCardChannel
+	encoder  *json.Encoder
+	closer   io.Closer
+	mutex    sync.Mutex
+	observed bool
+	inside   bool
+	internal []*Entry
+	// writeErr is the first error writing the recording, Disconnect returns it.
+	writeErr error
+}
+
+// NewRecorder wraps the channel and writes the recording to w.
+// If the channel implements apdu.ObservableChannel, the APDUs it sends on its own are recorded as well.
+func NewRecorder(channel apdu.SmartCardChannel, w io.Writer) *Recorder {
+	r := &Recorder{channel: channel, encoder: json.NewEncoder(w)}
+	if observable, ok := channel.(apdu.ObservableChannel); ok {
+		r.observed = true
+		observable.SetObserver(r.observe)
+	}
+	return r
+}
+
+// Create wraps the channel and writes the recording to the named file.
+// The file is closed when the channel is disconnected.
+func Create(channel apdu.SmartCardChannel, name string) (*Recorder, error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	r := NewRecorder(channel, f)
+	r.closer = f
+	return r, nil
+}
+
+func (r *Recorder) Connect() error {
+	return r.record(&Entry{Operation: OperationConnect}, r.channel.Connect)
+}
+
+func (r *Recorder) Disconnect() error {
+	err := r.record(&Entry{Operation: OperationDisconnect}, r.channel.Disconnect)
+	r.mutex.Lock()
+	if err == nil && r.writeErr != nil {
+		err = fmt.Errorf("replay: write the recording: %w", r.writeErr)
+	}
+	r.mutex.Unlock()
+	if r.closer != nil {
+		if closeErr := r.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (r *Recorder) OpenLogicalChannel(AID []byte) (channel byte, err error) {
+	entry := &Entry{Operation: OperationOpenLogicalChannel, AID: AID}
+	err = r.record(entry, func() error {
+		channel, err = r.channel.OpenLogicalChannel(AID)
+		entry.Channel = channel
+		return err
+	})
+	return
+}
+
+func (r *Recorder) CloseLogicalChannel(channel byte) error {
+	return r.record(&Entry{Operation: OperationCloseLogicalChannel, Channel: channel}, func() error {
+		return r.channel.CloseLogicalChannel(channel)
+	})
+}
+
+func (r *Recorder) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	response, err := r.channel.Transmit(ctx, command)
+	if !r.observed {
+		r.write(transmitEntry(command, response, err))
+	}
+	return response, err
+}
+
+// record calls the operation and writes its entry followed by the APDUs observed meanwhile.
+func (r *Recorder) record(entry *Entry, call func() error) error {
+	r.mutex.Lock()
+	r.inside = true
+	r.mutex.Unlock()
+	err := call()
+	r.mutex.Lock()
+	internal := r.internal
+	r.inside, r.internal = false, nil
+	r.mutex.Unlock()
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	r.write(entry)
+	for _, e := range internal {
+		r.write(e)
+	}
+	return err
+}
+
+func (r *Recorder) observe(command, response []byte, err error) {
+	entry := transmitEntry(command, response, err)
+	r.mutex.Lock()
+	if r.inside {
+		entry.Internal = true
+		r.internal = append(r.internal, entry)
+		r.mutex.Unlock()
+		return
+	}
+	r.mutex.Unlock()
+	r.write(entry)
+}
+
+func (r *Recorder) write(entry *Entry) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.encoder.Encode(entry); err != nil && r.writeErr == nil {
+		r.writeErr = err
+	}
+}
+
+func transmitEntry(command, response []byte, err error) *Entry {
+	entry := &Entry{
+		Operation: OperationTransmit,
+		Command:   bytes.Clone(command),
+		Response:  bytes.Clone(response),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	return entry
+}
+
+// endregion
+
+// region Replayer
+
+// Replayer is an apdu.SmartCardChannel serving the responses of a recording.
+// Every call must match the next recorded entry, otherwise ErrDivergence is returned.
+type Replayer struct {
+	entries []*Entry
+	index   int
+	mutex   sync.Mutex
+}
+
+// NewReplayer reads the recording from r.
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	var entries []*Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		entry := new(Entry)
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, fmt.Errorf("replay: line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &Replayer{entries: entries}, nil
+}
+
+// Open reads the recording from the named file.
+func Open(name string) (*Replayer, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewReplayer(f)
+}
+
+func (r *Replayer) Connect() error {
+	entry, err := r.next(OperationConnect)
+	if err != nil {
+		return err
+	}
+	return entry.err()
+}
+
+func (r *Replayer) Disconnect() error {
+	entry, err := r.next(OperationDisconnect)
+	if err != nil {
+		return err
+	}
+	return entry.err()
+}
+
+func (r *Replayer) OpenLogicalChannel(AID []byte) (byte, error) {
+	entry, err := r.next(OperationOpenLogicalChannel)
+	if err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(entry.AID, AID) {
+		return 0, fmt.Errorf("%w: expected AID %X, got %X", ErrDivergence, []byte(entry.AID), AID)
+	}
+	return entry.Channel, entry.err()
+}
+
+func (r *Replayer) CloseLogicalChannel(channel byte) error {
+	entry, err := r.next(OperationCloseLogicalChannel)
+	if err != nil {
+		return err
+	}
+	if entry.Channel != channel {
+		return fmt.Errorf("%w: expected channel %d, got %d", ErrDivergence, entry.Channel, channel)
+	}
+	return entry.err()
+}
+
+func (r *Replayer) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	entry, err := r.next(OperationTransmit)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(entry.Command, command) {
+		return nil, fmt.Errorf("%w: expected command %X, got %X", ErrDivergence, []byte(entry.Command), command)
+	}
+	return bytes.Clone(entry.Response), entry.err()
+}
+
+// Done returns an error if the recording has entries which were not replayed.
+func (r *Replayer) Done() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, entry := range r.entries[r.index:] {
+		if !entry.Internal {
+			return fmt.Errorf("%w: %d entries were not replayed", ErrDivergence, len(r.entries)-r.index)
+		}
+	}
+	return nil
+}
+
+// next returns the next entry which is not internal, and checks its operation.
+func (r *Replayer) next(operation Operation) (*Entry, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for ; r.index < len(r.entries); r.index++ {
+		if entry := r.entries[r.index]; !entry.Internal {
+			r.index++
+			if entry.Operation != operation {
+				return nil, fmt.Errorf("%w: expected %s, got %s", ErrDivergence, entry.Operation, operation)
+			}
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unexpected %s after the end of the recording", ErrDivergence, operation)
+}
+
+// endregion
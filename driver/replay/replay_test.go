@@ -0,0 +1,131 @@
+package replay_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver/replay"
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func record(t *testing.T, session func(client *lpa.Client)) []byte {
+	card := virtual.New()
+	iccid, err := sgp22.NewICCID("8944476500001224158")
+	require.NoError(t, err)
+	card.AddProfile(&virtual.Profile{
+		ICCID:       iccid,
+		State:       sgp22.ProfileEnabled,
+		ProfileName: "Profile A",
+		Class:       sgp22.ProfileClassOperational,
+	})
+	var recording bytes.Buffer
+	client, err := lpa.New(&lpa.Options{Channel: replay.NewRecorder(card, &recording)})
+	require.NoError(t, err)
+	session(client)
+	require.NoError(t, client.Close())
+	return recording.Bytes()
+}
+
+func TestReplayer(t *testing.T) {
+	ctx := context.Background()
+	session := func(client *lpa.Client) {
+		profiles, err := client.ListProfile(ctx, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, profiles, 1)
+		assert.Equal(t, "Profile A", profiles[0].ProfileName)
+		eid, err := client.EID(ctx)
+		require.NoError(t, err)
+		assert.Len(t, eid, 16)
+	}
+	recording := record(t, session)
+
+	replayer, err := replay.NewReplayer(bytes.NewReader(recording))
+	require.NoError(t, err)
+	client, err := lpa.New(&lpa.Options{Channel: replayer})
+	require.NoError(t, err)
+	session(client)
+	require.NoError(t, client.Close())
+	assert.NoError(t, replayer.Done())
+}
+
+func TestReplayer_Divergence(t *testing.T) {
+	ctx := context.Background()
+	recording := record(t, func(client *lpa.Client) {
+		_, err := client.EID(ctx)
+		require.NoError(t, err)
+	})
+
+	replayer, err := replay.NewReplayer(bytes.NewReader(recording))
+	require.NoError(t, err)
+	client, err := lpa.New(&lpa.Options{Channel: replayer})
+	require.NoError(t, err)
+	_, err = client.ListProfile(ctx, nil, nil)
+	assert.ErrorIs(t, err, replay.ErrDivergence)
+	assert.ErrorIs(t, replayer.Done(), replay.ErrDivergence)
+}
+
+func TestRecorder_WriteError(t *testing.T) {
+	recorder := replay.NewRecorder(virtual.New(), failingWriter{})
+	require.NoError(t, recorder.Connect())
+	assert.ErrorIs(t, recorder.Disconnect(), errWrite)
+}
+
+var errWrite = errors.New("disk full")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWrite }
+
+// observable is a channel sending the terminal capability on its own when connecting.
+type observable struct {
+	apdu.SmartCardChannel
+	observer apdu.Observer
+}
+
+func (o *observable) SetObserver(observer apdu.Observer) { o.observer = observer }
+
+func (o *observable) Connect() error {
+	o.observer([]byte{0x80, 0xAA, 0x00, 0x00, 0x0A, 0xA9, 0x08, 0x81, 0x00, 0x82, 0x01, 0x01, 0x83, 0x01, 0x07}, []byte{0x90, 0x00}, nil)
+	return o.SmartCardChannel.Connect()
+}
+
+func (o *observable) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	response, err := o.SmartCardChannel.Transmit(ctx, command)
+	o.observer(command, response, err)
+	return response, err
+}
+
+func TestRecorder_Internal(t *testing.T) {
+	ctx := context.Background()
+	var recording bytes.Buffer
+	client, err := lpa.New(&lpa.Options{
+		Channel: replay.NewRecorder(&observable{SmartCardChannel: virtual.New()}, &recording),
+	})
+	require.NoError(t, err)
+	_, err = client.EID(ctx)
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	lines := strings.Split(strings.TrimSpace(recording.String()), "\n")
+	require.Len(t, lines, 7)
+	assert.JSONEq(t, `{"op":"connect"}`, lines[0])
+	assert.JSONEq(t, `{"op":"transmit","internal":true,"command":"80AA00000AA9088100820101830107","response":"9000"}`, lines[1])
+	assert.Contains(t, lines[3], `"op":"transmit","command":"81E2910006BF3E035C015A"`)
+
+	replayer, err := replay.NewReplayer(&recording)
+	require.NoError(t, err)
+	client, err = lpa.New(&lpa.Options{Channel: replayer})
+	require.NoError(t, err)
+	_, err = client.EID(ctx)
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+	assert.NoError(t, replayer.Done())
+}
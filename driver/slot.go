@@ -0,0 +1,108 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+)
+
+// SlotStatus is the status of a physical SIM slot of a modem.
+type SlotStatus struct {
+	// Slot is the physical slot, starting at 1.
+	Slot uint8
+	// LogicalSlot is the logical slot the physical slot is mapped to, starting at 1, or 0 when the slot is not mapped.
+	LogicalSlot uint8
+	// Active is set when the slot is mapped and powered on by the modem.
+	Active bool
+	// CardPresent is set when a card is inserted in the slot.
+	CardPresent bool
+	// EUICC is set when the modem reports the card as an eUICC.
+	EUICC bool
+	// ICCID and EID are the digits of the identifiers of the card, empty when the modem does not report them.
+	ICCID string
+	EID   string
+}
+
+// SlotSwitcher is implemented by the channels of modems with several SIM slots.
+//
+// Connect maps the slot of the channel to its logical slot, as SwitchSlot does.
+// The slots can be listed and switched before Connect, without switching the slot of the channel.
+type SlotSwitcher interface {
+	apdu.SmartCardChannel
+	// Slots returns the status of the physical slots of the modem.
+	Slots(ctx context.Context) ([]SlotStatus, error)
+	// SwitchSlot maps the physical slot to the logical slot and waits for the card to be ready.
+	SwitchSlot(ctx context.Context, slot, logicalSlot uint8) error
+}
+
+// LogicalSlot returns the logical query parameter of the URI, the logical slot the slot of the channel is mapped to.
+// It is 1 when it is not set.
+func LogicalSlot(uri *url.URL) (uint8, error) {
+	value := uri.Query().Get("logical")
+	if value == "" {
+		return 1, nil
+	}
+	slot, err := strconv.ParseUint(value, 10, 8)
+	if err != nil || slot == 0 {
+		return 0, fmt.Errorf("driver: invalid logical slot %q", value)
+	}
+	return uint8(slot), nil
+}
+
+// RestoreSlot returns the restore query parameter of the URI,
+// set when the slot mapped before Connect is mapped back on Disconnect.
+func RestoreSlot(uri *url.URL) (bool, error) {
+	value := uri.Query().Get("restore")
+	if value == "" {
+		return false, nil
+	}
+	restore, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("driver: invalid restore %q", value)
+	}
+	return restore, nil
+}
+
+// slotsTimeout bounds the time SlotURIs waits for the slots of a device.
+const slotsTimeout = 10 * time.Second
+
+// SlotURIs returns the URI of every slot holding a card of every device, for enumerators of modems with several slots.
+// The slots are listed by the channel open returns for the device, which is disconnected without being connected.
+// A device whose channel cannot be opened or list its slots only gets the URI of slot 1.
+func SlotURIs(scheme string, devices []string, open func(device string) (apdu.SmartCardChannel, error)) []*url.URL {
+	var uris []*url.URL
+	for _, device := range devices {
+		uris = append(uris, URIs(scheme, []string{device}, cardSlots(device, open)...)...)
+	}
+	return uris
+}
+
+// cardSlots returns the slots holding a card of the device, or slot 1 when they cannot be listed.
+func cardSlots(device string, open func(device string) (apdu.SmartCardChannel, error)) []uint8 {
+	channel, err := open(device)
+	if err != nil {
+		return []uint8{1}
+	}
+	defer channel.Disconnect()
+	switcher, ok := channel.(SlotSwitcher)
+	if !ok {
+		return []uint8{1}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), slotsTimeout)
+	defer cancel()
+	status, err := switcher.Slots(ctx)
+	if err != nil {
+		return []uint8{1}
+	}
+	var slots []uint8
+	for _, slot := range status {
+		if slot.CardPresent {
+			slots = append(slots, slot.Slot)
+		}
+	}
+	return slots
+}
@@ -0,0 +1,100 @@
+package driver_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogicalSlot(t *testing.T) {
+	tests := map[string]uint8{
+		"qmi:///dev/cdc-wdm0?slot=2":           1,
+		"qmi:///dev/cdc-wdm0?slot=1&logical=2": 2,
+		"qrtr://?logical=0":                    0,
+		"qrtr://?logical=x":                    0,
+	}
+	for uri, expected := range tests {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		slot, err := driver.LogicalSlot(u)
+		if expected == 0 {
+			assert.Error(t, err, uri)
+			continue
+		}
+		require.NoError(t, err, uri)
+		assert.Equal(t, expected, slot, uri)
+	}
+}
+
+func TestRestoreSlot(t *testing.T) {
+	tests := map[string]bool{
+		"qmi:///dev/cdc-wdm0?slot=2":              false,
+		"qmi:///dev/cdc-wdm0?slot=2&restore=true": true,
+		"mbim:///dev/cdc-wdm0?restore=0":          false,
+	}
+	for uri, expected := range tests {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		restore, err := driver.RestoreSlot(u)
+		require.NoError(t, err, uri)
+		assert.Equal(t, expected, restore, uri)
+	}
+	_, err := driver.RestoreSlot(&url.URL{RawQuery: "restore=maybe"})
+	assert.EqualError(t, err, `driver: invalid restore "maybe"`)
+}
+
+// switcher is a modem channel listing its slots.
+type switcher struct {
+	*virtual.EUICC
+	slots        []driver.SlotStatus
+	disconnected bool
+}
+
+func (s *switcher) Slots(context.Context) ([]driver.SlotStatus, error) {
+	if s.slots == nil {
+		return nil, errors.New("slot status not supported")
+	}
+	return s.slots, nil
+}
+
+func (s *switcher) SwitchSlot(context.Context, uint8, uint8) error {
+	return nil
+}
+
+func (s *switcher) Disconnect() error {
+	s.disconnected = true
+	return nil
+}
+
+func TestSlotURIs(t *testing.T) {
+	channels := map[string]*switcher{
+		"/dev/cdc-wdm0": {EUICC: virtual.New(), slots: []driver.SlotStatus{{Slot: 1}, {Slot: 2, CardPresent: true}, {Slot: 3, CardPresent: true}}},
+		"/dev/cdc-wdm1": {EUICC: virtual.New()},
+	}
+	uris := driver.SlotURIs("qmi", []string{"/dev/cdc-wdm0", "/dev/cdc-wdm1", "/dev/cdc-wdm2"}, func(device string) (apdu.SmartCardChannel, error) {
+		if channel, ok := channels[device]; ok {
+			return channel, nil
+		}
+		return nil, errors.New("no such device")
+	})
+	var actual []string
+	for _, uri := range uris {
+		actual = append(actual, uri.String())
+	}
+	assert.Equal(t, []string{
+		"qmi:///dev/cdc-wdm0?slot=2",
+		"qmi:///dev/cdc-wdm0?slot=3",
+		"qmi:///dev/cdc-wdm1?slot=1",
+		"qmi:///dev/cdc-wdm2?slot=1",
+	}, actual)
+	for device, channel := range channels {
+		assert.True(t, channel.disconnected, device)
+	}
+}
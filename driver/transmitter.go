@@ -1,8 +1,8 @@
 package driver
 
 import (
+	"context"
 	"fmt"
-	"io"
 	"log/slog"
 
 	"github.com/KilimcininKorOglu/euicc-go/apdu"
@@ -12,11 +12,13 @@ import (
 
 type Transmitter interface {
 	sgp22.Transmitter
+	// Exclusive calls fn while no command is being transmitted.
+	Exclusive(fn func() error) error
 	Close() error
 }
 
 type transmitter struct {
-	card   io.ReadWriteCloser
+	card   *apdu.Transmitter
 	logger *slog.Logger
 }
 
@@ -28,12 +30,12 @@ func NewTransmitter(logger *slog.Logger, channel apdu.SmartCardChannel, AID []by
 	return &transmitter{card: t, logger: logger}, nil
 }
 
-func (t *transmitter) Transmit(request bertlv.Marshaler, response bertlv.Unmarshaler) error {
+func (t *transmitter) Transmit(ctx context.Context, request bertlv.Marshaler, response bertlv.Unmarshaler) error {
 	req, err := request.MarshalBERTLV()
 	if err != nil {
 		return err
 	}
-	bs, err := t.TransmitRaw(req.Bytes())
+	bs, err := t.TransmitRaw(ctx, req.Bytes())
 	if err != nil {
 		return err
 	}
@@ -44,12 +46,9 @@ func (t *transmitter) Transmit(request bertlv.Marshaler, response bertlv.Unmarsh
 	return response.UnmarshalBERTLV(&tlv)
 }
 
-func (t *transmitter) TransmitRaw(command []byte) ([]byte, error) {
+func (t *transmitter) TransmitRaw(ctx context.Context, command []byte) ([]byte, error) {
 	t.logger.Debug("[APDU] sending", "command", fmt.Sprintf("%X", command))
-	if _, err := t.card.Write(command); err != nil {
-		return nil, err
-	}
-	bs, err := io.ReadAll(t.card)
+	bs, err := t.card.Transmit(ctx, command)
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +56,10 @@ func (t *transmitter) TransmitRaw(command []byte) ([]byte, error) {
 	return bs, err
 }
 
+func (t *transmitter) Exclusive(fn func() error) error {
+	return t.card.Exclusive(fn)
+}
+
 func (t *transmitter) Close() error {
 	return t.card.Close()
 }
@@ -0,0 +1,76 @@
+package virtual
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/bertlv"
+)
+
+// generateCertificates creates a self-signed EUM certificate and an eUICC certificate issued by it.
+// The chain is not signed by a GSMA CI, it only allows a peer to verify the eUICC signatures.
+func (e *EUICC) generateCertificates() error {
+	eumKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	if e.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		return err
+	}
+	notBefore := time.Now().Add(-time.Hour)
+	eum := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"euicc-go"}, CommonName: "Virtual EUM"},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	if e.eumCertificate, err = x509.CreateCertificate(rand.Reader, eum, eum, &eumKey.PublicKey, eumKey); err != nil {
+		return err
+	}
+	if eum, err = x509.ParseCertificate(e.eumCertificate); err != nil {
+		return err
+	}
+	euicc := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{Organization: []string{"euicc-go"}, CommonName: "Virtual eUICC"},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.AddDate(10, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	e.certificate, err = x509.CreateCertificate(rand.Reader, euicc, eum, &e.key.PublicKey, eumKey)
+	return err
+}
+
+// sign signs the DER encoding of the data objects and returns the euiccSignature TLV.
+// The signature is the concatenation of r and s as required by SGP.22.
+func (e *EUICC) sign(objects ...*bertlv.TLV) *bertlv.TLV {
+	hashed := sha256.New()
+	for _, object := range objects {
+		hashed.Write(object.Bytes())
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, e.key, hashed.Sum(nil))
+	if err != nil {
+		panic(err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return bertlv.NewValue(bertlv.Application.Primitive(55), signature)
+}
+
+func (e *EUICC) certificateTLV(der []byte) *bertlv.TLV {
+	var tlv bertlv.TLV
+	if err := tlv.UnmarshalBinary(der); err != nil {
+		panic(err)
+	}
+	return &tlv
+}
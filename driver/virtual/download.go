@@ -0,0 +1,416 @@
+package virtual
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/KilimcininKorOglu/euicc-go/bertlv"
+	"github.com/KilimcininKorOglu/euicc-go/bertlv/primitive"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+// session is the RSP session opened by ES10b.AuthenticateServer.
+type session struct {
+	transactionID []byte
+	serverAddress string
+	matchingID    []byte
+	prepared      bool
+}
+
+// boundProfilePackage accumulates the segments of a Bound Profile Package until it is complete.
+type boundProfilePackage struct {
+	data      bytes.Buffer
+	remaining int
+}
+
+// region Section 5.7.13, ES10b.AuthenticateServer
+
+// authenticateServer opens a new RSP session.
+// The server signature is not verified, the virtual eUICC has no GSMA CI to check it against.
+//
+// See https://aka.pw/sgp22/v2.5#page=195 (Section 5.7.13, ES10b.AuthenticateServer)
+func (e *EUICC) authenticateServer(request *bertlv.TLV) *bertlv.TLV {
+	signed1 := request.First(bertlv.Universal.Constructed(16))
+	if signed1 == nil {
+		return authenticateServerError(nil, 127)
+	}
+	transactionID := signed1.First(bertlv.ContextSpecific.Primitive(0))
+	if transactionID == nil {
+		return authenticateServerError(nil, 127)
+	}
+	if challenge := signed1.First(bertlv.ContextSpecific.Primitive(1)); challenge == nil || !bytes.Equal(challenge.Value, e.challenge) {
+		return authenticateServerError(transactionID.Value, 6)
+	}
+	serverAddress := signed1.First(bertlv.ContextSpecific.Primitive(3))
+	ciPKID := signed1.First(bertlv.ContextSpecific.Primitive(4))
+	ctxParams1 := request.First(bertlv.ContextSpecific.Constructed(0))
+	if serverAddress == nil || ciPKID == nil || ctxParams1 == nil {
+		return authenticateServerError(transactionID.Value, 127)
+	}
+	e.challenge = nil
+	e.session = &session{
+		transactionID: transactionID.Value,
+		serverAddress: string(serverAddress.Value),
+	}
+	if matchingID := ctxParams1.First(bertlv.ContextSpecific.Primitive(0)); matchingID != nil {
+		e.session.matchingID = matchingID.Value
+	}
+	euiccSigned1 := bertlv.NewChildren(
+		bertlv.Universal.Constructed(16),
+		transactionID,
+		serverAddress,
+		ciPKID,
+		e.euiccInfo2(),
+		ctxParams1,
+	)
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(56),
+		bertlv.NewChildren(
+			bertlv.ContextSpecific.Constructed(0),
+			euiccSigned1,
+			e.sign(euiccSigned1),
+			e.certificateTLV(e.certificate),
+			e.certificateTLV(e.eumCertificate),
+		),
+	)
+}
+
+func authenticateServerError(transactionID []byte, code int8) *bertlv.TLV {
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(56),
+		bertlv.NewChildrenIter(bertlv.ContextSpecific.Constructed(1), func(yield func(*bertlv.TLV) bool) {
+			if transactionID != nil && !yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), transactionID)) {
+				return
+			}
+			yield(mustMarshalValue(bertlv.Universal.Primitive(2), primitive.MarshalInt(code)))
+		}),
+	)
+}
+
+// endregion
+
+// region Section 5.7.5, ES10b.PrepareDownload
+
+// prepareDownload generates the one-time key pair of the session.
+//
+// See https://aka.pw/sgp22/v2.5#page=184 (Section 5.7.5, ES10b.PrepareDownload)
+func (e *EUICC) prepareDownload(request *bertlv.TLV) *bertlv.TLV {
+	signed2 := request.First(bertlv.Universal.Constructed(16))
+	if signed2 == nil {
+		return prepareDownloadError(nil, 127)
+	}
+	transactionID := signed2.First(bertlv.ContextSpecific.Primitive(0))
+	switch {
+	case transactionID == nil:
+		return prepareDownloadError(nil, 127)
+	case e.session == nil:
+		return prepareDownloadError(transactionID.Value, 4)
+	case !bytes.Equal(transactionID.Value, e.session.transactionID):
+		return prepareDownloadError(transactionID.Value, 5)
+	}
+	var ccRequired bool
+	if flag := signed2.First(bertlv.Universal.Primitive(1)); flag != nil {
+		_ = flag.UnmarshalValue(primitive.UnmarshalBool(&ccRequired))
+	}
+	hashCC := request.First(bertlv.Universal.Primitive(4))
+	if ccRequired && hashCC == nil {
+		return prepareDownloadError(transactionID.Value, 127)
+	}
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return prepareDownloadError(transactionID.Value, 127)
+	}
+	e.session.prepared = true
+	euiccSigned2 := bertlv.NewChildrenIter(bertlv.Universal.Constructed(16), func(yield func(*bertlv.TLV) bool) {
+		if !yield(transactionID) {
+			return
+		}
+		if !yield(bertlv.NewValue(bertlv.Application.Primitive(73), key.PublicKey().Bytes())) {
+			return
+		}
+		if hashCC != nil {
+			yield(hashCC)
+		}
+	})
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(33),
+		bertlv.NewChildren(
+			bertlv.ContextSpecific.Constructed(0),
+			euiccSigned2,
+			e.sign(euiccSigned2),
+		),
+	)
+}
+
+func prepareDownloadError(transactionID []byte, code int8) *bertlv.TLV {
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(33),
+		bertlv.NewChildrenIter(bertlv.ContextSpecific.Constructed(1), func(yield func(*bertlv.TLV) bool) {
+			if transactionID != nil && !yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), transactionID)) {
+				return
+			}
+			yield(mustMarshalValue(bertlv.Universal.Primitive(2), primitive.MarshalInt(code)))
+		}),
+	)
+}
+
+// endregion
+
+// region Section 5.7.6, ES10b.LoadBoundProfilePackage
+
+// loadBoundProfilePackage accumulates the segments of the Bound Profile Package
+// and installs the profile once the last segment is received.
+//
+// See https://aka.pw/sgp22/v2.5#page=186 (Section 5.7.6, ES10b.LoadBoundProfilePackage)
+func (e *EUICC) loadBoundProfilePackage(command []byte) ([]byte, error) {
+	if e.bpp == nil {
+		length, err := boundProfilePackageLength(command)
+		if err != nil {
+			return nil, err
+		}
+		e.bpp = &boundProfilePackage{remaining: length}
+	}
+	e.bpp.data.Write(command)
+	if e.bpp.remaining -= len(command); e.bpp.remaining > 0 {
+		return nil, nil
+	}
+	var bpp bertlv.TLV
+	err := bpp.UnmarshalBinary(e.bpp.data.Bytes())
+	e.bpp = nil
+	if err != nil {
+		return nil, err
+	}
+	return e.install(&bpp).Bytes(), nil
+}
+
+// boundProfilePackageLength returns the number of bytes of the Bound Profile Package
+// not yet received, after the tag and length fields contained in the first segment.
+func boundProfilePackageLength(segment []byte) (int, error) {
+	if len(segment) < 3 {
+		return 0, errors.New("invalid boundProfilePackage")
+	}
+	length, n := int(segment[2]), 1
+	if segment[2] >= 0x80 {
+		n += int(segment[2] & 0x7F)
+		if n == 1 || n > 4 || len(segment) < 2+n {
+			return 0, errors.New("invalid boundProfilePackage length")
+		}
+		length = 0
+		for _, b := range segment[3 : 2+n] {
+			length = length<<8 | int(b)
+		}
+	}
+	return 2 + n + length, nil
+}
+
+// install processes the Bound Profile Package and returns the signed ProfileInstallationResult.
+// The SCP03t protection is not verified, the StoreMetadata request is read from the '88' TLVs.
+//
+// See https://aka.pw/sgp22/v2.5#page=35 (Section 2.5.6, ProfileInstallationResult)
+func (e *EUICC) install(bpp *bertlv.TLV) *bertlv.TLV {
+	if e.session == nil || !e.session.prepared {
+		return e.installationResult(nil, nil, installationError(0, 3))
+	}
+	session := e.session
+	e.session = nil
+	if err := sgp22.ValidBoundProfilePackage(bpp); err != nil {
+		return e.installationResult(session, nil, installationError(0, 1))
+	}
+	request := bpp.First(bertlv.ContextSpecific.Constructed(35))
+	if transactionID := request.First(bertlv.ContextSpecific.Primitive(0)); transactionID == nil || !bytes.Equal(transactionID.Value, session.transactionID) {
+		return e.installationResult(session, nil, installationError(0, 3))
+	}
+	profile, err := storeMetadata(bpp.First(bertlv.ContextSpecific.Constructed(1)))
+	if err != nil {
+		return e.installationResult(session, nil, installationError(2, 1))
+	}
+	if e.Profile(profile.ICCID) != nil {
+		return e.installationResult(session, profile, installationError(2, 9))
+	}
+	for _, element := range bpp.First(bertlv.ContextSpecific.Constructed(3)).Children {
+		profile.size += len(element.Value)
+	}
+	if uint32(profile.size) > e.FreeNonVolatileMemory {
+		return e.installationResult(session, profile, installationError(5, 10))
+	}
+	e.FreeNonVolatileMemory -= uint32(profile.size)
+	profile.ISDPAID = e.allocateISDPAID()
+	e.Profiles = append(e.Profiles, profile)
+	return e.installationResult(session, profile, bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(0),
+		bertlv.NewValue(sgp22.TagISDPAID, profile.ISDPAID),
+		bertlv.NewValue(bertlv.Universal.Primitive(4), []byte{0x30, 0x00}),
+	))
+}
+
+// storeMetadata decodes the StoreMetadata request carried by the '88' TLVs,
+// each of them ends with the 8 bytes MAC of the SCP03t protection.
+func storeMetadata(sequenceOf88 *bertlv.TLV) (*Profile, error) {
+	var data []byte
+	for _, child := range sequenceOf88.Children {
+		if len(child.Value) < 8 {
+			return nil, errors.New("invalid storeMetadata")
+		}
+		data = append(data, child.Value[:len(child.Value)-8]...)
+	}
+	var metadata bertlv.TLV
+	if err := metadata.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	var info sgp22.ProfileInfo
+	if err := info.UnmarshalBERTLV(&metadata); err != nil {
+		return nil, err
+	}
+	profile := &Profile{
+		ICCID:                         info.ICCID,
+		State:                         sgp22.ProfileDisabled,
+		ServiceProviderName:           info.ServiceProviderName,
+		ProfileName:                   info.ProfileName,
+		Icon:                          info.Icon,
+		Class:                         sgp22.ProfileClassOperational,
+		NotificationConfigurationInfo: info.NotificationConfigurationInfo,
+	}
+	if metadata.First(sgp22.TagProfileClass) != nil {
+		profile.Class = info.ProfileClass
+	}
+	if len(info.ProfileOwner.PLMN) > 0 {
+		profile.Owner = &info.ProfileOwner
+	}
+	if rules := metadata.First(sgp22.TagProfilePolicyRules); rules != nil {
+		var bits []bool
+		if err := rules.UnmarshalValue(primitive.UnmarshalBitString(&bits)); err != nil {
+			return nil, err
+		}
+		bits = append(bits, make([]bool, 3)...)
+		profile.DisableNotAllowed, profile.DeleteNotAllowed = bits[1], bits[2]
+	}
+	return profile, nil
+}
+
+func installationError(commandID, reason byte) *bertlv.TLV {
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(1),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), []byte{commandID}),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(1), []byte{reason}),
+	)
+}
+
+// installationResult signs the ProfileInstallationResult and queues it as the install notification.
+func (e *EUICC) installationResult(session *session, profile *Profile, finalResult *bertlv.TLV) *bertlv.TLV {
+	notification := &Notification{
+		NotificationMetadata: sgp22.NotificationMetadata{
+			ProfileManagementOperation: sgp22.NotificationEventInstall,
+		},
+	}
+	var transactionID []byte
+	if session != nil {
+		transactionID = session.transactionID
+		notification.Address = session.serverAddress
+	}
+	if profile != nil {
+		notification.ICCID = profile.ICCID
+		if address, ok := profile.notificationAddress(sgp22.NotificationEventInstall); ok {
+			notification.Address = address
+		}
+	}
+	e.sequenceNumber++
+	notification.SequenceNumber = e.sequenceNumber
+	data := bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(39),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), transactionID),
+		notification.metadata(),
+		e.smdpOID(),
+		bertlv.NewChildren(bertlv.ContextSpecific.Constructed(2), finalResult),
+	)
+	notification.PendingNotification = bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(55),
+		data,
+		e.sign(data),
+	)
+	if session != nil {
+		e.Notifications = append(e.Notifications, notification)
+	}
+	return notification.PendingNotification
+}
+
+// endregion
+
+// region Section 5.7.14, ES10b.CancelSession
+
+// cancelSession closes the RSP session and returns the signed cancel session response.
+//
+// See https://aka.pw/sgp22/v2.5#page=197 (Section 5.7.14, ES10b.CancelSession)
+func (e *EUICC) cancelSession(request *bertlv.TLV) *bertlv.TLV {
+	transactionID := request.First(bertlv.ContextSpecific.Primitive(0))
+	reason := request.First(bertlv.ContextSpecific.Primitive(1))
+	switch {
+	case transactionID == nil || reason == nil:
+		return cancelSessionError(127)
+	case e.session == nil || !bytes.Equal(transactionID.Value, e.session.transactionID):
+		return cancelSessionError(5)
+	}
+	e.session = nil
+	e.bpp = nil
+	signed := bertlv.NewChildren(
+		bertlv.Universal.Constructed(16),
+		transactionID,
+		e.smdpOID(),
+		reason,
+	)
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(65),
+		bertlv.NewChildren(
+			bertlv.ContextSpecific.Constructed(0),
+			signed,
+			e.sign(signed),
+		),
+	)
+}
+
+func cancelSessionError(code int8) *bertlv.TLV {
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(65),
+		mustMarshalValue(bertlv.ContextSpecific.Primitive(1), primitive.MarshalInt(code)),
+	)
+}
+
+// endregion
+
+// errInvalidSMDPOID is returned for the commands signing their results when SMDPOID is not an OID, they are answered with 6F00.
+var errInvalidSMDPOID = errors.New("virtual: invalid SM-DP+ OID")
+
+// encodeSMDPOID encodes SMDPOID as an OBJECT IDENTIFIER.
+func (e *EUICC) encodeSMDPOID() (*bertlv.TLV, error) {
+	var oid asn1.ObjectIdentifier
+	for _, arc := range strings.Split(e.SMDPOID, ".") {
+		n, err := strconv.Atoi(arc)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w %q", errInvalidSMDPOID, e.SMDPOID)
+		}
+		oid = append(oid, n)
+	}
+	der, err := asn1.Marshal(oid)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", errInvalidSMDPOID, e.SMDPOID, err)
+	}
+	var tlv bertlv.TLV
+	if err = tlv.UnmarshalBinary(der); err != nil {
+		return nil, err
+	}
+	return &tlv, nil
+}
+
+// smdpOID encodes SMDPOID as an OBJECT IDENTIFIER, handle checks that it is valid before the commands using it.
+func (e *EUICC) smdpOID() *bertlv.TLV {
+	tlv, err := e.encodeSMDPOID()
+	if err != nil {
+		panic(err)
+	}
+	return tlv
+}
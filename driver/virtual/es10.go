@@ -0,0 +1,463 @@
+package virtual
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"slices"
+
+	"github.com/KilimcininKorOglu/euicc-go/bertlv"
+	"github.com/KilimcininKorOglu/euicc-go/bertlv/primitive"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+// handle dispatches a complete ES10 command to its handler and returns the encoded response.
+func (e *EUICC) handle(command []byte) ([]byte, error) {
+	// The results of LoadBoundProfilePackage and CancelSession carry SMDPOID, which is set by the caller.
+	if e.bpp != nil || bytes.HasPrefix(command, []byte{0xBF, 0x36}) || bytes.HasPrefix(command, []byte{0xBF, 0x41}) {
+		if _, err := e.encodeSMDPOID(); err != nil {
+			return nil, err
+		}
+	}
+	if e.bpp != nil || bytes.HasPrefix(command, []byte{0xBF, 0x36}) {
+		return e.loadBoundProfilePackage(command)
+	}
+	var request bertlv.TLV
+	if err := request.UnmarshalBinary(command); err != nil {
+		return nil, err
+	}
+	if !request.Tag.ContextSpecific() || !request.Tag.Constructed() {
+		return nil, sgp22.ErrUnexpectedTag
+	}
+	var response *bertlv.TLV
+	switch request.Tag.Value() {
+	case 32, 34:
+		response = e.euiccInfo(&request)
+	case 33:
+		response = e.prepareDownload(&request)
+	case 40:
+		response = e.listNotification(&request)
+	case 41:
+		response = e.setNickname(&request)
+	case 43:
+		response = e.retrieveNotificationsList(&request)
+	case 45:
+		response = e.profilesInfo(&request)
+	case 46:
+		response = e.euiccChallenge()
+	case 48:
+		response = e.removeNotificationFromList(&request)
+	case 49, 50, 51:
+		response = e.profileOperation(&request)
+	case 52:
+		response = e.memoryReset(&request)
+	case 56:
+		response = e.authenticateServer(&request)
+	case 60:
+		response = e.configuredAddresses()
+	case 62:
+		response = e.euiccData()
+	case 63:
+		response = e.setDefaultDPAddress(&request)
+	case 65:
+		response = e.cancelSession(&request)
+	case 67:
+		response = e.rulesAuthorisationTable()
+	default:
+		return nil, errors.New("unsupported command")
+	}
+	return response.Bytes(), nil
+}
+
+// region ES10a
+
+func (e *EUICC) configuredAddresses() *bertlv.TLV {
+	return bertlv.NewChildrenIter(bertlv.ContextSpecific.Constructed(60), func(yield func(*bertlv.TLV) bool) {
+		if e.DefaultSMDPAddress != "" {
+			if !yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), []byte(e.DefaultSMDPAddress))) {
+				return
+			}
+		}
+		yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(1), []byte(e.RootSMDSAddress)))
+	})
+}
+
+func (e *EUICC) setDefaultDPAddress(request *bertlv.TLV) *bertlv.TLV {
+	if address := request.First(bertlv.ContextSpecific.Primitive(0)); address != nil {
+		e.DefaultSMDPAddress = string(address.Value)
+	}
+	return result(63, 0)
+}
+
+// endregion
+
+// region ES10b
+
+func (e *EUICC) euiccChallenge() *bertlv.TLV {
+	e.challenge = make([]byte, 16)
+	_, _ = rand.Read(e.challenge)
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(46),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), e.challenge),
+	)
+}
+
+func (e *EUICC) euiccInfo(request *bertlv.TLV) *bertlv.TLV {
+	if request.Tag.Value() == 32 {
+		return e.euiccInfo1()
+	}
+	return e.euiccInfo2()
+}
+
+func (e *EUICC) euiccInfo1() *bertlv.TLV {
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(32),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(2), []byte{0x02, 0x05, 0x00}),
+		bertlv.NewChildren(bertlv.ContextSpecific.Constructed(9), bertlv.NewValue(bertlv.Universal.Primitive(4), e.CIPKID)),
+		bertlv.NewChildren(bertlv.ContextSpecific.Constructed(10), bertlv.NewValue(bertlv.Universal.Primitive(4), e.CIPKID)),
+	)
+}
+
+func (e *EUICC) euiccInfo2() *bertlv.TLV {
+	resource := slices.Concat(
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(1), mustMarshalInt(int32(len(e.Profiles)))).Bytes(),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(2), mustMarshalInt(int64(e.FreeNonVolatileMemory))).Bytes(),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(3), mustMarshalInt(int64(64*1024))).Bytes(),
+	)
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(34),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(1), []byte{0x02, 0x03, 0x01}),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(2), []byte{0x02, 0x05, 0x00}),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(3), []byte{0x01, 0x00, 0x00}),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(4), resource),
+		mustMarshalValue(bertlv.ContextSpecific.Primitive(5), primitive.MarshalBitString([]bool{false, true, true})),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(6), []byte{0x09, 0x02, 0x00}),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(7), []byte{0x02, 0x03, 0x00}),
+		mustMarshalValue(bertlv.ContextSpecific.Primitive(8), primitive.MarshalBitString([]bool{true, false, false, true})),
+		bertlv.NewChildren(bertlv.ContextSpecific.Constructed(9), bertlv.NewValue(bertlv.Universal.Primitive(4), e.CIPKID)),
+		bertlv.NewChildren(bertlv.ContextSpecific.Constructed(10), bertlv.NewValue(bertlv.Universal.Primitive(4), e.CIPKID)),
+		bertlv.NewValue(bertlv.Universal.Primitive(4), []byte{0x00, 0x01, 0x00}),
+		bertlv.NewValue(bertlv.Universal.Primitive(12), []byte("VIRTUAL-EUICC")),
+	)
+}
+
+func (e *EUICC) listNotification(request *bertlv.TLV) *bertlv.TLV {
+	var filter []bool
+	if events := request.First(bertlv.ContextSpecific.Primitive(1)); events != nil {
+		_ = events.UnmarshalValue(primitive.UnmarshalBitString(&filter))
+	}
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(40),
+		bertlv.NewChildrenIter(bertlv.ContextSpecific.Constructed(0), func(yield func(*bertlv.TLV) bool) {
+			for _, notification := range e.Notifications {
+				event := int(notification.ProfileManagementOperation)
+				if len(filter) > 0 && (event >= len(filter) || !filter[event]) {
+					continue
+				}
+				if !yield(notification.metadata()) {
+					return
+				}
+			}
+		}),
+	)
+}
+
+func (e *EUICC) retrieveNotificationsList(request *bertlv.TLV) *bertlv.TLV {
+	criteria := request.Select(bertlv.ContextSpecific.Constructed(0))
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(43),
+		bertlv.NewChildrenIter(bertlv.ContextSpecific.Constructed(0), func(yield func(*bertlv.TLV) bool) {
+			for _, notification := range e.Notifications {
+				if criteria != nil && len(criteria.Children) > 0 && !notification.match(criteria.At(0)) {
+					continue
+				}
+				if !yield(notification.PendingNotification) {
+					return
+				}
+			}
+		}),
+	)
+}
+
+func (e *EUICC) removeNotificationFromList(request *bertlv.TLV) *bertlv.TLV {
+	tlv := request.First(bertlv.ContextSpecific.Primitive(0))
+	if tlv == nil {
+		return result(48, 127)
+	}
+	var sequenceNumber sgp22.SequenceNumber
+	_ = tlv.UnmarshalValue(primitive.UnmarshalInt(&sequenceNumber))
+	for index, notification := range e.Notifications {
+		if notification.SequenceNumber == sequenceNumber {
+			e.Notifications = slices.Delete(e.Notifications, index, index+1)
+			return result(48, 0)
+		}
+	}
+	return result(48, 1)
+}
+
+func (e *EUICC) rulesAuthorisationTable() *bertlv.TLV {
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(67),
+		bertlv.NewChildren(bertlv.ContextSpecific.Constructed(0)),
+	)
+}
+
+// endregion
+
+// region ES10c
+
+func (e *EUICC) profilesInfo(request *bertlv.TLV) *bertlv.TLV {
+	tags := []bertlv.Tag{
+		sgp22.TagICCID,
+		sgp22.TagISDPAID,
+		sgp22.TagProfileState,
+		sgp22.TagNickname,
+		sgp22.TagServiceProviderName,
+		sgp22.TagProfileName,
+		sgp22.TagProfileIconType,
+		sgp22.TagProfileIcon,
+		sgp22.TagProfileClass,
+		sgp22.TagNotificationConfigurationInfo,
+		sgp22.TagProfileOwner,
+		sgp22.TagProfilePolicyRules,
+	}
+	if tagList := request.First(bertlv.Application.Primitive(28)); tagList != nil {
+		tags = parseTagList(tagList.Value)
+	}
+	var criteria *bertlv.TLV
+	if searchCriteria := request.First(bertlv.ContextSpecific.Constructed(0)); searchCriteria != nil && len(searchCriteria.Children) > 0 {
+		criteria = searchCriteria.At(0)
+	}
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(45),
+		bertlv.NewChildrenIter(bertlv.ContextSpecific.Constructed(0), func(yield func(*bertlv.TLV) bool) {
+			for _, profile := range e.Profiles {
+				if criteria != nil && !profile.match(criteria) {
+					continue
+				}
+				if !yield(profile.marshalProfileInfo(tags)) {
+					return
+				}
+			}
+		}),
+	)
+}
+
+func (p *Profile) match(criteria *bertlv.TLV) bool {
+	switch {
+	case criteria.Tag.Equal(sgp22.TagICCID):
+		return bytes.Equal(p.ICCID, criteria.Value)
+	case criteria.Tag.Equal(sgp22.TagISDPAID):
+		return bytes.Equal(p.ISDPAID, criteria.Value)
+	case criteria.Tag.Equal(sgp22.TagProfileClass):
+		var class sgp22.ProfileClass
+		_ = criteria.UnmarshalValue(primitive.UnmarshalInt(&class))
+		return p.Class == class
+	}
+	return false
+}
+
+// profileOperation enables, disables or deletes a profile.
+//
+// See https://aka.pw/sgp22/v2.5#page=201 (Section 5.7.16, ES10c.EnableProfile)
+//
+// See https://aka.pw/sgp22/v2.5#page=204 (Section 5.7.17, ES10c.DisableProfile)
+//
+// See https://aka.pw/sgp22/v2.5#page=206 (Section 5.7.18, ES10c.DeleteProfile)
+func (e *EUICC) profileOperation(request *bertlv.TLV) *bertlv.TLV {
+	operation := sgp22.ProfileOperation(request.Tag.Value())
+	identifier := request.Select(bertlv.ContextSpecific.Constructed(0))
+	if operation == sgp22.DeleteProfile {
+		identifier = request
+	}
+	var profile *Profile
+	if identifier != nil && len(identifier.Children) > 0 {
+		profile = e.findProfile(identifier.At(0))
+	}
+	return result(uint64(operation), e.applyProfileOperation(operation, profile))
+}
+
+func (e *EUICC) applyProfileOperation(operation sgp22.ProfileOperation, profile *Profile) int8 {
+	switch {
+	case profile == nil:
+		return 1
+	case e.Busy:
+		return 5
+	}
+	switch operation {
+	case sgp22.EnableProfile:
+		if profile.State == sgp22.ProfileEnabled {
+			return 2
+		}
+		if enabled := e.enabledProfile(); enabled != nil {
+			if enabled.DisableNotAllowed {
+				return 3
+			}
+			enabled.State = sgp22.ProfileDisabled
+			e.notify(enabled, sgp22.NotificationEventDisable)
+		}
+		profile.State = sgp22.ProfileEnabled
+		e.notify(profile, sgp22.NotificationEventEnable)
+	case sgp22.DisableProfile:
+		if profile.State != sgp22.ProfileEnabled {
+			return 2
+		}
+		if profile.DisableNotAllowed {
+			return 3
+		}
+		profile.State = sgp22.ProfileDisabled
+		e.notify(profile, sgp22.NotificationEventDisable)
+	case sgp22.DeleteProfile:
+		if profile.State == sgp22.ProfileEnabled {
+			return 2
+		}
+		if profile.DeleteNotAllowed {
+			return 3
+		}
+		e.Profiles = slices.DeleteFunc(e.Profiles, func(p *Profile) bool { return p == profile })
+		e.FreeNonVolatileMemory += uint32(profile.size)
+		e.notify(profile, sgp22.NotificationEventDelete)
+	}
+	return 0
+}
+
+func (e *EUICC) memoryReset(request *bertlv.TLV) *bertlv.TLV {
+	if e.Busy {
+		return result(52, 5)
+	}
+	tlv := request.First(bertlv.Application.Primitive(2))
+	if tlv == nil {
+		return result(52, 127)
+	}
+	var options []bool
+	_ = tlv.UnmarshalValue(primitive.UnmarshalBitString(&options))
+	options = append(options, make([]bool, 3)...)
+	var deleted bool
+	e.Profiles = slices.DeleteFunc(e.Profiles, func(profile *Profile) bool {
+		remove := options[0] && profile.Class == sgp22.ProfileClassOperational ||
+			options[1] && profile.Class == sgp22.ProfileClassTest
+		if remove {
+			e.FreeNonVolatileMemory += uint32(profile.size)
+			deleted = true
+		}
+		return remove
+	})
+	if options[2] && e.DefaultSMDPAddress != "" {
+		e.DefaultSMDPAddress = ""
+		deleted = true
+	}
+	if !deleted {
+		return result(52, 1)
+	}
+	return result(52, 0)
+}
+
+func (e *EUICC) euiccData() *bertlv.TLV {
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(62),
+		bertlv.NewValue(bertlv.Application.Primitive(26), e.EID),
+	)
+}
+
+func (e *EUICC) setNickname(request *bertlv.TLV) *bertlv.TLV {
+	profile := e.findProfile(request.First(sgp22.TagICCID))
+	if profile == nil {
+		return result(41, 1)
+	}
+	profile.Nickname = ""
+	if nickname := request.First(sgp22.TagNickname); nickname != nil {
+		profile.Nickname = string(nickname.Value)
+	}
+	return result(41, 0)
+}
+
+// endregion
+
+// notify records a notification for the event if the profile requests one.
+func (e *EUICC) notify(profile *Profile, event sgp22.NotificationEvent) {
+	address, ok := profile.notificationAddress(event)
+	if !ok {
+		return
+	}
+	e.sequenceNumber++
+	notification := &Notification{
+		NotificationMetadata: sgp22.NotificationMetadata{
+			SequenceNumber:             e.sequenceNumber,
+			ProfileManagementOperation: event,
+			Address:                    address,
+			ICCID:                      profile.ICCID,
+		},
+	}
+	metadata := notification.metadata()
+	notification.PendingNotification = bertlv.NewChildren(
+		bertlv.Universal.Constructed(16),
+		metadata,
+		e.sign(metadata),
+		e.certificateTLV(e.certificate),
+		e.certificateTLV(e.eumCertificate),
+	)
+	e.Notifications = append(e.Notifications, notification)
+}
+
+func (n *Notification) metadata() *bertlv.TLV {
+	return bertlv.NewChildrenIter(bertlv.ContextSpecific.Constructed(47), func(yield func(*bertlv.TLV) bool) {
+		if !yield(mustMarshalValue(bertlv.ContextSpecific.Primitive(0), primitive.MarshalInt(n.SequenceNumber))) {
+			return
+		}
+		if !yield(mustMarshalValue(bertlv.ContextSpecific.Primitive(1), &n.ProfileManagementOperation)) {
+			return
+		}
+		if !yield(bertlv.NewValue(bertlv.Universal.Primitive(12), []byte(n.Address))) {
+			return
+		}
+		if len(n.ICCID) > 0 {
+			yield(bertlv.NewValue(sgp22.TagICCID, n.ICCID))
+		}
+	})
+}
+
+func (n *Notification) match(criteria *bertlv.TLV) bool {
+	switch {
+	case criteria.Tag.Equal(bertlv.ContextSpecific.Primitive(0)):
+		var sequenceNumber sgp22.SequenceNumber
+		_ = criteria.UnmarshalValue(primitive.UnmarshalInt(&sequenceNumber))
+		return n.SequenceNumber == sequenceNumber
+	case criteria.Tag.Equal(bertlv.ContextSpecific.Primitive(1)):
+		var event sgp22.NotificationEvent
+		_ = criteria.UnmarshalValue(&event)
+		return n.ProfileManagementOperation == event
+	}
+	return false
+}
+
+func parseTagList(data []byte) (tags []bertlv.Tag) {
+	reader := bytes.NewReader(data)
+	for reader.Len() > 0 {
+		var tag bertlv.Tag
+		if _, err := tag.ReadFrom(reader); err != nil {
+			break
+		}
+		tags = append(tags, slices.Clone(tag))
+	}
+	return tags
+}
+
+func result(tag uint64, code int8) *bertlv.TLV {
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(tag),
+		mustMarshalValue(bertlv.ContextSpecific.Primitive(0), primitive.MarshalInt(code)),
+	)
+}
+
+func mustMarshalInt[Int int8 | int32 | int64](value Int) []byte {
+	data, _ := primitive.MarshalInt(value).MarshalBinary()
+	return data
+}
+
+func mustMarshalValue(tag bertlv.Tag, value interface{ MarshalBinary() ([]byte, error) }) *bertlv.TLV {
+	tlv, err := bertlv.MarshalValue(tag, value)
+	if err != nil {
+		panic(err)
+	}
+	return tlv
+}
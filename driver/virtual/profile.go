@@ -0,0 +1,196 @@
+package virtual
+
+import (
+	"bytes"
+
+	"github.com/KilimcininKorOglu/euicc-go/bertlv"
+	"github.com/KilimcininKorOglu/euicc-go/bertlv/primitive"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+// Profile is a profile installed on the virtual eUICC.
+type Profile struct {
+	ICCID               sgp22.ICCID
+	ISDPAID             sgp22.ISDPAID
+	State               sgp22.ProfileState
+	Nickname            string
+	ServiceProviderName string
+	ProfileName         string
+	Icon                sgp22.ProfileIcon
+	Class               sgp22.ProfileClass
+	Owner               *sgp22.OperatorId
+	// NotificationConfigurationInfo lists the events for which a notification is generated.
+	NotificationConfigurationInfo sgp22.NotificationConfigurationInfo
+	// DisableNotAllowed reflects PPR1, disabling this profile is not allowed.
+	DisableNotAllowed bool
+	// DeleteNotAllowed reflects PPR2, deleting this profile is not allowed.
+	DeleteNotAllowed bool
+
+	size int
+}
+
+// Notification is a pending notification stored on the virtual eUICC.
+type Notification struct {
+	sgp22.NotificationMetadata
+	// PendingNotification is either a ProfileInstallationResult or an OtherSignedNotification.
+	PendingNotification *bertlv.TLV
+}
+
+// Profile returns the profile identified by the ICCID, or nil if it is not installed.
+func (e *EUICC) Profile(iccid sgp22.ICCID) *Profile {
+	for _, profile := range e.Profiles {
+		if bytes.Equal(profile.ICCID, iccid) {
+			return profile
+		}
+	}
+	return nil
+}
+
+// AddProfile installs a profile without going through the download procedure.
+// The ISD-P AID is allocated when it is not provided.
+func (e *EUICC) AddProfile(profile *Profile) *Profile {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if profile.ISDPAID == nil {
+		profile.ISDPAID = e.allocateISDPAID()
+	}
+	e.Profiles = append(e.Profiles, profile)
+	return profile
+}
+
+func (e *EUICC) allocateISDPAID() sgp22.ISDPAID {
+	aid := sgp22.ISDPAID{0xA0, 0x00, 0x00, 0x05, 0x59, 0x10, 0x10, 0xFF, 0xFF, 0xFF, 0xFF, 0x89, 0x00, 0x00, 0x10, 0x00}
+	for {
+		if !e.hasISDPAID(aid) {
+			return aid
+		}
+		aid[14]++
+	}
+}
+
+func (e *EUICC) hasISDPAID(aid sgp22.ISDPAID) bool {
+	for _, profile := range e.Profiles {
+		if bytes.Equal(profile.ISDPAID, aid) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *EUICC) findProfile(identifier *bertlv.TLV) *Profile {
+	if identifier == nil {
+		return nil
+	}
+	for _, profile := range e.Profiles {
+		switch {
+		case identifier.Tag.Equal(sgp22.TagICCID) && bytes.Equal(profile.ICCID, identifier.Value):
+			return profile
+		case identifier.Tag.Equal(sgp22.TagISDPAID) && bytes.Equal(profile.ISDPAID, identifier.Value):
+			return profile
+		}
+	}
+	return nil
+}
+
+func (e *EUICC) enabledProfile() *Profile {
+	for _, profile := range e.Profiles {
+		if profile.State == sgp22.ProfileEnabled {
+			return profile
+		}
+	}
+	return nil
+}
+
+func (p *Profile) notificationAddress(event sgp22.NotificationEvent) (string, bool) {
+	for _, config := range p.NotificationConfigurationInfo {
+		if config.ProfileManagementOperation == event {
+			return config.Address, true
+		}
+	}
+	return "", false
+}
+
+// marshalProfileInfo encodes the ProfileInfo data objects listed in tags.
+//
+// See https://aka.pw/sgp22/v2.5#page=199 (Section 5.7.15, ES10c.GetProfilesInfo)
+func (p *Profile) marshalProfileInfo(tags []bertlv.Tag) *bertlv.TLV {
+	return bertlv.NewChildrenIter(bertlv.Private.Constructed(3), func(yield func(*bertlv.TLV) bool) {
+		for _, tag := range tags {
+			if child := p.dataObject(tag); child != nil && !yield(child) {
+				return
+			}
+		}
+	})
+}
+
+func (p *Profile) dataObject(tag bertlv.Tag) *bertlv.TLV {
+	switch {
+	case tag.Equal(sgp22.TagICCID):
+		return bertlv.NewValue(tag, p.ICCID)
+	case tag.Equal(sgp22.TagISDPAID):
+		return bertlv.NewValue(tag, p.ISDPAID)
+	case tag.Equal(sgp22.TagProfileState):
+		return mustMarshalValue(tag, primitive.MarshalInt(p.State))
+	case tag.Equal(sgp22.TagNickname):
+		if p.Nickname == "" {
+			return nil
+		}
+		return bertlv.NewValue(tag, []byte(p.Nickname))
+	case tag.Equal(sgp22.TagServiceProviderName):
+		return bertlv.NewValue(tag, []byte(p.ServiceProviderName))
+	case tag.Equal(sgp22.TagProfileName):
+		return bertlv.NewValue(tag, []byte(p.ProfileName))
+	case tag.Equal(sgp22.TagProfileIconType):
+		if !p.Icon.Valid() {
+			return nil
+		}
+		iconType := byte(0x00)
+		if p.Icon.FileType() == "image/png" {
+			iconType = 0x01
+		}
+		return bertlv.NewValue(tag, []byte{iconType})
+	case tag.Equal(sgp22.TagProfileIcon):
+		if len(p.Icon) == 0 {
+			return nil
+		}
+		return bertlv.NewValue(tag, p.Icon)
+	case tag.Equal(sgp22.TagProfileClass):
+		return mustMarshalValue(tag, primitive.MarshalInt(p.Class))
+	case tag.Equal(sgp22.TagNotificationConfigurationInfo):
+		if len(p.NotificationConfigurationInfo) == 0 {
+			return nil
+		}
+		return bertlv.NewChildrenIter(tag, func(yield func(*bertlv.TLV) bool) {
+			for _, config := range p.NotificationConfigurationInfo {
+				if !yield(bertlv.NewChildren(
+					bertlv.Universal.Constructed(16),
+					mustMarshalValue(bertlv.ContextSpecific.Primitive(0), &config.ProfileManagementOperation),
+					bertlv.NewValue(bertlv.ContextSpecific.Primitive(1), []byte(config.Address)),
+				)) {
+					return
+				}
+			}
+		})
+	case tag.Equal(sgp22.TagProfileOwner):
+		if p.Owner == nil {
+			return nil
+		}
+		return bertlv.NewChildrenIter(tag, func(yield func(*bertlv.TLV) bool) {
+			if !yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), p.Owner.PLMN)) {
+				return
+			}
+			if len(p.Owner.GID1) > 0 && !yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(1), p.Owner.GID1)) {
+				return
+			}
+			if len(p.Owner.GID2) > 0 {
+				yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(2), p.Owner.GID2))
+			}
+		})
+	case tag.Equal(sgp22.TagProfilePolicyRules):
+		if !p.DisableNotAllowed && !p.DeleteNotAllowed {
+			return nil
+		}
+		return mustMarshalValue(tag, primitive.MarshalBitString([]bool{false, p.DisableNotAllowed, p.DeleteNotAllowed}))
+	}
+	return nil
+}
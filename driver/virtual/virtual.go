@@ -0,0 +1,241 @@
+package virtual
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"sync"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+// ISDRApplicationAID is the AID of the ISD-R application answered by the virtual eUICC.
+var ISDRApplicationAID = []byte{0xA0, 0x00, 0x00, 0x05, 0x59, 0x10, 0x10, 0xFF, 0xFF, 0xFF, 0xFF, 0x89, 0x00, 0x00, 0x01, 0x00}
+
+var ErrNotConnected = errors.New("virtual eUICC is not connected")
+
+// EUICC is an in-memory eUICC implementing the apdu.SmartCardChannel interface.
+// It answers the ES10a, ES10b and ES10c STORE DATA commands produced by apdu.Transmitter,
+// so it can be used as the channel of an lpa.Client:
+//
+//	card := virtual.New()
+//	client, err := lpa.New(&lpa.Options{Channel: card})
+//
+// The exported fields may be seeded before the card is used and inspected afterwards,
+// they must not be modified while a command is being transmitted.
+type EUICC struct {
+	// EID is the eUICC identifier returned by ES10c.GetEID.
+	EID []byte
+	// AID is the application identifier of the ISD-R. It defaults to ISDRApplicationAID.
+	AID []byte
+	// DefaultSMDPAddress and RootSMDSAddress are returned by ES10a.GetEuiccConfiguredAddresses.
+	DefaultSMDPAddress string
+	RootSMDSAddress    string
+	// SMDPOID is the SM-DP+ OID reported in the signed installation and cancel session results.
+	SMDPOID string
+	// CIPKID is the public key identifier of the GSMA CI advertised in EUICCInfo1 and EUICCInfo2.
+	CIPKID []byte
+	// FreeNonVolatileMemory is reported in the extended card resource of EUICCInfo2.
+	FreeNonVolatileMemory uint32
+	// Busy makes the profile management and memory reset functions answer catBusy.
+	Busy bool
+
+	Profiles      []*Profile
+	Notifications []*Notification
+
+	mutex          sync.Mutex
+	connected      bool
+	channel        byte
+	command        bytes.Buffer
+	response       []byte
+	bpp            *boundProfilePackage
+	challenge      []byte
+	session        *session
+	sequenceNumber sgp22.SequenceNumber
+	key            *ecdsa.PrivateKey
+	certificate    []byte
+	eumCertificate []byte
+}
+
+// New creates a virtual eUICC without any profile.
+func New() *EUICC {
+	card := &EUICC{
+		EID:                   []byte{0x89, 0x04, 0x90, 0x32, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		AID:                   ISDRApplicationAID,
+		SMDPOID:               "2.999.10",
+		CIPKID:                []byte{0xF5, 0x41, 0x72, 0xBD, 0xF9, 0x8A, 0x95, 0xD6, 0x5C, 0xBE, 0xB8, 0x8A, 0x38, 0xA1, 0xC1, 0x1D, 0x80, 0x0A, 0x85, 0xC3},
+		FreeNonVolatileMemory: 512 * 1024,
+	}
+	if err := card.generateCertificates(); err != nil {
+		panic(err)
+	}
+	return card
+}
+
+// Certificate returns the DER encoded eUICC certificate used to sign the eUICC responses.
+func (e *EUICC) Certificate() []byte { return e.certificate }
+
+// EUMCertificate returns the DER encoded EUM certificate which issued the eUICC certificate.
+func (e *EUICC) EUMCertificate() []byte { return e.eumCertificate }
+
+func (e *EUICC) Connect() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.connected = true
+	return nil
+}
+
+func (e *EUICC) Disconnect() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.connected = false
+	e.channel = 0
+	e.reset()
+	return nil
+}
+
+func (e *EUICC) OpenLogicalChannel(AID []byte) (byte, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if !e.connected {
+		return 0, ErrNotConnected
+	}
+	if !bytes.Equal(AID, e.AID) {
+		return 0, errors.New("select AID: application not found")
+	}
+	e.channel = 1
+	return e.channel, nil
+}
+
+func (e *EUICC) CloseLogicalChannel(channel byte) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if !e.connected {
+		return ErrNotConnected
+	}
+	if channel != e.channel {
+		return errors.New("close logical channel: channel is not open")
+	}
+	e.channel = 0
+	e.reset()
+	return nil
+}
+
+func (e *EUICC) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if !e.connected {
+		return nil, ErrNotConnected
+	}
+	if len(command) < 4 {
+		return sw(0x6700), nil
+	}
+	request := parseRequest(command)
+	switch request.INS {
+	case 0x70:
+		return e.manageChannel(request), nil
+	case 0xA4:
+		return e.selectApplication(request), nil
+	case 0xAA:
+		return sw(0x9000), nil
+	case 0xE2:
+		return e.storeData(request), nil
+	case 0xC0:
+		return e.getResponse(request), nil
+	}
+	return sw(0x6D00), nil
+}
+
+func (e *EUICC) reset() {
+	e.command.Reset()
+	e.response = nil
+	e.bpp = nil
+}
+
+func (e *EUICC) manageChannel(request *apdu.Request) apdu.Response {
+	if request.P1 == 0x80 {
+		if request.P2 == e.channel {
+			e.channel = 0
+			e.reset()
+		}
+		return sw(0x9000)
+	}
+	e.channel = 1
+	return append([]byte{e.channel}, sw(0x9000)...)
+}
+
+func (e *EUICC) selectApplication(request *apdu.Request) apdu.Response {
+	if !bytes.Equal(request.Data, e.AID) {
+		return sw(0x6A82)
+	}
+	return sw(0x9000)
+}
+
+// storeData accumulates the STORE DATA blocks and processes the command once the last block is received.
+func (e *EUICC) storeData(request *apdu.Request) apdu.Response {
+	if request.P2 == 0 {
+		e.command.Reset()
+	}
+	e.command.Write(request.Data)
+	if request.P1&0x80 == 0 {
+		return sw(0x9000)
+	}
+	command := bytes.Clone(e.command.Bytes())
+	e.command.Reset()
+	response, err := e.handle(command)
+	if errors.Is(err, errInvalidSMDPOID) {
+		return sw(0x6F00)
+	}
+	if err != nil {
+		return sw(0x6A80)
+	}
+	return e.respond(response)
+}
+
+func (e *EUICC) respond(response []byte) apdu.Response {
+	if e.response = response; len(e.response) == 0 {
+		return sw(0x9000)
+	}
+	return sw(0x6100 | uint16(min(len(e.response), 256)&0xFF))
+}
+
+func (e *EUICC) getResponse(request *apdu.Request) apdu.Response {
+	if len(e.response) == 0 {
+		return sw(0x6985)
+	}
+	n := 256
+	if request.Le != nil && *request.Le != 0 {
+		n = int(*request.Le)
+	}
+	n = min(n, len(e.response))
+	chunk := e.response[:n]
+	e.response = e.response[n:]
+	if len(e.response) == 0 {
+		return append(bytes.Clone(chunk), sw(0x9000)...)
+	}
+	return append(bytes.Clone(chunk), sw(0x6100|uint16(min(len(e.response), 256)&0xFF))...)
+}
+
+func parseRequest(command []byte) *apdu.Request {
+	request := &apdu.Request{CLA: command[0], INS: command[1], P1: command[2], P2: command[3]}
+	switch body := command[4:]; {
+	case len(body) == 1:
+		request.Le = &body[0]
+	case len(body) > 1:
+		n := min(int(body[0]), len(body)-1)
+		request.Data = body[1 : 1+n]
+		if len(body) > 1+n {
+			request.Le = &body[1+n]
+		}
+	}
+	return request
+}
+
+func sw(status uint16) apdu.Response {
+	return apdu.Response{byte(status >> 8), byte(status)}
+}
@@ -0,0 +1,182 @@
+package virtual_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T) (*virtual.EUICC, *lpa.Client) {
+	card := virtual.New()
+	card.DefaultSMDPAddress = "smdp.example.com"
+	notifications := sgp22.NotificationConfigurationInfo{
+		{ProfileManagementOperation: sgp22.NotificationEventEnable, Address: "smdp.example.com"},
+		{ProfileManagementOperation: sgp22.NotificationEventDisable, Address: "smdp.example.com"},
+		{ProfileManagementOperation: sgp22.NotificationEventDelete, Address: "smdp.example.com"},
+	}
+	card.AddProfile(&virtual.Profile{
+		ICCID:                         mustICCID(t, "8944476500001224158"),
+		State:                         sgp22.ProfileEnabled,
+		ServiceProviderName:           "Operator A",
+		ProfileName:                   "Profile A",
+		Class:                         sgp22.ProfileClassOperational,
+		NotificationConfigurationInfo: notifications,
+	})
+	card.AddProfile(&virtual.Profile{
+		ICCID:                         mustICCID(t, "8944476500001224166"),
+		State:                         sgp22.ProfileDisabled,
+		ServiceProviderName:           "Operator B",
+		ProfileName:                   "Profile B",
+		Class:                         sgp22.ProfileClassOperational,
+		NotificationConfigurationInfo: notifications,
+	})
+	client, err := lpa.New(&lpa.Options{Channel: card, MSS: 120})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return card, client
+}
+
+func mustICCID(t *testing.T, value string) sgp22.ICCID {
+	iccid, err := sgp22.NewICCID(value)
+	require.NoError(t, err)
+	return iccid
+}
+
+func TestEUICC_ChipInfo(t *testing.T) {
+	ctx := context.Background()
+	card, client := newClient(t)
+	info, err := client.ChipInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "89049032000000000000000000000001", info.EID)
+	assert.Equal(t, "smdp.example.com", info.ConfiguredAddresses.DefaultSMDPAddress)
+	assert.Equal(t, card.FreeNonVolatileMemory, info.Info2.ExtCardResource.FreeNonVolatileMemory)
+}
+
+func TestEUICC_ProfileManagement(t *testing.T) {
+	ctx := context.Background()
+	card, client := newClient(t)
+	profiles, err := client.ListProfile(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "8944476500001224158", profiles[0].ICCID.String())
+	assert.Equal(t, sgp22.ProfileEnabled, profiles[0].ProfileState)
+
+	second := profiles[1].ICCID
+	assert.NoError(t, client.EnableProfile(ctx, second, false))
+	assert.Equal(t, sgp22.ProfileDisabled, card.Profiles[0].State)
+	assert.Equal(t, sgp22.ProfileEnabled, card.Profiles[1].State)
+	err = client.EnableProfile(ctx, second, false)
+	assert.EqualError(t, err, "profile not in disabled state")
+	var resultErr *sgp22.ResultError
+	require.ErrorAs(t, err, &resultErr)
+	assert.Equal(t, "ES10c.EnableProfile", resultErr.Function)
+	assert.Equal(t, int8(2), resultErr.Result)
+
+	assert.NoError(t, client.SetNickname(ctx, second, "Work"))
+	profiles, err = client.ListProfile(ctx, second, nil)
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "Work", profiles[0].ProfileNickname)
+
+	assert.ErrorIs(t, client.DeleteProfile(ctx, second), sgp22.ErrProfileNotInDisabledState)
+	assert.NoError(t, client.DisableProfile(ctx, profiles[0].ISDPAID, false))
+	assert.NoError(t, client.DeleteProfile(ctx, second))
+	assert.Len(t, card.Profiles, 1)
+	assert.ErrorIs(t, client.DeleteProfile(ctx, second), sgp22.ErrICCIDOrAIDNotFound)
+
+	card.Busy = true
+	assert.ErrorIs(t, client.EnableProfile(ctx, card.Profiles[0].ICCID, false), sgp22.ErrCatBusy)
+}
+
+func TestEUICC_Notifications(t *testing.T) {
+	ctx := context.Background()
+	card, client := newClient(t)
+	require.NoError(t, client.EnableProfile(ctx, card.Profiles[1].ICCID, false))
+
+	notifications, err := client.ListNotification(ctx)
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, sgp22.NotificationEventDisable, notifications[0].ProfileManagementOperation)
+	assert.Equal(t, sgp22.NotificationEventEnable, notifications[1].ProfileManagementOperation)
+
+	notifications, err = client.ListNotification(ctx, sgp22.NotificationEventEnable)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+
+	pending, err := client.RetrieveNotificationList(ctx, notifications[0].SequenceNumber)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "smdp.example.com", pending[0].Notification.Address)
+	assert.Equal(t, card.Profiles[1].ICCID, pending[0].Notification.ICCID)
+
+	assert.NoError(t, client.RemoveNotificationFromList(ctx, notifications[0].SequenceNumber))
+	assert.ErrorIs(t, client.RemoveNotificationFromList(ctx, notifications[0].SequenceNumber), sgp22.ErrNothingToDelete)
+	assert.Len(t, card.Notifications, 1)
+}
+
+func TestEUICC_ConfiguredAddresses(t *testing.T) {
+	ctx := context.Background()
+	_, client := newClient(t)
+	require.NoError(t, client.SetDefaultDPAddress(ctx, "smdp.example.org"))
+	addresses, err := client.EUICCConfiguredAddresses(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "smdp.example.org", addresses.DefaultSMDPAddress)
+}
+
+func TestEUICC_MemoryReset(t *testing.T) {
+	ctx := context.Background()
+	card, client := newClient(t)
+	require.NoError(t, client.MemoryReset(ctx))
+	assert.Empty(t, card.Profiles)
+	assert.Empty(t, card.DefaultSMDPAddress)
+	assert.ErrorIs(t, client.MemoryReset(ctx), sgp22.ErrNothingToDelete)
+}
+
+func TestEUICC_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, client := newClient(t)
+	_, err := client.EID(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestEUICC_MalformedCommands(t *testing.T) {
+	card := virtual.New()
+	require.NoError(t, card.Connect())
+	defer card.Disconnect()
+	channel, err := card.OpenLogicalChannel(virtual.ISDRApplicationAID)
+	require.NoError(t, err)
+	// RemoveNotificationFromList and EUICCMemoryReset without their mandatory fields
+	for _, tag := range []byte{0x30, 0x34} {
+		response, err := card.Transmit(context.Background(), []byte{0x80 | channel, 0xE2, 0x91, 0x00, 0x03, 0xBF, tag, 0x00})
+		require.NoError(t, err)
+		require.Equal(t, []byte{0x61, 0x06}, response)
+		response, err = card.Transmit(context.Background(), []byte{0x80 | channel, 0xC0, 0x00, 0x00, 0x06})
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0xBF, tag, 0x03, 0x80, 0x01, 0x7F, 0x90, 0x00}, response)
+	}
+}
+
+func TestEUICC_InvalidSMDPOID(t *testing.T) {
+	card := virtual.New()
+	require.NoError(t, card.Connect())
+	defer card.Disconnect()
+	channel, err := card.OpenLogicalChannel(virtual.ISDRApplicationAID)
+	require.NoError(t, err)
+	for _, oid := range []string{"", "1", "2.x.10", "3.1"} {
+		card.SMDPOID = oid
+		// CancelSession signs its result with the SM-DP+ OID.
+		response, err := card.Transmit(context.Background(), []byte{0x80 | channel, 0xE2, 0x91, 0x00, 0x03, 0xBF, 0x41, 0x00})
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x6F, 0x00}, response, oid)
+	}
+	// The other commands do not use it.
+	response, err := card.Transmit(context.Background(), []byte{0x80 | channel, 0xE2, 0x91, 0x00, 0x03, 0xBF, 0x3E, 0x00})
+	require.NoError(t, err)
+	assert.Equal(t, byte(0x61), response[0])
+}
@@ -0,0 +1,113 @@
+package vpcd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+)
+
+// DefaultATR is the ATR of a card without historical bytes, offering T=0 and T=1.
+var DefaultATR = []byte{0x3B, 0x80, 0x80, 0x01, 0x01}
+
+// Card serves a channel as the virtual card of a vpcd.
+//
+// The command APDUs are sent as they are with Transmit, MANAGE CHANNEL and SELECT included,
+// so the channel must accept them, as the virtual eUICC and the PC/SC readers do.
+type Card struct {
+	// Channel is connected on power on, and disconnected on power off.
+	Channel apdu.SmartCardChannel
+	// ATR is the answer to reset, it defaults to DefaultATR.
+	ATR []byte
+}
+
+// Dial connects to the vpcd listening on the address and serves it, until the connection or the context is closed.
+func (c *Card) Dial(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("vpcd: %w", err)
+	}
+	return c.ServeConn(ctx, conn)
+}
+
+// Serve accepts the vpcd connections on the listener and serves them one at a time, until the listener is closed.
+func (c *Card) Serve(ctx context.Context, listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		_ = c.ServeConn(ctx, conn)
+	}
+}
+
+// ServeConn serves a vpcd connection until it or the context is closed, and closes it.
+// The channel is disconnected when the connection is closed while the card is powered on.
+func (c *Card) ServeConn(ctx context.Context, conn net.Conn) error {
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	powered := false
+	defer func() {
+		if powered {
+			_ = c.Channel.Disconnect()
+		}
+	}()
+	for {
+		message, err := readMessage(conn)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if len(message) != 1 {
+			if err := writeMessage(conn, c.transmit(ctx, message)); err != nil {
+				return err
+			}
+			continue
+		}
+		switch message[0] {
+		case controlPowerOff:
+			if powered {
+				powered = false
+				err = c.Channel.Disconnect()
+			}
+		case controlPowerOn:
+			if !powered {
+				err = c.Channel.Connect()
+				powered = err == nil
+			}
+		case controlReset:
+			if powered {
+				_ = c.Channel.Disconnect()
+			}
+			err = c.Channel.Connect()
+			powered = err == nil
+		case controlGetATR:
+			atr := c.ATR
+			if atr == nil {
+				atr = DefaultATR
+			}
+			err = writeMessage(conn, atr)
+		default:
+			err = fmt.Errorf("vpcd: unknown control %02X", message[0])
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// transmit sends the command to the channel, the errors other than a status word are answered with 6F00.
+func (c *Card) transmit(ctx context.Context, command []byte) []byte {
+	response, err := c.Channel.Transmit(ctx, command)
+	var statusErr *apdu.StatusWordError
+	if err != nil && (!errors.As(err, &statusErr) || len(response) < 2) {
+		return []byte{0x6F, 0x00}
+	}
+	return response
+}
@@ -0,0 +1,270 @@
+// Package vpcd speaks the protocol of the virtual smart card reader of vsmartcard,
+// to reach virtual cards such as vicc, jCardSim or the Android remote reader apps without PC/SC.
+//
+// The reader and the card exchange messages prefixed with their length on two bytes, over TCP.
+// A message of one byte is a control sent by the reader: power off, power on, reset or get the ATR;
+// any other message is a command APDU, answered by the response APDU.
+//
+// Reader is the channel of the reader side. It dials a card listening on an address,
+// or listens for a card connecting to it, as vpcd does:
+//
+//	vpcd://localhost:35963
+//	vpcd://:35963?listen=true
+//
+// Card is the card side, it serves a channel to a vpcd, so that PC/SC applications reach the channel.
+package vpcd
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+)
+
+// The control messages sent by the reader.
+const (
+	controlPowerOff byte = 0x00
+	controlPowerOn  byte = 0x01
+	controlReset    byte = 0x02
+	controlGetATR   byte = 0x04
+)
+
+// DefaultPort is the port vpcd listens on.
+const DefaultPort = "35963"
+
+// lifecycleTimeout bounds the calls taking no context, and the wait for a card connecting to a listening reader.
+const lifecycleTimeout = time.Minute
+
+func readMessage(r io.Reader) ([]byte, error) {
+	var length [2]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	message := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(r, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func writeMessage(w io.Writer, message []byte) error {
+	if len(message) > 0xFFFF {
+		return fmt.Errorf("vpcd: message of %d bytes is too long", len(message))
+	}
+	_, err := w.Write(binary.BigEndian.AppendUint16(nil, uint16(len(message))))
+	if err == nil {
+		_, err = w.Write(message)
+	}
+	return err
+}
+
+func init() {
+	driver.Register("vpcd", func(uri *url.URL) (apdu.SmartCardChannel, error) {
+		listen := false
+		if value := uri.Query().Get("listen"); value != "" {
+			var err error
+			if listen, err = strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("vpcd: invalid listen %q: %w", value, err)
+			}
+		}
+		address := uri.Host
+		if uri.Port() == "" {
+			address = net.JoinHostPort(uri.Hostname(), DefaultPort)
+		}
+		return New(address, listen), nil
+	})
+}
+
+// Reader is an apdu.SmartCardChannel to a virtual card.
+type Reader struct {
+	address  string
+	listen   bool
+	mutex    sync.Mutex
+	conn     net.Conn
+	broken   error
+	atr      []byte
+	observer apdu.Observer
+}
+
+// New returns a reader dialing the card listening on the address,
+// or listening on the address for the card when listen is set. The card is reached on Connect.
+func New(address string, listen bool) *Reader {
+	return &Reader{address: address, listen: listen}
+}
+
+func (r *Reader) SetObserver(observer apdu.Observer) {
+	r.observer = observer
+}
+
+// ATR returns the answer to reset of the card, read on Connect.
+func (r *Reader) ATR() []byte {
+	return r.atr
+}
+
+func (r *Reader) dial() (net.Conn, error) {
+	if !r.listen {
+		return net.DialTimeout("tcp", r.address, lifecycleTimeout)
+	}
+	listener, err := net.Listen("tcp", r.address)
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+	_ = listener.(*net.TCPListener).SetDeadline(time.Now().Add(lifecycleTimeout))
+	return listener.Accept()
+}
+
+// Connect reaches the card, powers it on and reads its ATR.
+func (r *Reader) Connect() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	conn, err := r.dial()
+	if err != nil {
+		return fmt.Errorf("vpcd: %w", err)
+	}
+	r.conn, r.broken = conn, nil
+	ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout)
+	defer cancel()
+	err = r.control(ctx, controlPowerOn)
+	if err == nil {
+		r.atr, err = r.exchange(ctx, []byte{controlGetATR})
+	}
+	if err != nil {
+		_ = r.conn.Close()
+		r.conn = nil
+		return fmt.Errorf("vpcd: power on: %w", err)
+	}
+	return nil
+}
+
+// Disconnect powers the card off and closes the connection.
+func (r *Reader) Disconnect() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.conn == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout)
+	defer cancel()
+	err := r.control(ctx, controlPowerOff)
+	if closeErr := r.conn.Close(); err == nil {
+		err = closeErr
+	}
+	r.conn = nil
+	return err
+}
+
+// control sends a control message, the card answers none but the ATR.
+func (r *Reader) control(ctx context.Context, control byte) error {
+	return r.call(ctx, func() error { return writeMessage(r.conn, []byte{control}) })
+}
+
+// exchange sends the message and receives the answer of the card.
+func (r *Reader) exchange(ctx context.Context, message []byte) (answer []byte, err error) {
+	err = r.call(ctx, func() error {
+		if err := writeMessage(r.conn, message); err != nil {
+			return err
+		}
+		answer, err = readMessage(r.conn)
+		return err
+	})
+	return answer, err
+}
+
+// call runs f with the deadline of the context on the connection.
+// An interrupted call leaves the connection out of step with the card, it is closed and the channel is unusable.
+func (r *Reader) call(ctx context.Context, f func() error) error {
+	if r.conn == nil {
+		return errors.New("vpcd: not connected")
+	}
+	if r.broken != nil {
+		return r.broken
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	_ = r.conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { _ = r.conn.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+	if err := f(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		r.broken = fmt.Errorf("vpcd: connection lost: %w", err)
+		_ = r.conn.Close()
+		return err
+	}
+	return nil
+}
+
+func (r *Reader) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.transmit(ctx, command)
+}
+
+func (r *Reader) transmit(ctx context.Context, command []byte) ([]byte, error) {
+	response, err := r.exchange(ctx, command)
+	if err == nil && len(response) < 2 {
+		err = fmt.Errorf("vpcd: invalid response %X", response)
+	}
+	if r.observer != nil {
+		r.observer(command, response, err)
+	}
+	return response, err
+}
+
+// lifecycleTransmit sends a command of the reader, failing on the status words other than 9000 and 61XX.
+func (r *Reader) lifecycleTransmit(command []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout)
+	defer cancel()
+	response, err := r.transmit(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+	if sw := apdu.Response(response).SW(); sw != 0x9000 && sw>>8 != 0x61 {
+		return response, &apdu.StatusWordError{Command: command, SW: sw}
+	}
+	return response, nil
+}
+
+func (r *Reader) OpenLogicalChannel(AID []byte) (byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	response, err := r.lifecycleTransmit([]byte{0x00, 0x70, 0x00, 0x00, 0x01})
+	if err != nil {
+		return 0, fmt.Errorf("open logical channel: %w", err)
+	}
+	if len(response) != 3 {
+		return 0, fmt.Errorf("open logical channel: invalid response %X", response)
+	}
+	channel := response[0]
+	cla := channel
+	if channel > 3 {
+		cla = 0x40 | (channel - 4)
+	}
+	if _, err := r.lifecycleTransmit(append([]byte{cla, 0xA4, 0x04, 0x00, byte(len(AID))}, AID...)); err != nil {
+		_, _ = r.lifecycleTransmit([]byte{0x00, 0x70, 0x80, channel, 0x00})
+		return 0, fmt.Errorf("select AID: %w", err)
+	}
+	return channel, nil
+}
+
+func (r *Reader) CloseLogicalChannel(channel byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, err := r.lifecycleTransmit([]byte{0x00, 0x70, 0x80, channel, 0x00}); err != nil {
+		return fmt.Errorf("close logical channel: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package vpcd
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func assertEUICC(t *testing.T, card *virtual.EUICC, reader *Reader) {
+	client, err := lpa.New(&lpa.Options{Channel: reader})
+	require.NoError(t, err)
+	eid, err := client.EID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, card.EID, eid)
+	require.NoError(t, client.Close())
+}
+
+func TestReader_Dial(t *testing.T) {
+	card := virtual.New()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go (&Card{Channel: card}).Serve(context.Background(), listener)
+
+	reader := New(listener.Addr().String(), false)
+	assertEUICC(t, card, reader)
+	assert.Equal(t, DefaultATR, reader.ATR())
+
+	// The card serves the next connection once the reader disconnected.
+	require.NoError(t, reader.Connect())
+	defer reader.Disconnect()
+	response, err := reader.Transmit(context.Background(), []byte{0x80, 0x10, 0x00, 0x00})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x6D, 0x00}, response)
+	_, err = reader.OpenLogicalChannel([]byte{0xA0, 0x00})
+	assert.EqualError(t, err, "select AID: returned an unexpected response with status 6A82")
+}
+
+func TestReader_Listen(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	// The card dials the reader until the reader listens, and again after each session, as vicc does.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	card := virtual.New()
+	go func() {
+		for ctx.Err() == nil {
+			_ = (&Card{Channel: card, ATR: []byte{0x3B, 0x00}}).Dial(ctx, address)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	reader := New(address, true)
+	assertEUICC(t, card, reader)
+	assert.Equal(t, []byte{0x3B, 0x00}, reader.ATR())
+	assertEUICC(t, card, reader)
+}
@@ -6,7 +6,8 @@ import (
 	"log/slog"
 	"net/url"
 
-	"github.com/KilimcininKorOglu/euicc-go/driver/qmi"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	_ "github.com/KilimcininKorOglu/euicc-go/driver/qmi"
 	"github.com/KilimcininKorOglu/euicc-go/lpa"
 	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
 )
@@ -14,32 +15,12 @@ import (
 func main() {
 	slog.SetLogLoggerLevel(slog.LevelDebug)
 
-	// ch, err := mbim.New("/dev/cdc-wdm0", 1)
-	// if err != nil {
-	// 	panic(err)
-	// }
-	ch, err := qmi.New("/dev/cdc-wdm0", 1)
-	// ch, err := qmi.NewQRTR(1)
+	// Other channels: mbim:///dev/cdc-wdm0?slot=1, qrtr://?slot=1, at:///dev/ttyUSB7 or pcsc://?reader=...,
+	// the driver package must be imported for its scheme to be registered.
+	ch, err := driver.Open("qmi:///dev/cdc-wdm0?slot=1")
 	if err != nil {
 		panic(err)
 	}
-	// ch, err := at.New("/dev/ttyUSB7")
-	// if err != nil {
-	// 	panic(err)
-	// }
-	// ch, err := ccid.New()
-	// if err != nil {
-	// 	panic(err)
-	// }
-	// reader, err := ch.ListReaders()
-	// if err != nil {
-	// 	panic(err)
-	// }
-	// if len(reader) == 0 {
-	// 	panic("No readers found")
-	// }
-	// fmt.Printf("Using reader: %s\n", reader[0])
-	// ch.SetReader(reader[0])
 
 	client, err := lpa.New(&lpa.Options{
 		Channel: ch,
@@ -58,7 +39,8 @@ func main() {
 }
 
 func testEID(client *lpa.Client) {
-	eid, err := client.EID()
+	ctx := context.Background()
+	eid, err := client.EID(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -92,7 +74,8 @@ func testDownload(client *lpa.Client) {
 }
 
 func testListProfiles(client *lpa.Client) {
-	profiles, err := client.ListProfile(nil, nil)
+	ctx := context.Background()
+	profiles, err := client.ListProfile(ctx, nil, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -102,7 +85,8 @@ func testListProfiles(client *lpa.Client) {
 }
 
 func testListNotifications(client *lpa.Client) {
-	notifications, err := client.ListNotification()
+	ctx := context.Background()
+	notifications, err := client.ListNotification(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -113,8 +97,9 @@ func testListNotifications(client *lpa.Client) {
 }
 
 func testEnableProfile(client *lpa.Client) {
+	ctx := context.Background()
 	id, _ := sgp22.NewICCID("8944476500001224158")
-	if err := client.EnableProfile(id, true); err != nil {
+	if err := client.EnableProfile(ctx, id, true); err != nil {
 		fmt.Printf("Failed to enable profile: %v\n", err)
 	} else {
 		fmt.Println("Profile enabled successfully")
@@ -122,8 +107,9 @@ func testEnableProfile(client *lpa.Client) {
 }
 
 func testDisableProfile(client *lpa.Client) {
+	ctx := context.Background()
 	id, _ := sgp22.NewICCID("8944476500001224158")
-	if err := client.DisableProfile(id, true); err != nil {
+	if err := client.DisableProfile(ctx, id, true); err != nil {
 		fmt.Printf("Failed to disable profile: %v\n", err)
 	} else {
 		fmt.Println("Profile disabled successfully")
@@ -131,7 +117,8 @@ func testDisableProfile(client *lpa.Client) {
 }
 
 func testSendNotification(client *lpa.Client, sequenceNumber sgp22.SequenceNumber) {
-	notifications, err := client.RetrieveNotificationList(sequenceNumber)
+	ctx := context.Background()
+	notifications, err := client.RetrieveNotificationList(ctx, sequenceNumber)
 	if err != nil {
 		fmt.Printf("Failed to retrieve notifications: %v\n", err)
 		return
@@ -140,7 +127,7 @@ func testSendNotification(client *lpa.Client, sequenceNumber sgp22.SequenceNumbe
 		fmt.Println("No notifications found")
 		return
 	}
-	if err := client.HandleNotification(notifications[0]); err != nil {
+	if err := client.HandleNotification(ctx, notifications[0]); err != nil {
 		fmt.Printf("Failed to handle notification: %v\n", err)
 	} else {
 		fmt.Println("Notification handled successfully")
@@ -148,6 +135,7 @@ func testSendNotification(client *lpa.Client, sequenceNumber sgp22.SequenceNumbe
 }
 
 func testDiscovery(client *lpa.Client) {
+	ctx := context.Background()
 	addresses := []url.URL{
 		{Scheme: "https", Host: "lpa.ds.gsma.com"},
 		{Scheme: "https", Host: "lpa.live.esimdiscovery.com"},
@@ -156,7 +144,7 @@ func testDiscovery(client *lpa.Client) {
 	for _, address := range addresses {
 		fmt.Printf("Discovering profiles at %s...\n", address.Host)
 		imei, _ := sgp22.NewIMEI("356938035643809") // Example IMEI, replace with actual if needed
-		entries, err := client.Discovery(&address, imei)
+		entries, err := client.Discovery(ctx, &address, imei)
 		if err != nil {
 			fmt.Printf("Failed to discover profiles: %v\n", err)
 			continue
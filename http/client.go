@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,23 +10,40 @@ import (
 	"net/url"
 )
 
+// maxErrorBodySize limits the body kept in a StatusError.
+const maxErrorBodySize = 64 << 10
+
+// StatusError is returned when the server answers with an HTTP status other than 2xx.
+type StatusError struct {
+	StatusCode int
+	// Body is the body of the response, truncated to 64 KiB.
+	Body []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
 type Client struct {
 	Client               *http.Client
 	AdminProtocolVersion string
 }
 
-func (c *Client) NewRequest(u *url.URL, request any) (*http.Request, error) {
+func (c *Client) NewRequest(ctx context.Context, u *url.URL, request any) (*http.Request, error) {
 	var body bytes.Buffer
 	if err := json.NewEncoder(&body).Encode(request); err != nil {
 		return nil, err
 	}
-	httpRequest, _ := http.NewRequest(http.MethodPost, u.String(), &body)
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), &body)
+	if err != nil {
+		return nil, err
+	}
 	httpRequest.Header = c.Header()
 	return httpRequest, nil
 }
 
-func (c *Client) SendRequest(u *url.URL, request, response any) error {
-	httpRequest, err := c.NewRequest(u, request)
+func (c *Client) SendRequest(ctx context.Context, u *url.URL, request, response any) error {
+	httpRequest, err := c.NewRequest(ctx, u, request)
 	if err != nil {
 		return err
 	}
@@ -33,10 +51,14 @@ func (c *Client) SendRequest(u *url.URL, request, response any) error {
 	if err != nil {
 		return err
 	}
+	defer httpResponse.Body.Close()
 	if httpResponse.StatusCode > 299 {
-		return fmt.Errorf("unexpected status code: %d", httpResponse.StatusCode)
+		body, _ := io.ReadAll(io.LimitReader(httpResponse.Body, maxErrorBodySize))
+		// Some servers report the failed function execution status with an HTTP error status,
+		// decode it so that the caller can still inspect the header of the response.
+		_ = json.Unmarshal(body, response)
+		return &StatusError{StatusCode: httpResponse.StatusCode, Body: body}
 	}
-	defer httpResponse.Body.Close()
 	if err = json.NewDecoder(httpResponse.Body).Decode(response); err != nil && err != io.EOF {
 		return err
 	}
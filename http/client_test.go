@@ -0,0 +1,49 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	euicchttp "github.com/KilimcininKorOglu/euicc-go/http"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, status int, body string) (*euicchttp.Client, *url.URL) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	address, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return &euicchttp.Client{Client: server.Client(), AdminProtocolVersion: "2.5.0"}, address
+}
+
+func TestClient_StatusError(t *testing.T) {
+	client, address := serve(t, http.StatusBadGateway, "upstream unavailable")
+	_, err := sgp22.InvokeHTTP(context.Background(), client, address, &sgp22.ES9CancelSessionRequest{
+		TransactionID: sgp22.HexString{0x01, 0x02},
+	})
+	var statusErr *euicchttp.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusBadGateway, statusErr.StatusCode)
+	assert.Equal(t, "upstream unavailable", string(statusErr.Body))
+}
+
+func TestClient_StatusErrorWithHeader(t *testing.T) {
+	client, address := serve(t, http.StatusBadRequest, `{"header":{"functionExecutionStatus":{"status":"Failed","statusCodeData":{"subjectCode":"8.10.1","reasonCode":"3.9"}}}}`)
+	_, err := sgp22.InvokeHTTP(context.Background(), client, address, &sgp22.ES9CancelSessionRequest{
+		TransactionID: sgp22.HexString{0x01, 0x02},
+	})
+	var rspErr *sgp22.RSPError
+	require.ErrorAs(t, err, &rspErr)
+	assert.True(t, rspErr.IsTransactionIDUnknown())
+	assert.Equal(t, "ES9+.CancelSession", rspErr.Function)
+	assert.Equal(t, sgp22.HexString{0x01, 0x02}, rspErr.TransactionID)
+	assert.EqualError(t, err, "The RSP session identified by the TransactionID is unknown")
+}
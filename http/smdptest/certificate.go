@@ -0,0 +1,145 @@
+package smdptest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/bertlv"
+)
+
+// certificate is a certificate of the test chain together with its private key.
+type certificate struct {
+	der []byte
+	key *ecdsa.PrivateKey
+}
+
+// generateCertificates creates a test CI certificate and the CERT.DPauth.ECDSA and CERT.DPpb.ECDSA issued by it.
+// The chain is not trusted by a real eUICC, it only allows a virtual eUICC to exercise the download procedure.
+func (s *Server) generateCertificates() (err error) {
+	if s.ci, err = newCertificate(nil, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"euicc-go"}, CommonName: "Test CI"},
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}); err != nil {
+		return err
+	}
+	oid, err := parseOID(s.OID)
+	if err != nil {
+		return err
+	}
+	extensions := []pkix.Extension{subjectAltNameRID(oid)}
+	if s.auth, err = newCertificate(s.ci, &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		Subject:         pkix.Name{Organization: []string{"euicc-go"}, CommonName: "Test SM-DP+ auth"},
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtraExtensions: extensions,
+	}); err != nil {
+		return err
+	}
+	s.pb, err = newCertificate(s.ci, &x509.Certificate{
+		SerialNumber:    big.NewInt(3),
+		Subject:         pkix.Name{Organization: []string{"euicc-go"}, CommonName: "Test SM-DP+ pb"},
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtraExtensions: extensions,
+	})
+	return err
+}
+
+func newCertificate(issuer *certificate, template *x509.Certificate) (*certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = template.NotBefore.AddDate(10, 0, 0)
+	parent, signer := template, key
+	if issuer != nil {
+		if parent, err = x509.ParseCertificate(issuer.der); err != nil {
+			return nil, err
+		}
+		signer = issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, err
+	}
+	return &certificate{der: der, key: key}, nil
+}
+
+// subjectAltNameRID encodes the SM-DP+ OID as the registeredID of the subject alternative name.
+func subjectAltNameRID(oid asn1.ObjectIdentifier) pkix.Extension {
+	value, _ := asn1.Marshal(oid)
+	value[0] = 0x88
+	names, _ := asn1.Marshal(asn1.RawValue{Tag: asn1.TagSequence, Class: asn1.ClassUniversal, IsCompound: true, Bytes: value})
+	return pkix.Extension{Id: asn1.ObjectIdentifier{2, 5, 29, 17}, Value: names}
+}
+
+// TLV returns the certificate as a TLV.
+func (c *certificate) TLV() *bertlv.TLV {
+	var tlv bertlv.TLV
+	if err := tlv.UnmarshalBinary(c.der); err != nil {
+		panic(err)
+	}
+	return &tlv
+}
+
+// SubjectKeyID returns the subject key identifier of the certificate.
+func (c *certificate) SubjectKeyID() []byte {
+	parsed, err := x509.ParseCertificate(c.der)
+	if err != nil {
+		panic(err)
+	}
+	return parsed.SubjectKeyId
+}
+
+// sign signs the DER encoding of the data objects and returns the signature TLV.
+// The signature is the concatenation of r and s as required by SGP.22.
+func (c *certificate) sign(objects ...*bertlv.TLV) *bertlv.TLV {
+	hashed := sha256.New()
+	for _, object := range objects {
+		hashed.Write(object.Bytes())
+	}
+	r, ss, err := ecdsa.Sign(rand.Reader, c.key, hashed.Sum(nil))
+	if err != nil {
+		panic(err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	ss.FillBytes(signature[32:])
+	return bertlv.NewValue(bertlv.Application.Primitive(55), signature)
+}
+
+// verify checks the eUICC signature of the data objects against the eUICC certificate.
+func verify(certificate, signature *bertlv.TLV, objects ...*bertlv.TLV) error {
+	if certificate == nil || signature == nil || len(signature.Value) != 64 {
+		return errors.New("missing eUICC signature")
+	}
+	parsed, err := x509.ParseCertificate(certificate.Bytes())
+	if err != nil {
+		return err
+	}
+	key, ok := parsed.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return errors.New("unsupported eUICC public key")
+	}
+	hashed := sha256.New()
+	for _, object := range objects {
+		hashed.Write(object.Bytes())
+	}
+	r := new(big.Int).SetBytes(signature.Value[:32])
+	ss := new(big.Int).SetBytes(signature.Value[32:])
+	if !ecdsa.Verify(key, hashed.Sum(nil), r, ss) {
+		return errors.New("invalid eUICC signature")
+	}
+	return nil
+}
@@ -0,0 +1,486 @@
+// Package smdptest provides a local SM-DP+ and SM-DS for testing the profile download procedure.
+//
+// The server answers the ES9+ and ES11 functions used by lpa.Client with a test certificate chain,
+// it is meant to be used together with a virtual eUICC:
+//
+//	server := smdptest.NewServer()
+//	defer server.Close()
+//	server.Orders["QR-CODE"] = &smdptest.Order{Profile: &sgp22.ProfileInfo{...}}
+//	client.HTTP.Client = server.Client()
+//	client.DownloadProfile(ctx, &lpa.ActivationCode{SMDP: server.SMDP(), MatchingID: "QR-CODE", IMEI: "..."}, nil)
+package smdptest
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/KilimcininKorOglu/euicc-go/bertlv"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+// The functions served by the test server, used as keys of Server.Errors.
+const (
+	InitiateAuthentication = "initiateAuthentication"
+	AuthenticateClient     = "authenticateClient"
+	GetBoundProfilePackage = "getBoundProfilePackage"
+	HandleNotification     = "handleNotification"
+	CancelSession          = "cancelSession"
+)
+
+// Order is a profile download order, identified by its matching ID.
+type Order struct {
+	// Profile is the metadata of the profile, sent as the StoreMetadata request.
+	// The ICCID, service provider name and profile name are required.
+	Profile *sgp22.ProfileInfo
+	// ConfirmationCode is required to download the profile when it is not empty.
+	ConfirmationCode string
+	// Expired makes the order fail with "The Download order has expired".
+	Expired bool
+	// ProfileSize is the size in bytes of the profile elements. It defaults to 1024.
+	ProfileSize int
+	// Downloaded is set once the Bound Profile Package was delivered, the order cannot be downloaded again.
+	Downloaded bool
+}
+
+// CanceledSession is a session canceled by the LPA through ES9+.CancelSession.
+type CanceledSession struct {
+	TransactionID []byte
+	Response      *bertlv.TLV
+}
+
+// Server is a test SM-DP+ and SM-DS serving the ES9+ and ES11 functions over TLS.
+//
+// The exported fields may be modified before the download is started and inspected afterwards,
+// they must not be modified while a request is being served.
+type Server struct {
+	*httptest.Server
+	// OID is the SM-DP+ OID, included in the SM-DP+ certificates.
+	OID string
+	// Orders are the download orders, keyed by matching ID.
+	Orders map[string]*Order
+	// Events are returned by ES11.AuthenticateClient.
+	// When Events is not nil, an authentication without matching ID is handled as an SM-DS discovery.
+	Events []*sgp22.EventEntry
+	// Errors makes a function fail with the execution status, keyed by function name.
+	Errors map[string]*sgp22.ExecutionStatus
+	// Notifications are the pending notifications received through ES9+.HandleNotification.
+	Notifications []*bertlv.TLV
+	// CanceledSessions are the sessions received through ES9+.CancelSession.
+	CanceledSessions []*CanceledSession
+
+	mutex    sync.Mutex
+	sessions map[string]*session
+	ci       *certificate
+	auth     *certificate
+	pb       *certificate
+}
+
+type session struct {
+	transactionID   []byte
+	serverChallenge []byte
+	order           *Order
+}
+
+// NewServer starts a test SM-DP+ with its own certificate chain.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		OID:      "2.999.10",
+		Orders:   make(map[string]*Order),
+		Errors:   make(map[string]*sgp22.ExecutionStatus),
+		sessions: make(map[string]*session),
+	}
+	if err := s.generateCertificates(); err != nil {
+		panic(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /gsma/rsp2/es9plus/initiateAuthentication", s.handle(InitiateAuthentication, s.initiateAuthentication))
+	mux.HandleFunc("POST /gsma/rsp2/es9plus/authenticateClient", s.handle(AuthenticateClient, s.authenticateClient))
+	mux.HandleFunc("POST /gsma/rsp2/es9plus/getBoundProfilePackage", s.handle(GetBoundProfilePackage, s.getBoundProfilePackage))
+	mux.HandleFunc("POST /gsma/rsp2/es9plus/handleNotification", s.handle(HandleNotification, s.handleNotification))
+	mux.HandleFunc("POST /gsma/rsp2/es9plus/cancelSession", s.handle(CancelSession, s.cancelSession))
+	s.Server = httptest.NewTLSServer(mux)
+	return s
+}
+
+// SMDP returns the address of the server to be used in an activation code.
+func (s *Server) SMDP() *url.URL {
+	return &url.URL{Scheme: "https", Host: s.Listener.Addr().String()}
+}
+
+// CICertificate returns the DER encoded test CI certificate.
+func (s *Server) CICertificate() []byte { return s.ci.der }
+
+type handlerFunc func(body []byte) (any, *sgp22.StatusCodeData)
+
+// handle wraps a function handler with the scripted errors and the JSON encoding of the response.
+func (s *Server) handle(function string, handler handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body bytes.Buffer
+		if _, err := body.ReadFrom(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if status, ok := s.Errors[function]; ok {
+			writeJSON(w, &struct {
+				Header *sgp22.Header `json:"header"`
+			}{&sgp22.Header{ExecutionStatus: status}})
+			return
+		}
+		response, failure := handler(body.Bytes())
+		switch {
+		case failure != nil:
+			writeJSON(w, &struct {
+				Header *sgp22.Header `json:"header"`
+			}{&sgp22.Header{ExecutionStatus: &sgp22.ExecutionStatus{Status: "Failed", StatusCodeData: failure}}})
+		case response == nil:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeJSON(w, response)
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Admin-Protocol", "gsma/rsp/v2.5.0")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func success() *sgp22.Header {
+	return &sgp22.Header{ExecutionStatus: &sgp22.ExecutionStatus{Status: "Executed-Success"}}
+}
+
+// statusCode returns the status code data of the rspErrors table entry.
+func statusCode(subjectCode, reasonCode string) *sgp22.StatusCodeData {
+	return &sgp22.StatusCodeData{SubjectCode: subjectCode, ReasonCode: reasonCode}
+}
+
+// region Section 5.6.1, ES9+.InitiateAuthentication
+
+func (s *Server) initiateAuthentication(body []byte) (any, *sgp22.StatusCodeData) {
+	var request sgp22.ES9InitiateAuthenticationRequest
+	if err := json.Unmarshal(body, &request); err != nil || request.Info1 == nil {
+		return nil, statusCode("8.1", "6.1")
+	}
+	transactionID := make([]byte, 16)
+	serverChallenge := make([]byte, 16)
+	_, _ = rand.Read(transactionID)
+	_, _ = rand.Read(serverChallenge)
+	signed1 := bertlv.NewChildren(
+		bertlv.Universal.Constructed(16),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), transactionID),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(1), request.Challenge),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(3), []byte(request.Address)),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(4), serverChallenge),
+	)
+	s.sessions[string(transactionID)] = &session{
+		transactionID:   transactionID,
+		serverChallenge: serverChallenge,
+	}
+	return &sgp22.ES9InitiateAuthenticationResponse{
+		Header:        success(),
+		TransactionID: transactionID,
+		Signed1:       signed1,
+		Signature1:    s.auth.sign(signed1),
+		UsedIssuer:    bertlv.NewValue(bertlv.Universal.Primitive(4), s.usedIssuer(request.Info1)),
+		Certificate:   s.auth.TLV(),
+	}, nil
+}
+
+// usedIssuer returns the first CI public key identifier supported by the eUICC for signing.
+func (s *Server) usedIssuer(info1 *bertlv.TLV) []byte {
+	if list := info1.First(bertlv.ContextSpecific.Constructed(10)); list != nil && len(list.Children) > 0 {
+		return list.At(0).Value
+	}
+	return s.ci.SubjectKeyID()
+}
+
+// endregion
+
+// region Section 5.6.3, ES9+.AuthenticateClient and Section 5.8.2, ES11.AuthenticateClient
+
+func (s *Server) authenticateClient(body []byte) (any, *sgp22.StatusCodeData) {
+	var request sgp22.ES9AuthenticateClientRequest
+	if err := json.Unmarshal(body, &request); err != nil || request.Response == nil {
+		return nil, statusCode("8.1", "6.1")
+	}
+	session := s.sessions[string(request.TransactionID)]
+	if session == nil {
+		return nil, statusCode("8.10.1", "3.9")
+	}
+	response := request.Response.First(bertlv.ContextSpecific.Constructed(0))
+	if response == nil || len(response.Children) < 4 {
+		delete(s.sessions, string(request.TransactionID))
+		return nil, statusCode("8.1", "6.1")
+	}
+	signed1 := response.First(bertlv.Universal.Constructed(16))
+	signature1 := response.First(bertlv.Application.Primitive(55))
+	if signed1 == nil || verify(response.At(2), signature1, signed1) != nil {
+		return nil, statusCode("8.1", "6.1")
+	}
+	if challenge := signed1.First(bertlv.ContextSpecific.Primitive(4)); challenge == nil || !bytes.Equal(challenge.Value, session.serverChallenge) {
+		return nil, statusCode("8.1", "6.1")
+	}
+	var matchingID string
+	if id := signed1.Select(bertlv.ContextSpecific.Constructed(0), bertlv.ContextSpecific.Primitive(0)); id != nil {
+		matchingID = string(id.Value)
+	}
+	if matchingID == "" && s.Events != nil {
+		delete(s.sessions, string(request.TransactionID))
+		return &sgp22.ES11AuthenticateClientResponse{
+			Header:        success(),
+			TransactionID: session.transactionID,
+			EventEntries:  s.Events,
+		}, nil
+	}
+	order := s.Orders[matchingID]
+	switch {
+	case order == nil:
+		return nil, statusCode("8.2.6", "3.8")
+	case order.Expired:
+		return nil, statusCode("8.8.5", "4.10")
+	case order.Downloaded:
+		return nil, statusCode("8.2", "3.7")
+	}
+	session.order = order
+	signed2 := bertlv.NewChildren(
+		bertlv.Universal.Constructed(16),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), session.transactionID),
+		bertlv.NewValue(bertlv.Universal.Primitive(1), []byte{boolean(order.ConfirmationCode != "")}),
+	)
+	return &sgp22.ES9AuthenticateClientResponse{
+		Header:          success(),
+		TransactionID:   session.transactionID,
+		ProfileMetadata: storeMetadata(order.Profile),
+		Signed2:         signed2,
+		Signature2:      s.pb.sign(signed2, signature1),
+		Certificate:     s.pb.TLV(),
+	}, nil
+}
+
+// endregion
+
+// region Section 5.6.2, ES9+.GetBoundProfilePackage
+
+func (s *Server) getBoundProfilePackage(body []byte) (any, *sgp22.StatusCodeData) {
+	var request sgp22.ES9BoundProfilePackageRequest
+	if err := json.Unmarshal(body, &request); err != nil || request.Response == nil {
+		return nil, statusCode("8.1", "6.1")
+	}
+	session := s.sessions[string(request.TransactionID)]
+	if session == nil || session.order == nil {
+		return nil, statusCode("8.10.1", "3.9")
+	}
+	response := request.Response.First(bertlv.ContextSpecific.Constructed(0))
+	if response == nil {
+		delete(s.sessions, string(request.TransactionID))
+		return nil, statusCode("8.1", "6.1")
+	}
+	signed2 := response.First(bertlv.Universal.Constructed(16))
+	if signed2 == nil {
+		return nil, statusCode("8.1", "6.1")
+	}
+	otpk := signed2.First(bertlv.Application.Primitive(73))
+	if otpk == nil {
+		return nil, statusCode("8.1", "6.1")
+	}
+	if code := session.order.ConfirmationCode; code != "" {
+		hashCC := signed2.First(bertlv.Universal.Primitive(4))
+		if hashCC == nil {
+			return nil, statusCode("8.2.7", "2.2")
+		}
+		if !bytes.Equal(hashCC.Value, hashConfirmationCode(code, session.transactionID)) {
+			return nil, statusCode("8.2.7", "3.8")
+		}
+	}
+	bpp, err := s.boundProfilePackage(session, otpk.Value)
+	if err != nil {
+		return nil, statusCode("8.1", "6.1")
+	}
+	session.order.Downloaded = true
+	delete(s.sessions, string(request.TransactionID))
+	return &sgp22.ES9BoundProfilePackageResponse{
+		Header:              success(),
+		TransactionID:       session.transactionID,
+		BoundProfilePackage: bpp,
+	}, nil
+}
+
+// boundProfilePackage builds the Bound Profile Package of the order.
+// The SCP03t protection is not applied, each '88' and '86' TLV only carries a dummy 8 bytes MAC.
+func (s *Server) boundProfilePackage(session *session, euiccOtpk []byte) (*bertlv.TLV, error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	mac := make([]byte, 8)
+	signed := []*bertlv.TLV{
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(2), []byte{0x01}),
+		bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), session.transactionID),
+		bertlv.NewChildren(
+			bertlv.ContextSpecific.Constructed(6),
+			bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), []byte{0x88}),
+			bertlv.NewValue(bertlv.ContextSpecific.Primitive(1), []byte{0x10}),
+			bertlv.NewValue(bertlv.ContextSpecific.Primitive(4), []byte("euicc-go")),
+		),
+		bertlv.NewValue(bertlv.Application.Primitive(73), key.PublicKey().Bytes()),
+	}
+	initialiseSecureChannel := bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(35),
+		append(signed, s.pb.sign(append(signed, bertlv.NewValue(bertlv.Application.Primitive(73), euiccOtpk))...))...,
+	)
+	configureISDP := make([]byte, 24)
+	_, _ = rand.Read(configureISDP)
+	metadata := storeMetadata(session.order.Profile)
+	size := session.order.ProfileSize
+	if size == 0 {
+		size = 1024
+	}
+	elements := make([]byte, size)
+	_, _ = rand.Read(elements)
+	sequenceOf86 := bertlv.NewChildren(bertlv.ContextSpecific.Constructed(3))
+	for len(elements) > 0 {
+		n := min(len(elements), 1020)
+		sequenceOf86.Children = append(sequenceOf86.Children, bertlv.NewValue(bertlv.ContextSpecific.Primitive(6), append(elements[:n:n], mac...)))
+		elements = elements[n:]
+	}
+	return bertlv.NewChildren(
+		bertlv.ContextSpecific.Constructed(54),
+		initialiseSecureChannel,
+		bertlv.NewChildren(bertlv.ContextSpecific.Constructed(0), bertlv.NewValue(bertlv.ContextSpecific.Primitive(7), append(configureISDP, mac...))),
+		bertlv.NewChildren(bertlv.ContextSpecific.Constructed(1), bertlv.NewValue(bertlv.ContextSpecific.Primitive(8), append(metadata.Bytes(), mac...))),
+		sequenceOf86,
+	), nil
+}
+
+// storeMetadata encodes the profile metadata as a StoreMetadata request.
+//
+// See https://aka.pw/sgp22/v2.5#page=146 (Section 5.5.3, ES8+.StoreMetadata)
+func storeMetadata(profile *sgp22.ProfileInfo) *bertlv.TLV {
+	return bertlv.NewChildrenIter(bertlv.ContextSpecific.Constructed(37), func(yield func(*bertlv.TLV) bool) {
+		if !yield(bertlv.NewValue(sgp22.TagICCID, profile.ICCID)) {
+			return
+		}
+		if !yield(bertlv.NewValue(sgp22.TagServiceProviderName, []byte(profile.ServiceProviderName))) {
+			return
+		}
+		if !yield(bertlv.NewValue(sgp22.TagProfileName, []byte(profile.ProfileName))) {
+			return
+		}
+		if len(profile.Icon) > 0 {
+			iconType := byte(0x00)
+			if profile.Icon.FileType() == "image/png" {
+				iconType = 0x01
+			}
+			if !yield(bertlv.NewValue(sgp22.TagProfileIconType, []byte{iconType})) {
+				return
+			}
+			if !yield(bertlv.NewValue(sgp22.TagProfileIcon, profile.Icon)) {
+				return
+			}
+		}
+		if !yield(bertlv.NewValue(sgp22.TagProfileClass, []byte{byte(profile.ProfileClass)})) {
+			return
+		}
+		if len(profile.NotificationConfigurationInfo) > 0 {
+			configs := bertlv.NewChildren(sgp22.TagNotificationConfigurationInfo)
+			for _, config := range profile.NotificationConfigurationInfo {
+				event, _ := config.ProfileManagementOperation.MarshalBinary()
+				configs.Children = append(configs.Children, bertlv.NewChildren(
+					bertlv.Universal.Constructed(16),
+					bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), event),
+					bertlv.NewValue(bertlv.ContextSpecific.Primitive(1), []byte(config.Address)),
+				))
+			}
+			if !yield(configs) {
+				return
+			}
+		}
+		if owner := profile.ProfileOwner; len(owner.PLMN) > 0 {
+			yield(bertlv.NewChildrenIter(sgp22.TagProfileOwner, func(yield func(*bertlv.TLV) bool) {
+				if !yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), owner.PLMN)) {
+					return
+				}
+				if len(owner.GID1) > 0 && !yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(1), owner.GID1)) {
+					return
+				}
+				if len(owner.GID2) > 0 {
+					yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(2), owner.GID2))
+				}
+			}))
+		}
+	})
+}
+
+// hashConfirmationCode returns SHA256(SHA256(Confirmation Code) | TransactionID).
+func hashConfirmationCode(code string, transactionID []byte) []byte {
+	hashed := sha256.Sum256([]byte(code))
+	hashed = sha256.Sum256(append(hashed[:], transactionID...))
+	return hashed[:]
+}
+
+// endregion
+
+// region Section 5.6.4, ES9+.HandleNotification
+
+func (s *Server) handleNotification(body []byte) (any, *sgp22.StatusCodeData) {
+	var request sgp22.ES9HandleNotificationRequest
+	if err := json.Unmarshal(body, &request); err != nil || request.PendingNotification == nil {
+		return nil, statusCode("8.1", "6.1")
+	}
+	s.Notifications = append(s.Notifications, request.PendingNotification)
+	return nil, nil
+}
+
+// endregion
+
+// region Section 5.6.5, ES9+.CancelSession
+
+func (s *Server) cancelSession(body []byte) (any, *sgp22.StatusCodeData) {
+	var request sgp22.ES9CancelSessionRequest
+	if err := json.Unmarshal(body, &request); err != nil || request.Response == nil {
+		return nil, statusCode("8.1", "6.1")
+	}
+	if s.sessions[string(request.TransactionID)] == nil {
+		return nil, statusCode("8.10.1", "3.9")
+	}
+	delete(s.sessions, string(request.TransactionID))
+	s.CanceledSessions = append(s.CanceledSessions, &CanceledSession{
+		TransactionID: request.TransactionID,
+		Response:      request.Response,
+	})
+	return &sgp22.ES9CancelSessionResponse{Header: success()}, nil
+}
+
+// endregion
+
+func boolean(value bool) byte {
+	if value {
+		return 0xFF
+	}
+	return 0x00
+}
+
+func parseOID(value string) (oid asn1.ObjectIdentifier, err error) {
+	for _, arc := range strings.Split(value, ".") {
+		var n int
+		if n, err = strconv.Atoi(arc); err != nil {
+			return nil, err
+		}
+		oid = append(oid, n)
+	}
+	return oid, nil
+}
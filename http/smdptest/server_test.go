@@ -0,0 +1,244 @@
+package smdptest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/http/smdptest"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const imei = "356938035643809"
+
+func setup(t *testing.T) (*smdptest.Server, *virtual.EUICC, *lpa.Client) {
+	server := smdptest.NewServer()
+	t.Cleanup(server.Close)
+	iccid, err := sgp22.NewICCID("8944476500001224158")
+	require.NoError(t, err)
+	server.Orders["QR-G-5C-1LS-1W1Z9P7"] = &smdptest.Order{
+		Profile: &sgp22.ProfileInfo{
+			ICCID:               iccid,
+			ServiceProviderName: "Test Operator",
+			ProfileName:         "Test Profile",
+			ProfileClass:        sgp22.ProfileClassOperational,
+		},
+	}
+	card := virtual.New()
+	client, err := lpa.New(&lpa.Options{Channel: card})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	client.HTTP.Client = server.Client()
+	return server, card, client
+}
+
+func activationCode(server *smdptest.Server) *lpa.ActivationCode {
+	return &lpa.ActivationCode{
+		SMDP:       server.SMDP(),
+		MatchingID: "QR-G-5C-1LS-1W1Z9P7",
+		IMEI:       imei,
+	}
+}
+
+func TestServer_DownloadProfile(t *testing.T) {
+	server, card, client := setup(t)
+	var stages []lpa.DownloadStage
+	result, err := client.DownloadProfile(context.Background(), activationCode(server), &lpa.DownloadOptions{
+		OnProgress: func(stage lpa.DownloadStage) { stages = append(stages, stage) },
+		OnConfirm: func(metadata *sgp22.ProfileInfo) bool {
+			assert.Equal(t, "Test Profile", metadata.ProfileName)
+			return true
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, stages, 3)
+	require.Len(t, card.Profiles, 1)
+	assert.Equal(t, card.Profiles[0].ISDPAID, result.ISDPAID())
+	assert.Equal(t, sgp22.ProfileDisabled, card.Profiles[0].State)
+	assert.True(t, server.Orders["QR-G-5C-1LS-1W1Z9P7"].Downloaded)
+
+	require.NotNil(t, result.SuccessResult)
+	assert.Nil(t, result.ErrorResult)
+	assert.NotEmpty(t, result.TransactionID)
+	assert.NotEmpty(t, result.Signature)
+	assert.Equal(t, card.SMDPOID, result.SMDPOID)
+	assert.Equal(t, sgp22.NotificationEventInstall, result.Notification.ProfileManagementOperation)
+	assert.Equal(t, card.Profiles[0].ICCID, result.Notification.ICCID)
+	notifications, err := client.ListNotification(context.Background(), sgp22.NotificationEventInstall)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, notifications[0].SequenceNumber, result.Notification.SequenceNumber)
+
+	results, err := client.ProcessAllNotifications(context.Background(), &lpa.ProcessNotificationsOptions{AutoRemove: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Removed)
+	assert.Len(t, server.Notifications, 1)
+	assert.Empty(t, card.Notifications)
+
+	_, err = client.DownloadProfile(context.Background(), activationCode(server), nil)
+	assert.EqualError(t, err, "BPP is not available for a new binding")
+}
+
+func TestServer_ConfirmationCode(t *testing.T) {
+	server, card, client := setup(t)
+	server.Orders["QR-G-5C-1LS-1W1Z9P7"].ConfirmationCode = "1234"
+
+	_, err := client.DownloadProfile(context.Background(), activationCode(server), nil)
+	assert.ErrorContains(t, err, "confirmation code is required")
+
+	_, err = client.DownloadProfile(context.Background(), activationCode(server), &lpa.DownloadOptions{
+		OnEnterConfirmationCode: func() string { return "0000" },
+	})
+	assert.EqualError(t, err, "Confirmation Code is refused")
+	var rspErr *sgp22.RSPError
+	require.ErrorAs(t, err, &rspErr)
+	assert.True(t, rspErr.IsConfirmationCodeRefused())
+	assert.Equal(t, "ES9+.GetBoundProfilePackage", rspErr.Function)
+	assert.Equal(t, "Failed", rspErr.Status)
+	assert.NotEmpty(t, rspErr.TransactionID)
+	assert.Len(t, server.CanceledSessions, 2)
+	assert.Empty(t, card.Profiles)
+
+	_, err = client.DownloadProfile(context.Background(), activationCode(server), &lpa.DownloadOptions{
+		OnEnterConfirmationCode: func() string { return "1234" },
+	})
+	assert.NoError(t, err)
+	assert.Len(t, card.Profiles, 1)
+}
+
+func TestServer_InstallationError(t *testing.T) {
+	server, card, client := setup(t)
+	card.FreeNonVolatileMemory = 0
+	result, err := client.DownloadProfile(context.Background(), activationCode(server), nil)
+	var installErr *sgp22.LoadBoundProfilePackageError
+	require.ErrorAs(t, err, &installErr)
+	assert.Equal(t, "loadProfileElements,installFailedDueToInsufficientMemoryForProfile", installErr.Error())
+	require.NotNil(t, result)
+	require.NotNil(t, result.ErrorResult)
+	assert.Nil(t, result.SuccessResult)
+	assert.Equal(t, byte(5), result.ErrorResult.BPPCommandID)
+	assert.Equal(t, byte(10), result.ErrorResult.ErrorReason)
+	assert.Nil(t, result.ISDPAID())
+	assert.Empty(t, card.Profiles)
+}
+
+func TestServer_Rejected(t *testing.T) {
+	server, card, client := setup(t)
+	_, err := client.DownloadProfile(context.Background(), activationCode(server), &lpa.DownloadOptions{
+		OnConfirm: func(*sgp22.ProfileInfo) bool { return false },
+	})
+	assert.NoError(t, err)
+	require.Len(t, server.CanceledSessions, 1)
+	assert.Empty(t, card.Profiles)
+	assert.False(t, server.Orders["QR-G-5C-1LS-1W1Z9P7"].Downloaded)
+}
+
+func TestServer_Canceled(t *testing.T) {
+	server, card, client := setup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := client.DownloadProfile(ctx, activationCode(server), &lpa.DownloadOptions{
+		OnProgress: func(stage lpa.DownloadStage) {
+			if stage == lpa.DownloadStageAuthenticateServer {
+				cancel()
+			}
+		},
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	require.Len(t, server.CanceledSessions, 1)
+	assert.Empty(t, card.Profiles)
+}
+
+func TestServer_Errors(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Function string
+		Status   *sgp22.ExecutionStatus
+		Order    func(*smdptest.Order)
+		Code     func(*lpa.ActivationCode)
+		Error    string
+	}{
+		{
+			Name:  "expired order",
+			Order: func(order *smdptest.Order) { order.Expired = true },
+			Error: "The Download order has expired",
+		},
+		{
+			Name:  "unknown matching id",
+			Code:  func(ac *lpa.ActivationCode) { ac.MatchingID = "UNKNOWN" },
+			Error: "MatchingID (AC_Token or EventID) is refused",
+		},
+		{
+			Name:     "initiate authentication",
+			Function: smdptest.InitiateAuthentication,
+			Status:   &sgp22.ExecutionStatus{Status: "Failed", StatusCodeData: &sgp22.StatusCodeData{SubjectCode: "8.8.2", ReasonCode: "3.1"}},
+			Error:    "None of the proposed Public Key Identifiers is supported by the SM-DP+",
+		},
+		{
+			Name:     "authenticate client",
+			Function: smdptest.AuthenticateClient,
+			Status:   &sgp22.ExecutionStatus{Status: "Failed", StatusCodeData: &sgp22.StatusCodeData{SubjectCode: "8.1.3", ReasonCode: "6.3"}},
+			Error:    "eUICC Certificate has expired",
+		},
+		{
+			Name:     "get bound profile package",
+			Function: smdptest.GetBoundProfilePackage,
+			Status:   &sgp22.ExecutionStatus{Status: "Failed", StatusCodeData: &sgp22.StatusCodeData{SubjectCode: "8.2.7", ReasonCode: "6.4"}},
+			Error:    "The maximum number of retries for the Confirmation Code has been exceeded",
+		},
+		{
+			Name:     "expired status",
+			Function: smdptest.AuthenticateClient,
+			Status:   &sgp22.ExecutionStatus{Status: "Expired", StatusCodeData: &sgp22.StatusCodeData{SubjectCode: "8.8.5", ReasonCode: "6.4"}},
+			Error:    "The maximum number of retries for the Profile download order has been exceeded",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			server, card, client := setup(t)
+			if c.Function != "" {
+				server.Errors[c.Function] = c.Status
+			}
+			if c.Order != nil {
+				c.Order(server.Orders["QR-G-5C-1LS-1W1Z9P7"])
+			}
+			ac := activationCode(server)
+			if c.Code != nil {
+				c.Code(ac)
+			}
+			_, err := client.DownloadProfile(context.Background(), ac, nil)
+			assert.ErrorContains(t, err, c.Error)
+			assert.Empty(t, card.Profiles)
+		})
+	}
+}
+
+func TestServer_OrderExpired(t *testing.T) {
+	server, _, client := setup(t)
+	server.Orders["QR-G-5C-1LS-1W1Z9P7"].Expired = true
+	_, err := client.DownloadProfile(context.Background(), activationCode(server), nil)
+	var rspErr *sgp22.RSPError
+	require.ErrorAs(t, err, &rspErr)
+	assert.True(t, rspErr.IsOrderExpired())
+	assert.False(t, rspErr.Transient())
+	assert.ErrorIs(t, err, &sgp22.RSPError{SubjectCode: "8.8.5", ReasonCode: "4.10"})
+}
+
+func TestServer_DiscoverProfiles(t *testing.T) {
+	server, _, client := setup(t)
+	server.Events = []*sgp22.EventEntry{{EventID: "EVENT-1", Address: "smdp.example.com"}}
+	imei, err := sgp22.NewIMEI(imei)
+	require.NoError(t, err)
+	profiles, err := client.DiscoverProfiles(context.Background(), &lpa.DiscoverProfilesOptions{
+		SMDSAddress: server.SMDP().Host,
+		IMEI:        imei,
+	})
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "EVENT-1", profiles[0].EventID)
+	assert.Equal(t, "smdp.example.com", profiles[0].SMDPAddress)
+}
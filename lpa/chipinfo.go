@@ -1,6 +1,10 @@
 package lpa
 
-import sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+import (
+	"context"
+
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
 
 // ChipInfo contains comprehensive information about the eUICC chip.
 // This is a convenience structure that aggregates data from multiple
@@ -35,32 +39,32 @@ type ChipInfo struct {
 // Example usage:
 //
 //	client, _ := lpa.New(&lpa.Options{Channel: driver})
-//	info, err := client.ChipInfo()
+//	info, err := client.ChipInfo(ctx)
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 //	fmt.Printf("EID: %s\n", info.EID)
 //	fmt.Printf("Free NV Memory: %d bytes\n", info.Info2.ExtCardResource.FreeNonVolatileMemory)
 //	fmt.Printf("Free Volatile Memory: %d bytes\n", info.Info2.ExtCardResource.FreeVolatileMemory)
-func (c *Client) ChipInfo() (*ChipInfo, error) {
+func (c *Client) ChipInfo(ctx context.Context) (*ChipInfo, error) {
 	var info ChipInfo
 	var err error
 
 	// EID is required - fail if we can't get it
-	eidBytes, err := c.EID()
+	eidBytes, err := c.EID(ctx)
 	if err != nil {
 		return nil, err
 	}
 	info.EID = hexEncodeEID(eidBytes)
 
 	// ConfiguredAddresses is optional - ignore errors
-	info.ConfiguredAddresses, _ = c.EUICCConfiguredAddresses()
+	info.ConfiguredAddresses, _ = c.EUICCConfiguredAddresses(ctx)
 
 	// EUICCInfo2 is highly recommended - ignore errors but try to get it
-	info.Info2, _ = c.EUICCInfo2Parsed()
+	info.Info2, _ = c.EUICCInfo2Parsed(ctx)
 
 	// RulesAuthorisationTable is optional - ignore errors
-	info.RulesAuthorisationTable, _ = c.GetRAT()
+	info.RulesAuthorisationTable, _ = c.GetRAT(ctx)
 
 	return &info, nil
 }
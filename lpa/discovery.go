@@ -27,8 +27,8 @@ type DiscoverProfilesOptions struct {
 	// - eSIM Discovery: "lpa.live.esimdiscovery.com"
 	SMDSAddress string
 
-	// IMEI is the device IMEI to use during authentication.
-	// This is optional and may be nil for devices without IMEI.
+	// IMEI is the BCD-coded device IMEI to use during authentication, see sgp22.NewIMEI.
+	// This is optional, it is read from the channel when it is nil and the channel implements driver.DeviceIdentity.
 	IMEI []byte
 }
 
@@ -54,7 +54,7 @@ const (
 // Example usage:
 //
 //	// Discover from default GSMA SM-DS
-//	profiles, err := client.DiscoverProfiles(nil)
+//	profiles, err := client.DiscoverProfiles(ctx, nil)
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
@@ -64,13 +64,13 @@ const (
 //	}
 //
 //	// Discover from custom SM-DS with IMEI
-//	profiles, err := client.DiscoverProfiles(&lpa.DiscoverProfilesOptions{
+//	profiles, err := client.DiscoverProfiles(ctx, &lpa.DiscoverProfilesOptions{
 //	    SMDSAddress: "prod.smds.rsp.goog",
-//	    IMEI: []byte("123456789012345"),
+//	    IMEI: imei, // sgp22.NewIMEI("356938035643809")
 //	})
 //
 // See https://aka.pw/sgp22/v2.5#page=212 (Section 5.8, SM-DS Discovery)
-func (c *Client) DiscoverProfiles(opts *DiscoverProfilesOptions) ([]*DiscoveredProfile, error) {
+func (c *Client) DiscoverProfiles(ctx context.Context, opts *DiscoverProfilesOptions) ([]*DiscoveredProfile, error) {
 	if opts == nil {
 		opts = &DiscoverProfilesOptions{}
 	}
@@ -88,7 +88,7 @@ func (c *Client) DiscoverProfiles(opts *DiscoverProfilesOptions) ([]*DiscoveredP
 	}
 
 	// Call the lower-level Discovery function
-	eventEntries, err := c.Discovery(smdsURL, opts.IMEI)
+	eventEntries, err := c.Discovery(ctx, smdsURL, opts.IMEI)
 	if err != nil {
 		return nil, fmt.Errorf("SM-DS discovery failed: %w", err)
 	}
@@ -129,7 +129,7 @@ func (c *Client) DiscoverProfiles(opts *DiscoverProfilesOptions) ([]*DiscoveredP
 //	}
 func (c *Client) DiscoverAndDownload(ctx context.Context, discoveryOpts *DiscoverProfilesOptions, downloadOpts *DownloadOptions) (*sgp22.LoadBoundProfilePackageResponse, error) {
 	// Discover available profiles
-	profiles, err := c.DiscoverProfiles(discoveryOpts)
+	profiles, err := c.DiscoverProfiles(ctx, discoveryOpts)
 	if err != nil {
 		return nil, fmt.Errorf("discovery failed: %w", err)
 	}
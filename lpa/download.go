@@ -14,6 +14,9 @@ import (
 
 // ActivationCode represents the activation code for downloading a profile.
 //
+// The IMEI is read from the channel when it is empty and the channel implements driver.DeviceIdentity.
+// The IMEI is optional, the download goes on without it when the channel does not report it or fails to read it.
+//
 // See https://aka.pw/sgp22/v2.5#page=113 (Section 4.1 Activation Code)
 type ActivationCode struct {
 	SMDP             *url.URL
@@ -73,9 +76,6 @@ func (ac *ActivationCode) validate() error {
 	if ac.SMDP == nil || ac.SMDP.Host == "" {
 		return errors.New("SM-DP+ is required")
 	}
-	if ac.IMEI == "" {
-		return errors.New("IMEI is required")
-	}
 	return nil
 }
 
@@ -109,7 +109,17 @@ type DownloadOptions struct {
 }
 
 // DownloadProfile downloads a profile using the provided activation code and options.
+// It returns the ProfileInstallationResult signed by the eUICC, also when the eUICC reports that the installation failed.
 func (c *Client) DownloadProfile(ctx context.Context, ac *ActivationCode, opts *DownloadOptions) (*sgp22.LoadBoundProfilePackageResponse, error) {
+	if ac.IMEI == "" {
+		imei, err := c.deviceIMEI(ctx)
+		if err != nil {
+			c.logger.Warn("[Download] continuing without the IMEI", "error", err)
+		}
+		withIMEI := *ac
+		withIMEI.IMEI = imei
+		ac = &withIMEI
+	}
 	if err := ac.validate(); err != nil {
 		return nil, err
 	}
@@ -118,16 +128,19 @@ func (c *Client) DownloadProfile(ctx context.Context, ac *ActivationCode, opts *
 		opts.OnProgress(DownloadStageAuthenticateClient)
 	}
 
-	clientResponse, metadata, ccRequired, err := c.authenticateClient(ac)
+	clientResponse, metadata, ccRequired, err := c.authenticateClient(ctx, ac)
 	if err != nil {
 		if clientResponse != nil && clientResponse.FunctionExecutionStatus().ExecutedSuccess() {
-			return nil, c.abort(ac, clientResponse.TransactionID, err, sgp22.CancelSessionReasonEndUserRejection)
+			return nil, c.abort(ctx, ac, clientResponse.TransactionID, err, sgp22.CancelSessionReasonEndUserRejection)
 		}
 		return nil, err
 	}
 
 	if c.isCanceled(ctx) || (opts != nil && opts.OnConfirm != nil && !opts.OnConfirm(metadata)) {
-		_, err := c.cancelSession(ac, clientResponse.TransactionID, sgp22.CancelSessionReasonEndUserRejection)
+		_, err := c.cancelSession(ctx, ac, clientResponse.TransactionID, sgp22.CancelSessionReasonEndUserRejection)
+		if err == nil {
+			err = ctx.Err()
+		}
 		return nil, err
 	}
 
@@ -136,7 +149,7 @@ func (c *Client) DownloadProfile(ctx context.Context, ac *ActivationCode, opts *
 			ac.ConfirmationCode = opts.OnEnterConfirmationCode()
 		}
 		if ac.ConfirmationCode == "" {
-			return nil, c.abort(
+			return nil, c.abort(ctx,
 				ac,
 				clientResponse.TransactionID,
 				errors.New("confirmation code is required"),
@@ -149,36 +162,42 @@ func (c *Client) DownloadProfile(ctx context.Context, ac *ActivationCode, opts *
 		opts.OnProgress(DownloadStageAuthenticateServer)
 	}
 	if c.isCanceled(ctx) {
-		_, err := c.cancelSession(ac, clientResponse.TransactionID, sgp22.CancelSessionReasonEndUserRejection)
+		_, err := c.cancelSession(ctx, ac, clientResponse.TransactionID, sgp22.CancelSessionReasonEndUserRejection)
+		if err == nil {
+			err = ctx.Err()
+		}
 		return nil, err
 	}
-	serverResponse, err := c.authenticateServer(ac, clientResponse)
+	serverResponse, err := c.authenticateServer(ctx, ac, clientResponse)
 	if err != nil {
-		return nil, c.abort(ac, clientResponse.TransactionID, err, sgp22.CancelSessionReasonEndUserRejection)
+		return nil, c.abort(ctx, ac, clientResponse.TransactionID, err, sgp22.CancelSessionReasonEndUserRejection)
 	}
 
 	if opts != nil && opts.OnProgress != nil {
 		opts.OnProgress(DownloadStageInstall)
 	}
 	if c.isCanceled(ctx) {
-		_, err := c.cancelSession(ac, serverResponse.TransactionID, sgp22.CancelSessionReasonEndUserRejection)
+		_, err := c.cancelSession(ctx, ac, serverResponse.TransactionID, sgp22.CancelSessionReasonEndUserRejection)
+		if err == nil {
+			err = ctx.Err()
+		}
 		return nil, err
 	}
-	result, err := c.install(serverResponse)
+	result, err := c.install(ctx, serverResponse)
 	if err != nil {
-		return result, c.abort(ac, serverResponse.TransactionID, err, sgp22.CancelSessionReasonLoadBppExecutionError)
+		return result, c.abort(ctx, ac, serverResponse.TransactionID, err, sgp22.CancelSessionReasonLoadBppExecutionError)
 	}
 	return result, nil
 }
 
-func (c *Client) install(bppResponse *sgp22.ES9BoundProfilePackageResponse) (*sgp22.LoadBoundProfilePackageResponse, error) {
+func (c *Client) install(ctx context.Context, bppResponse *sgp22.ES9BoundProfilePackageResponse) (*sgp22.LoadBoundProfilePackageResponse, error) {
 	segments, err := sgp22.SegmentedBoundProfilePackage(bppResponse.BoundProfilePackage)
 	if err != nil {
 		return nil, err
 	}
 	var r []byte
 	for _, command := range segments {
-		r, err = sgp22.InvokeRawAPDU(c.APDU, command)
+		r, err = sgp22.InvokeRawAPDU(ctx, c.APDU, command)
 		if err != nil {
 			return nil, err
 		}
@@ -197,8 +216,8 @@ func (c *Client) install(bppResponse *sgp22.ES9BoundProfilePackageResponse) (*sg
 	return &response, response.Valid()
 }
 
-func (c *Client) authenticateServer(ac *ActivationCode, clientResponse *sgp22.ES9AuthenticateClientResponse) (*sgp22.ES9BoundProfilePackageResponse, error) {
-	return c.PrepareDownload(ac.SMDP, &sgp22.PrepareDownloadRequest{
+func (c *Client) authenticateServer(ctx context.Context, ac *ActivationCode, clientResponse *sgp22.ES9AuthenticateClientResponse) (*sgp22.ES9BoundProfilePackageResponse, error) {
+	return c.PrepareDownload(ctx, ac.SMDP, &sgp22.PrepareDownloadRequest{
 		TransactionID:    clientResponse.TransactionID,
 		ProfileMetadata:  clientResponse.ProfileMetadata,
 		Signed2:          clientResponse.Signed2,
@@ -208,16 +227,18 @@ func (c *Client) authenticateServer(ac *ActivationCode, clientResponse *sgp22.ES
 	})
 }
 
-func (c *Client) authenticateClient(ac *ActivationCode) (*sgp22.ES9AuthenticateClientResponse, *sgp22.ProfileInfo, bool, error) {
-	initiateAuthenticationResponse, err := c.InitiateAuthentication(ac.SMDP)
+func (c *Client) authenticateClient(ctx context.Context, ac *ActivationCode) (*sgp22.ES9AuthenticateClientResponse, *sgp22.ProfileInfo, bool, error) {
+	initiateAuthenticationResponse, err := c.InitiateAuthentication(ctx, ac.SMDP)
 	if err != nil {
 		return nil, nil, false, err
 	}
-	imei, err := sgp22.NewIMEI(ac.IMEI)
-	if err != nil {
-		return nil, nil, false, err
+	var imei sgp22.IMEI
+	if ac.IMEI != "" {
+		if imei, err = sgp22.NewIMEI(ac.IMEI); err != nil {
+			return nil, nil, false, err
+		}
 	}
-	response, err := c.AuthenticateClient(ac.SMDP, &sgp22.AuthenticateServerRequest{
+	response, err := c.AuthenticateClient(ctx, ac.SMDP, &sgp22.AuthenticateServerRequest{
 		TransactionID: initiateAuthenticationResponse.TransactionID,
 		Signed1:       initiateAuthenticationResponse.Signed1,
 		Signature1:    initiateAuthenticationResponse.Signature1,
@@ -259,21 +280,24 @@ func (c *Client) isCanceled(ctx context.Context) bool {
 	}
 }
 
-func (c *Client) abort(ac *ActivationCode, transactionID []byte, err error, cancelReason sgp22.CancelSessionReason) error {
-	_, cancelErr := c.cancelSession(ac, transactionID, cancelReason)
+func (c *Client) abort(ctx context.Context, ac *ActivationCode, transactionID []byte, err error, cancelReason sgp22.CancelSessionReason) error {
+	_, cancelErr := c.cancelSession(ctx, ac, transactionID, cancelReason)
 	if cancelErr != nil {
 		return fmt.Errorf("%w (cancel session error: %v)", err, cancelErr)
 	}
 	return err
 }
 
-func (c *Client) cancelSession(ac *ActivationCode, transactionID []byte, reason sgp22.CancelSessionReason) (*sgp22.ES9CancelSessionResponse, error) {
-	cancelSessionRequest, err := sgp22.InvokeAPDU(c.APDU, &sgp22.CancelSessionRequest{
+func (c *Client) cancelSession(ctx context.Context, ac *ActivationCode, transactionID []byte, reason sgp22.CancelSessionReason) (*sgp22.ES9CancelSessionResponse, error) {
+	// The session must be cancelled even when the download was cancelled by the context,
+	// the timeouts of the channel and of the HTTP client still bound the calls.
+	ctx = context.WithoutCancel(ctx)
+	cancelSessionRequest, err := sgp22.InvokeAPDU(ctx, c.APDU, &sgp22.CancelSessionRequest{
 		TransactionID: transactionID,
 		Reason:        reason,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return sgp22.InvokeHTTP(c.HTTP, ac.SMDP, cancelSessionRequest)
+	return sgp22.InvokeHTTP(ctx, c.HTTP, ac.SMDP, cancelSessionRequest)
 }
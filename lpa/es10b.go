@@ -1,6 +1,7 @@
 package lpa
 
 import (
+	"context"
 	"errors"
 	"net/url"
 
@@ -8,8 +9,8 @@ import (
 	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
 )
 
-func (c *Client) EUICCChallenge() ([]byte, error) {
-	euiccChallenge, err := sgp22.InvokeAPDU(c.APDU, new(sgp22.GetEuiccChallengeRequest))
+func (c *Client) EUICCChallenge(ctx context.Context) ([]byte, error) {
+	euiccChallenge, err := sgp22.InvokeAPDU(ctx, c.APDU, new(sgp22.GetEuiccChallengeRequest))
 	if err != nil {
 		return nil, err
 	}
@@ -19,8 +20,8 @@ func (c *Client) EUICCChallenge() ([]byte, error) {
 // EUICCInfo1 retrieves the eUICC information (version 1).
 //
 // See https://aka.pw/sgp22/v2.5#page=187 (Section 5.7.8, ES10b.GetEUICCInfo)
-func (c *Client) EUICCInfo1() (*bertlv.TLV, error) {
-	euiccInfo1, err := sgp22.InvokeAPDU(c.APDU, &sgp22.GetEuiccInfoRequest{Version: 1})
+func (c *Client) EUICCInfo1(ctx context.Context) (*bertlv.TLV, error) {
+	euiccInfo1, err := sgp22.InvokeAPDU(ctx, c.APDU, &sgp22.GetEuiccInfoRequest{Version: 1})
 	if err != nil {
 		return nil, err
 	}
@@ -31,8 +32,8 @@ func (c *Client) EUICCInfo1() (*bertlv.TLV, error) {
 // For parsed structure, use EUICCInfo2Parsed().
 //
 // See https://aka.pw/sgp22/v2.5#page=187 (Section 5.7.8, ES10b.GetEUICCInfo)
-func (c *Client) EUICCInfo2() (*bertlv.TLV, error) {
-	euiccInfo1, err := sgp22.InvokeAPDU(c.APDU, &sgp22.GetEuiccInfoRequest{Version: 2})
+func (c *Client) EUICCInfo2(ctx context.Context) (*bertlv.TLV, error) {
+	euiccInfo1, err := sgp22.InvokeAPDU(ctx, c.APDU, &sgp22.GetEuiccInfoRequest{Version: 2})
 	if err != nil {
 		return nil, err
 	}
@@ -43,8 +44,8 @@ func (c *Client) EUICCInfo2() (*bertlv.TLV, error) {
 // This provides easy access to all eUICC information including memory/storage details.
 //
 // See https://aka.pw/sgp22/v2.5#page=187 (Section 5.7.8, ES10b.GetEUICCInfo)
-func (c *Client) EUICCInfo2Parsed() (*sgp22.EUICCInfo2, error) {
-	tlv, err := c.EUICCInfo2()
+func (c *Client) EUICCInfo2Parsed(ctx context.Context) (*sgp22.EUICCInfo2, error) {
+	tlv, err := c.EUICCInfo2(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -60,29 +61,29 @@ func (c *Client) EUICCInfo2Parsed() (*sgp22.EUICCInfo2, error) {
 // AuthenticateClient authenticates the client to the eUICC.
 //
 // See https://aka.pw/sgp22/v2.5#page=195 (Section 5.7.13, ES10b.AuthenticateClient)
-func (c *Client) AuthenticateClient(address *url.URL, request *sgp22.AuthenticateServerRequest) (*sgp22.ES9AuthenticateClientResponse, error) {
-	authenticateClientRequest, err := sgp22.InvokeAPDU(c.APDU, request)
+func (c *Client) AuthenticateClient(ctx context.Context, address *url.URL, request *sgp22.AuthenticateServerRequest) (*sgp22.ES9AuthenticateClientResponse, error) {
+	authenticateClientRequest, err := sgp22.InvokeAPDU(ctx, c.APDU, request)
 	if err != nil {
 		return nil, err
 	}
-	return sgp22.InvokeHTTP(c.HTTP, address, authenticateClientRequest)
+	return sgp22.InvokeHTTP(ctx, c.HTTP, address, authenticateClientRequest)
 }
 
 // PrepareDownload prepares the eUICC for a profile download.
 //
 // See https://aka.pw/sgp22/v2.5#page=184 (Section 5.7.13, ES10b.PrepareDownload)
-func (c *Client) PrepareDownload(address *url.URL, request *sgp22.PrepareDownloadRequest) (*sgp22.ES9BoundProfilePackageResponse, error) {
-	boundProfilePackageRequest, err := sgp22.InvokeAPDU(c.APDU, request)
+func (c *Client) PrepareDownload(ctx context.Context, address *url.URL, request *sgp22.PrepareDownloadRequest) (*sgp22.ES9BoundProfilePackageResponse, error) {
+	boundProfilePackageRequest, err := sgp22.InvokeAPDU(ctx, c.APDU, request)
 	if err != nil {
 		return nil, err
 	}
-	return sgp22.InvokeHTTP(c.HTTP, address, boundProfilePackageRequest)
+	return sgp22.InvokeHTTP(ctx, c.HTTP, address, boundProfilePackageRequest)
 }
 
 // ListNotification retrieves a list of notifications from the eUICC.
 //
 // See https://aka.pw/sgp22/v2.5#page=191 (Section 5.7.9, ES10b.ListNotification)
-func (c *Client) ListNotification(filters ...sgp22.NotificationEvent) ([]*sgp22.NotificationMetadata, error) {
+func (c *Client) ListNotification(ctx context.Context, filters ...sgp22.NotificationEvent) ([]*sgp22.NotificationMetadata, error) {
 	var request sgp22.ListNotificationRequest
 	request.Filter = make(map[sgp22.NotificationEvent]bool)
 	if len(filters) == 0 {
@@ -96,7 +97,7 @@ func (c *Client) ListNotification(filters ...sgp22.NotificationEvent) ([]*sgp22.
 	for _, event := range filters {
 		request.Filter[event] = true
 	}
-	response, err := sgp22.InvokeAPDU(c.APDU, &request)
+	response, err := sgp22.InvokeAPDU(ctx, c.APDU, &request)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +109,7 @@ func (c *Client) ListNotification(filters ...sgp22.NotificationEvent) ([]*sgp22.
 // Search Criteria:
 // - [sgp22.SequenceNumber]: The sequence number of the notification.
 // - [sgp22.NotificationEvent]: The event type of the notification.
-func (c *Client) RetrieveNotificationList(searchCriteria any) ([]*sgp22.PendingNotification, error) {
+func (c *Client) RetrieveNotificationList(ctx context.Context, searchCriteria any) ([]*sgp22.PendingNotification, error) {
 	var request sgp22.RetrieveNotificationsListRequest
 	switch v := searchCriteria.(type) {
 	case sgp22.SequenceNumber:
@@ -118,7 +119,7 @@ func (c *Client) RetrieveNotificationList(searchCriteria any) ([]*sgp22.PendingN
 	default:
 		return nil, errors.New("searchCriteria must be of type sgp22.SequenceNumber or sgp22.NotificationEvent")
 	}
-	response, err := sgp22.InvokeAPDU(c.APDU, &request)
+	response, err := sgp22.InvokeAPDU(ctx, c.APDU, &request)
 	if err != nil {
 		return nil, err
 	}
@@ -128,8 +129,8 @@ func (c *Client) RetrieveNotificationList(searchCriteria any) ([]*sgp22.PendingN
 // RemoveNotificationFromList removes a notification from the eUICC's notification list.
 //
 // See https://aka.pw/sgp22/v2.5#page=193 (Section 5.7.11, ES10b.RemoveNotificationFromList)
-func (c *Client) RemoveNotificationFromList(sequenceNumber sgp22.SequenceNumber) error {
-	_, err := sgp22.InvokeAPDU(c.APDU, &sgp22.NotificationSentRequest{
+func (c *Client) RemoveNotificationFromList(ctx context.Context, sequenceNumber sgp22.SequenceNumber) error {
+	_, err := sgp22.InvokeAPDU(ctx, c.APDU, &sgp22.NotificationSentRequest{
 		SequenceNumber: sequenceNumber,
 	})
 	return err
@@ -140,8 +141,8 @@ func (c *Client) RemoveNotificationFromList(sequenceNumber sgp22.SequenceNumber)
 // and profile policy rule flags.
 //
 // Returns an empty list if no RAT is configured on the eUICC.
-func (c *Client) GetRAT() ([]*sgp22.RulesAuthorisationTable, error) {
-	response, err := sgp22.InvokeAPDU(c.APDU, new(sgp22.GetRATRequest))
+func (c *Client) GetRAT(ctx context.Context) ([]*sgp22.RulesAuthorisationTable, error) {
+	response, err := sgp22.InvokeAPDU(ctx, c.APDU, new(sgp22.GetRATRequest))
 	if err != nil {
 		return nil, err
 	}
@@ -1,26 +1,41 @@
 package lpa
 
 import (
+	"context"
+	"fmt"
 	"net/url"
 
 	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
 )
 
 // Discovery discovers the downloadable profiles from SM-DS.
+// The IMEI, BCD-coded, is read from the channel when it is nil and the channel implements driver.DeviceIdentity.
+// The IMEI is optional, the discovery goes on without it when the channel fails to read it.
 //
 // See https://aka.pw/sgp22/v2.5#page=212 (Section 5.8.2, ES11.AuthenticateClient)
-func (c *Client) Discovery(address *url.URL, IMEI []byte) ([]*sgp22.EventEntry, error) {
-	response, err := c.InitiateAuthentication(address)
+func (c *Client) Discovery(ctx context.Context, address *url.URL, IMEI []byte) ([]*sgp22.EventEntry, error) {
+	response, err := c.InitiateAuthentication(ctx, address)
 	if err != nil {
 		return nil, err
 	}
+	if IMEI == nil {
+		imei, err := c.deviceIMEI(ctx)
+		if err != nil {
+			c.logger.Warn("[Discovery] continuing without the IMEI", "error", err)
+		}
+		if imei != "" {
+			if IMEI, err = sgp22.NewIMEI(imei); err != nil {
+				return nil, fmt.Errorf("invalid IMEI of the device %q: %w", imei, err)
+			}
+		}
+	}
 	cardRequest := response.CardRequest()
 	cardRequest.IMEI = IMEI
-	request, err := sgp22.InvokeAPDU(c.APDU, cardRequest)
+	request, err := sgp22.InvokeAPDU(ctx, c.APDU, cardRequest)
 	if err != nil {
 		return nil, err
 	}
-	clientResponse, err := sgp22.InvokeHTTP(c.HTTP, address, &sgp22.ES11AuthenticateClientRequest{
+	clientResponse, err := sgp22.InvokeHTTP(ctx, c.HTTP, address, &sgp22.ES11AuthenticateClientRequest{
 		ES9AuthenticateClientRequest: request,
 	})
 	if err != nil {
@@ -1,9 +1,11 @@
 package lpa
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/KilimcininKorOglu/euicc-go/apdu"
@@ -22,6 +24,12 @@ type Client struct {
 	APDU sgp22.Transmitter
 
 	transmitter driver.Transmitter
+	lock        *driver.FileLock
+	logger      *slog.Logger
+	// identity is the channel when it implements driver.DeviceIdentity, its IMEI is read once.
+	identity  driver.DeviceIdentity
+	imeiMutex sync.Mutex
+	imei      string
 }
 
 // Option is the configuration for the LPA client.
@@ -39,8 +47,19 @@ type Options struct {
 	Logger *slog.Logger
 	// Timeout is the timeout for the HTTP client. It defaults to 30 seconds.
 	Timeout time.Duration
+	// LockFile is the path of a file locked from New to Close, so that other processes locking the same file
+	// wait for the client to be closed before using the channel. It is usually driver.LockPath of the channel URI.
+	// No lock is taken when it is empty.
+	LockFile string
+	// LockTimeout is how long New waits for another process to release the lock file. It defaults to 30 seconds.
+	LockTimeout time.Duration
+	// Lock is a lock the caller took before opening the channel, instead of LockFile, it is released by Close.
+	Lock *driver.FileLock
 }
 
+// DefaultLockTimeout is how long New waits for the lock file by default.
+const DefaultLockTimeout = 30 * time.Second
+
 func (opts *Options) validateAdminProtocolVersion() error {
 	// If the version starts with "v", remove it
 	if opts.AdminProtocolVersion[0] == 'v' {
@@ -89,6 +108,9 @@ func (opts *Options) setDefaults() {
 	if opts.Logger == nil {
 		opts.Logger = slog.Default()
 	}
+	if opts.LockTimeout == 0 {
+		opts.LockTimeout = DefaultLockTimeout
+	}
 }
 
 // Normalize normalizes the options by setting default values and validating them.
@@ -98,16 +120,34 @@ func (opts *Options) Normalize() error {
 }
 
 // New creates a new LPA client with the given options.
+// The channel is disconnected and the lock released when New fails, so the caller has nothing to release.
 func New(opts *Options) (*Client, error) {
-	var c Client
+	c := Client{lock: opts.Lock}
 	var err error
 	if err := opts.Normalize(); err != nil {
+		if opts.Channel != nil {
+			_ = opts.Channel.Disconnect()
+		}
+		if c.lock != nil {
+			_ = c.lock.Unlock()
+		}
 		return nil, err
 	}
+	if opts.LockFile != "" && c.lock == nil {
+		if c.lock, err = driver.Lock(opts.LockFile, opts.LockTimeout); err != nil {
+			_ = opts.Channel.Disconnect()
+			return nil, err
+		}
+	}
 	if c.transmitter, err = driver.NewTransmitter(opts.Logger, opts.Channel, opts.AID, opts.MSS); err != nil {
+		if c.lock != nil {
+			_ = c.lock.Unlock()
+		}
 		return nil, err
 	}
 	c.APDU = c.transmitter
+	c.logger = opts.Logger
+	c.identity, _ = opts.Channel.(driver.DeviceIdentity)
 	c.HTTP = &http.Client{
 		Client:               driver.NewHTTPClient(opts.Logger, opts.Timeout),
 		AdminProtocolVersion: opts.AdminProtocolVersion,
@@ -115,8 +155,34 @@ func New(opts *Options) (*Client, error) {
 	return &c, nil
 }
 
-// Close closes the LPA client and the underlying APDU transmitter.
+// deviceIMEI returns the IMEI of the device when the channel implements driver.DeviceIdentity, and "" otherwise.
+func (c *Client) deviceIMEI(ctx context.Context) (string, error) {
+	if c.identity == nil {
+		return "", nil
+	}
+	c.imeiMutex.Lock()
+	defer c.imeiMutex.Unlock()
+	if c.imei == "" {
+		// The modem is asked between the commands sent to the eUICC, some drivers share one connection for both.
+		var imei string
+		err := c.transmitter.Exclusive(func() (err error) {
+			imei, err = c.identity.IMEI(ctx)
+			return err
+		})
+		if err != nil {
+			return "", fmt.Errorf("read the IMEI of the device: %w", err)
+		}
+		c.imei = imei
+	}
+	return c.imei, nil
+}
+
+// Close closes the LPA client and the underlying APDU transmitter, and releases the lock file.
 // You should call this method when you are done using the client to release resources.
 func (c *Client) Close() error {
-	return c.transmitter.Close()
+	err := c.transmitter.Close()
+	if c.lock != nil {
+		err = errors.Join(err, c.lock.Unlock())
+	}
+	return err
 }
@@ -0,0 +1,148 @@
+package lpa_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/http/smdptest"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// channel is a virtual eUICC recording whether it was disconnected.
+type channel struct {
+	*virtual.EUICC
+	disconnected bool
+}
+
+func (c *channel) Disconnect() error {
+	c.disconnected = true
+	return c.EUICC.Disconnect()
+}
+
+func TestNew_DisconnectsOnError(t *testing.T) {
+	card := &channel{EUICC: virtual.New()}
+	_, err := lpa.New(&lpa.Options{Channel: card, MSS: 255})
+	assert.EqualError(t, err, "invalid maximum APDU size: 255")
+	assert.True(t, card.disconnected)
+
+	lockFile := t.TempDir() + "/euicc.lock"
+	lock, err := driver.Lock(lockFile, time.Second)
+	require.NoError(t, err)
+	defer lock.Unlock()
+	card = &channel{EUICC: virtual.New()}
+	_, err = lpa.New(&lpa.Options{Channel: card, LockFile: lockFile, LockTimeout: 10 * time.Millisecond})
+	assert.ErrorIs(t, err, driver.ErrLocked)
+	assert.True(t, card.disconnected)
+}
+
+func TestNew_LockFile(t *testing.T) {
+	lockFile := filepath.Join(t.TempDir(), "euicc.lock")
+	client, err := lpa.New(&lpa.Options{Channel: virtual.New(), LockFile: lockFile})
+	require.NoError(t, err)
+
+	_, err = lpa.New(&lpa.Options{Channel: virtual.New(), LockFile: lockFile, LockTimeout: 10 * time.Millisecond})
+	assert.ErrorIs(t, err, driver.ErrLocked)
+
+	require.NoError(t, client.Close())
+	client, err = lpa.New(&lpa.Options{Channel: virtual.New(), LockFile: lockFile, LockTimeout: 10 * time.Millisecond})
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+}
+
+// modem is a virtual eUICC in a modem reporting its IMEI, recording the data of the commands it receives.
+type modem struct {
+	*virtual.EUICC
+	imei  string
+	err   error
+	reads int
+	data  []byte
+}
+
+func (m *modem) IMEI(context.Context) (string, error) {
+	m.reads++
+	return m.imei, m.err
+}
+
+func (m *modem) Transmit(ctx context.Context, command []byte) ([]byte, error) {
+	m.data = append(m.data, command[min(5, len(command)):]...)
+	return m.EUICC.Transmit(ctx, command)
+}
+
+// newServer returns an SM-DP+ and SM-DS with an order of a profile and an event.
+func newServer(t *testing.T) *smdptest.Server {
+	server := smdptest.NewServer()
+	t.Cleanup(server.Close)
+	iccid, err := sgp22.NewICCID("8944476500001224158")
+	require.NoError(t, err)
+	server.Orders["QR-G-5C-1LS-1W1Z9P7"] = &smdptest.Order{
+		Profile: &sgp22.ProfileInfo{
+			ICCID:               iccid,
+			ServiceProviderName: "Test Operator",
+			ProfileName:         "Test Profile",
+			ProfileClass:        sgp22.ProfileClassOperational,
+		},
+	}
+	server.Events = []*sgp22.EventEntry{{EventID: "EVENT-1", Address: "smdp.example.com"}}
+	return server
+}
+
+// newClient returns a client of the card reaching the server.
+func newClient(t *testing.T, server *smdptest.Server, card apdu.SmartCardChannel) *lpa.Client {
+	client, err := lpa.New(&lpa.Options{Channel: card})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	client.HTTP.Client = server.Client()
+	return client
+}
+
+func TestClient_DeviceIMEI(t *testing.T) {
+	server := newServer(t)
+	card := &modem{EUICC: virtual.New(), imei: "490154203237518"}
+	client := newClient(t, server, card)
+	bcd, err := sgp22.NewIMEI(card.imei)
+	require.NoError(t, err)
+
+	profiles, err := client.DiscoverProfiles(context.Background(), &lpa.DiscoverProfilesOptions{SMDSAddress: server.SMDP().Host})
+	require.NoError(t, err)
+	assert.Len(t, profiles, 1)
+	assert.True(t, bytes.Contains(card.data, bcd), "the device info carries the IMEI of the modem")
+
+	ac := &lpa.ActivationCode{SMDP: server.SMDP(), MatchingID: "QR-G-5C-1LS-1W1Z9P7"}
+	_, err = client.DownloadProfile(context.Background(), ac, nil)
+	require.NoError(t, err)
+	assert.Empty(t, ac.IMEI)
+	assert.Len(t, card.Profiles, 1)
+	assert.Equal(t, 1, card.reads, "the IMEI is read once")
+}
+
+func TestClient_DeviceIMEIError(t *testing.T) {
+	server := newServer(t)
+	ac := &lpa.ActivationCode{SMDP: server.SMDP(), MatchingID: "QR-G-5C-1LS-1W1Z9P7"}
+	card := &modem{EUICC: virtual.New(), err: errors.New("no answer")}
+	client := newClient(t, server, card)
+	// The discovery and the download go on without the IMEI.
+	profiles, err := client.DiscoverProfiles(context.Background(), &lpa.DiscoverProfilesOptions{SMDSAddress: server.SMDP().Host})
+	require.NoError(t, err)
+	assert.Len(t, profiles, 1)
+	_, err = client.DownloadProfile(context.Background(), ac, nil)
+	require.NoError(t, err)
+	assert.Len(t, card.Profiles, 1)
+	assert.Equal(t, 2, card.reads)
+
+	// The channel of a card outside a modem has no IMEI.
+	server = newServer(t)
+	plain := virtual.New()
+	_, err = newClient(t, server, plain).DownloadProfile(context.Background(), &lpa.ActivationCode{SMDP: server.SMDP(), MatchingID: "QR-G-5C-1LS-1W1Z9P7"}, nil)
+	require.NoError(t, err)
+	assert.Len(t, plain.Profiles, 1)
+}
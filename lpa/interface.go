@@ -0,0 +1,33 @@
+package lpa
+
+import (
+	"context"
+
+	"github.com/KilimcininKorOglu/euicc-go/bertlv"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+// Interface is the set of profile management operations of a [Client].
+// It is implemented by a Client using a local eUICC and by remote.Client using an eUICC of another host,
+// so that callers are agnostic to where the eUICC is.
+type Interface interface {
+	EID(ctx context.Context) ([]byte, error)
+	ChipInfo(ctx context.Context) (*ChipInfo, error)
+	EUICCConfiguredAddresses(ctx context.Context) (*EUICCConfiguredAddresses, error)
+	SetDefaultDPAddress(ctx context.Context, address string) error
+	ListProfile(ctx context.Context, searchCriteria any, tags []bertlv.Tag) ([]*sgp22.ProfileInfo, error)
+	EnableProfile(ctx context.Context, identifier any, refresh bool) error
+	DisableProfile(ctx context.Context, identifier any, refresh bool) error
+	DeleteProfile(ctx context.Context, identifier any) error
+	SetNickname(ctx context.Context, iccid sgp22.ICCID, nickname string) error
+	MemoryReset(ctx context.Context) error
+	ListNotification(ctx context.Context, filters ...sgp22.NotificationEvent) ([]*sgp22.NotificationMetadata, error)
+	RemoveNotificationFromList(ctx context.Context, sequenceNumber sgp22.SequenceNumber) error
+	ProcessNotifications(ctx context.Context, opts *ProcessNotificationsOptions, sequenceNumbers ...sgp22.SequenceNumber) ([]*NotificationProcessResult, error)
+	ProcessAllNotifications(ctx context.Context, opts *ProcessNotificationsOptions) ([]*NotificationProcessResult, error)
+	DiscoverProfiles(ctx context.Context, opts *DiscoverProfilesOptions) ([]*DiscoveredProfile, error)
+	DownloadProfile(ctx context.Context, ac *ActivationCode, opts *DownloadOptions) (*sgp22.LoadBoundProfilePackageResponse, error)
+	Close() error
+}
+
+var _ Interface = (*Client)(nil)
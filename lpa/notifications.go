@@ -1,6 +1,7 @@
 package lpa
 
 import (
+	"context"
 	"fmt"
 
 	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
@@ -40,7 +41,7 @@ type ProcessNotificationsOptions struct {
 //
 // Example usage:
 //
-//	results, err := client.ProcessNotifications(
+//	results, err := client.ProcessNotifications(ctx,
 //	    &lpa.ProcessNotificationsOptions{
 //	        AutoRemove: true,
 //	        ContinueOnError: true,
@@ -55,7 +56,7 @@ type ProcessNotificationsOptions struct {
 //	        fmt.Printf("Failed to process %d: %v\n", result.SequenceNumber, result.Error)
 //	    }
 //	}
-func (c *Client) ProcessNotifications(opts *ProcessNotificationsOptions, sequenceNumbers ...sgp22.SequenceNumber) ([]*NotificationProcessResult, error) {
+func (c *Client) ProcessNotifications(ctx context.Context, opts *ProcessNotificationsOptions, sequenceNumbers ...sgp22.SequenceNumber) ([]*NotificationProcessResult, error) {
 	if opts == nil {
 		opts = &ProcessNotificationsOptions{}
 	}
@@ -74,7 +75,7 @@ func (c *Client) ProcessNotifications(opts *ProcessNotificationsOptions, sequenc
 		}
 
 		// Process this single notification
-		removed, err := c.processSingleNotification(seqNum, opts.AutoRemove)
+		removed, err := c.processSingleNotification(ctx, seqNum, opts.AutoRemove)
 		result.Removed = removed
 
 		if err != nil {
@@ -102,7 +103,7 @@ func (c *Client) ProcessNotifications(opts *ProcessNotificationsOptions, sequenc
 //
 // Example usage:
 //
-//	results, err := client.ProcessAllNotifications(&lpa.ProcessNotificationsOptions{
+//	results, err := client.ProcessAllNotifications(ctx, &lpa.ProcessNotificationsOptions{
 //	    AutoRemove: true,
 //	    ContinueOnError: true,
 //	})
@@ -110,13 +111,13 @@ func (c *Client) ProcessNotifications(opts *ProcessNotificationsOptions, sequenc
 //	    log.Fatal(err)
 //	}
 //	fmt.Printf("Processed %d notifications\n", len(results))
-func (c *Client) ProcessAllNotifications(opts *ProcessNotificationsOptions) ([]*NotificationProcessResult, error) {
+func (c *Client) ProcessAllNotifications(ctx context.Context, opts *ProcessNotificationsOptions) ([]*NotificationProcessResult, error) {
 	if opts == nil {
 		opts = &ProcessNotificationsOptions{}
 	}
 
 	// List all pending notifications
-	notifications, err := c.ListNotification()
+	notifications, err := c.ListNotification(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list notifications: %w", err)
 	}
@@ -132,14 +133,14 @@ func (c *Client) ProcessAllNotifications(opts *ProcessNotificationsOptions) ([]*
 	}
 
 	// Process all notifications
-	return c.ProcessNotifications(opts, sequenceNumbers...)
+	return c.ProcessNotifications(ctx, opts, sequenceNumbers...)
 }
 
 // processSingleNotification processes a single notification identified by its sequence number.
 // It returns true if the notification was removed from the eUICC, and any error encountered.
-func (c *Client) processSingleNotification(seqNum sgp22.SequenceNumber, autoRemove bool) (bool, error) {
+func (c *Client) processSingleNotification(ctx context.Context, seqNum sgp22.SequenceNumber, autoRemove bool) (bool, error) {
 	// Step 1: Retrieve notification content from eUICC
-	notifications, err := c.RetrieveNotificationList(seqNum)
+	notifications, err := c.RetrieveNotificationList(ctx, seqNum)
 	if err != nil {
 		return false, fmt.Errorf("retrieve notification: %w", err)
 	}
@@ -152,13 +153,13 @@ func (c *Client) processSingleNotification(seqNum sgp22.SequenceNumber, autoRemo
 
 	// Step 2: Send notification to SM-DP+ server
 	// HandleNotification internally uses notification.Notification.Address
-	if err := c.HandleNotification(notification); err != nil {
+	if err := c.HandleNotification(ctx, notification); err != nil {
 		return false, fmt.Errorf("handle notification: %w", err)
 	}
 
 	// Step 3: Optionally remove notification from eUICC
 	if autoRemove {
-		if err := c.RemoveNotificationFromList(seqNum); err != nil {
+		if err := c.RemoveNotificationFromList(ctx, seqNum); err != nil {
 			// Notification was sent successfully but removal failed
 			// This is not a critical error, so we log it but don't fail
 			return false, fmt.Errorf("remove notification: %w", err)
@@ -0,0 +1,217 @@
+// Package lpac converts the results of the LPA client to the JSON data written by lpac,
+// so that the tools parsing the output of lpac can read the output of euicc-go.
+package lpac
+
+import (
+	"encoding/base64"
+
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+// ChipInfo is the data of lpac chip info.
+type ChipInfo struct {
+	EID                     string                `json:"eidValue"`
+	ConfiguredAddresses     *Addresses            `json:"EuiccConfiguredAddresses"`
+	EUICCInfo2              *EUICCInfo2           `json:"EUICCInfo2"`
+	RulesAuthorisationTable []*RulesAuthorisation `json:"rulesAuthorisationTable"`
+}
+
+type Addresses struct {
+	DefaultDPAddress *string `json:"defaultDpAddress"`
+	RootDSAddress    *string `json:"rootDsAddress"`
+}
+
+type EUICCInfo2 struct {
+	ProfileVersion                 string                  `json:"profileVersion"`
+	SVN                            string                  `json:"svn"`
+	EUICCFirmwareVer               string                  `json:"euiccFirmwareVer"`
+	ExtCardResource                ExtCardResource         `json:"extCardResource"`
+	UICCCapability                 []string                `json:"uiccCapability"`
+	TS102241Version                *string                 `json:"ts102241Version"`
+	GlobalPlatformVersion          *string                 `json:"globalplatformVersion"`
+	RSPCapability                  []string                `json:"rspCapability"`
+	EUICCCiPKIdListForVerification []string                `json:"euiccCiPKIdListForVerification"`
+	EUICCCiPKIdListForSigning      []string                `json:"euiccCiPKIdListForSigning"`
+	EUICCCategory                  *string                 `json:"euiccCategory"`
+	ForbiddenProfilePolicyRules    []string                `json:"forbiddenProfilePolicyRules"`
+	PPVersion                      string                  `json:"ppVersion"`
+	SASAccreditationNumber         string                  `json:"sasAcreditationNumber"`
+	CertificationDataObject        CertificationDataObject `json:"certificationDataObject"`
+}
+
+type ExtCardResource struct {
+	InstalledApplication  uint32 `json:"installedApplication"`
+	FreeNonVolatileMemory uint32 `json:"freeNonVolatileMemory"`
+	FreeVolatileMemory    uint32 `json:"freeVolatileMemory"`
+}
+
+type CertificationDataObject struct {
+	PlatformLabel    *string `json:"platformLabel"`
+	DiscoveryBaseURL *string `json:"discoveryBaseURL"`
+}
+
+type RulesAuthorisation struct {
+	PPRIds           []string    `json:"pprIds"`
+	AllowedOperators []*Operator `json:"allowedOperators"`
+	PPRFlags         []string    `json:"pprFlags"`
+}
+
+type Operator struct {
+	PLMN string  `json:"plmn"`
+	GID1 *string `json:"gid1"`
+	GID2 *string `json:"gid2"`
+}
+
+// Profile is an element of the data of lpac profile list.
+type Profile struct {
+	ICCID               string  `json:"iccid"`
+	ISDPAID             string  `json:"isdpAid"`
+	ProfileState        string  `json:"profileState"`
+	ProfileNickname     *string `json:"profileNickname"`
+	ServiceProviderName *string `json:"serviceProviderName"`
+	ProfileName         *string `json:"profileName"`
+	IconType            string  `json:"iconType"`
+	Icon                *string `json:"icon"`
+	ProfileClass        string  `json:"profileClass"`
+}
+
+// Notification is an element of the data of lpac notification list.
+type Notification struct {
+	SequenceNumber             sgp22.SequenceNumber `json:"seqNumber"`
+	ProfileManagementOperation string               `json:"profileManagementOperation"`
+	NotificationAddress        string               `json:"notificationAddress"`
+	ICCID                      *string              `json:"iccid"`
+}
+
+// DiscoveredProfile is an element of the data of lpac profile discovery.
+type DiscoveredProfile struct {
+	EventID          string `json:"eventId"`
+	RSPServerAddress string `json:"rspServerAddress"`
+}
+
+// NewChipInfo converts the chip information.
+func NewChipInfo(info *lpa.ChipInfo) *ChipInfo {
+	data := &ChipInfo{EID: info.EID}
+	if addresses := info.ConfiguredAddresses; addresses != nil {
+		data.ConfiguredAddresses = &Addresses{
+			DefaultDPAddress: optional(addresses.DefaultSMDPAddress),
+			RootDSAddress:    optional(addresses.RootSMDSAddress),
+		}
+	}
+	if info2 := info.Info2; info2 != nil {
+		data.EUICCInfo2 = &EUICCInfo2{
+			ProfileVersion:   info2.ProfileVersion,
+			SVN:              info2.SVN,
+			EUICCFirmwareVer: info2.EUICCFirmwareVer,
+			ExtCardResource: ExtCardResource{
+				InstalledApplication:  info2.ExtCardResource.InstalledApplication,
+				FreeNonVolatileMemory: info2.ExtCardResource.FreeNonVolatileMemory,
+				FreeVolatileMemory:    info2.ExtCardResource.FreeVolatileMemory,
+			},
+			UICCCapability:                 info2.UICCCapability,
+			TS102241Version:                optional(info2.TS102241Version),
+			GlobalPlatformVersion:          optional(info2.GlobalPlatformVersion),
+			RSPCapability:                  info2.RSPCapability,
+			EUICCCiPKIdListForVerification: info2.EUICCCiPKIdListForVerification,
+			EUICCCiPKIdListForSigning:      info2.EUICCCiPKIdListForSigning,
+			EUICCCategory:                  optional(info2.EUICCCategory),
+			ForbiddenProfilePolicyRules:    info2.ForbiddenProfilePolicyRules,
+			PPVersion:                      info2.PPVersion,
+			SASAccreditationNumber:         info2.SASAccreditationNumber,
+			CertificationDataObject: CertificationDataObject{
+				PlatformLabel:    optional(info2.CertificationDataObject.PlatformLabel),
+				DiscoveryBaseURL: optional(info2.CertificationDataObject.DiscoveryBaseURL),
+			},
+		}
+	}
+	for _, rule := range info.RulesAuthorisationTable {
+		rat := &RulesAuthorisation{PPRIds: rule.PPRIds, PPRFlags: rule.PPRFlags}
+		for _, operator := range rule.AllowedOperators {
+			rat.AllowedOperators = append(rat.AllowedOperators, &Operator{
+				PLMN: operator.PLMN,
+				GID1: optional(operator.GID1),
+				GID2: optional(operator.GID2),
+			})
+		}
+		data.RulesAuthorisationTable = append(data.RulesAuthorisationTable, rat)
+	}
+	return data
+}
+
+// NewProfiles converts the profiles returned by lpa.Client.ListProfile.
+func NewProfiles(profiles []*sgp22.ProfileInfo) []*Profile {
+	data := make([]*Profile, len(profiles))
+	for i, profile := range profiles {
+		data[i] = &Profile{
+			ICCID:               profile.ICCID.String(),
+			ISDPAID:             profile.ISDPAID.String(),
+			ProfileState:        "disabled",
+			ProfileNickname:     optional(profile.ProfileNickname),
+			ServiceProviderName: optional(profile.ServiceProviderName),
+			ProfileName:         optional(profile.ProfileName),
+			IconType:            "none",
+			ProfileClass:        profile.ProfileClass.String(),
+		}
+		if profile.ProfileState == sgp22.ProfileEnabled {
+			data[i].ProfileState = "enabled"
+		}
+		switch profile.Icon.FileType() {
+		case "image/png":
+			data[i].IconType = "png"
+		case "image/jpeg":
+			data[i].IconType = "jpg"
+		}
+		if len(profile.Icon) > 0 {
+			icon := base64.StdEncoding.EncodeToString(profile.Icon)
+			data[i].Icon = &icon
+		}
+	}
+	return data
+}
+
+// NewNotifications converts the notifications returned by lpa.Client.ListNotification.
+func NewNotifications(notifications []*sgp22.NotificationMetadata) []*Notification {
+	data := make([]*Notification, len(notifications))
+	for i, notification := range notifications {
+		data[i] = &Notification{
+			SequenceNumber:             notification.SequenceNumber,
+			ProfileManagementOperation: notification.ProfileManagementOperation.String(),
+			NotificationAddress:        notification.Address,
+		}
+		if len(notification.ICCID) > 0 {
+			data[i].ICCID = optional(notification.ICCID.String())
+		}
+	}
+	return data
+}
+
+// NewDiscoveredProfiles converts the profiles returned by lpa.Client.DiscoverProfiles.
+func NewDiscoveredProfiles(profiles []*lpa.DiscoveredProfile) []*DiscoveredProfile {
+	data := make([]*DiscoveredProfile, len(profiles))
+	for i, profile := range profiles {
+		data[i] = &DiscoveredProfile{EventID: profile.EventID, RSPServerAddress: profile.SMDPAddress}
+	}
+	return data
+}
+
+// DownloadStageFunction returns the lpac name of the step starting a download stage.
+func DownloadStageFunction(stage lpa.DownloadStage) string {
+	switch stage {
+	case lpa.DownloadStageAuthenticateClient:
+		return "es9p_initiate_authentication"
+	case lpa.DownloadStageAuthenticateServer:
+		return "es10b_prepare_download"
+	case lpa.DownloadStageInstall:
+		return "es10b_load_bound_profile_package"
+	}
+	return stage.String()
+}
+
+// optional returns nil for an empty string, which lpac writes as null.
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
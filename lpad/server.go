@@ -0,0 +1,356 @@
+// Package lpad serves the eUICCs of a manager over a local HTTP API, so that a single process owns the channels
+// and other programs, written in any language, manage the profiles through it.
+//
+// The API speaks JSON, the data of the profiles, notifications and chip information follow the output of lpac:
+//
+//	GET    /v1/cards                                          the cards, see Card
+//	GET    /v1/cards/{eid}                                    a card
+//	GET    /v1/cards/{eid}/chip                               the chip information, see lpac.ChipInfo
+//	GET    /v1/cards/{eid}/profiles                           the profiles, see lpac.Profile
+//	POST   /v1/cards/{eid}/profiles/{iccid|aid}/enable        enable a profile, see ProfileRequest
+//	POST   /v1/cards/{eid}/profiles/{iccid|aid}/disable       disable a profile, see ProfileRequest
+//	DELETE /v1/cards/{eid}/profiles/{iccid|aid}               delete a profile
+//	POST   /v1/cards/{eid}/downloads                          download a profile, see DownloadRequest and DownloadEvent
+//	GET    /v1/cards/{eid}/notifications                      the pending notifications, see lpac.Notification
+//	POST   /v1/cards/{eid}/notifications/process              send notifications, see ProcessNotificationsRequest
+//	DELETE /v1/cards/{eid}/notifications/{seqNumber}          remove a notification
+//
+// The requests without data answer 204 No Content, and a failed request answers an Error with a 4xx or 5xx status.
+// The response of a download is a stream of JSON lines reporting its progress, so that a client can follow it:
+//
+//	curl --unix-socket /run/lpad.sock -d '{"activationCode":"LPA:1$smdp.io$QR-G-5C-1LS-1W1Z9P7","imei":"356938035643809"}' \
+//		http://lpad/v1/cards/89049032000000000000000000000001/downloads
+package lpad
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/KilimcininKorOglu/euicc-go/lpac"
+	"github.com/KilimcininKorOglu/euicc-go/manager"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+// Options configure the server.
+type Options struct {
+	// Manager holds the cards served by the daemon.
+	Manager *manager.Manager
+	// HTTPClient reaches the SM-DP+ and SM-DS servers instead of the HTTP client of the LPA clients.
+	HTTPClient *http.Client
+	// Logger logs the failed requests, it defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// Server is the HTTP handler of the daemon.
+type Server struct {
+	manager    *manager.Manager
+	httpClient *http.Client
+	logger     *slog.Logger
+	mux        *http.ServeMux
+}
+
+// NewServer creates the handler serving the cards of the manager.
+func NewServer(opts *Options) *Server {
+	s := &Server{
+		manager:    opts.Manager,
+		httpClient: opts.HTTPClient,
+		logger:     opts.Logger,
+		mux:        http.NewServeMux(),
+	}
+	if s.logger == nil {
+		s.logger = slog.Default()
+	}
+	s.mux.HandleFunc("GET /v1/cards", s.listCards)
+	s.mux.HandleFunc("GET /v1/cards/{eid}", s.handle(s.getCard))
+	s.mux.HandleFunc("GET /v1/cards/{eid}/chip", s.handle(s.chipInfo))
+	s.mux.HandleFunc("GET /v1/cards/{eid}/profiles", s.handle(s.listProfiles))
+	s.mux.HandleFunc("POST /v1/cards/{eid}/profiles/{profile}/enable", s.handle(s.enableProfile))
+	s.mux.HandleFunc("POST /v1/cards/{eid}/profiles/{profile}/disable", s.handle(s.disableProfile))
+	s.mux.HandleFunc("DELETE /v1/cards/{eid}/profiles/{profile}", s.handle(s.deleteProfile))
+	s.mux.HandleFunc("POST /v1/cards/{eid}/downloads", s.download)
+	s.mux.HandleFunc("GET /v1/cards/{eid}/notifications", s.handle(s.listNotifications))
+	s.mux.HandleFunc("POST /v1/cards/{eid}/notifications/process", s.handle(s.processNotifications))
+	s.mux.HandleFunc("DELETE /v1/cards/{eid}/notifications/{seqNumber}", s.handle(s.removeNotification))
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Listen listens on the Unix socket, replacing the socket left by a previous daemon.
+// The socket is accessible to the owner and the group only, as the daemon manages the profiles for anyone reaching it.
+func Listen(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("lpad: %s is used by another daemon", path)
+		}
+		_ = os.Remove(path)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0660); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// handlerFunc handles a request for a card, the returned value is written as JSON.
+type handlerFunc func(r *http.Request, card *manager.Card) (any, error)
+
+func (s *Server) handle(handler handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		card, err := s.card(r)
+		var response any
+		if err == nil {
+			response, err = handler(r, card)
+		}
+		switch {
+		case err != nil:
+			s.writeError(w, r, err)
+		case response == nil:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeJSON(w, http.StatusOK, response)
+		}
+	}
+}
+
+func (s *Server) card(r *http.Request) (*manager.Card, error) {
+	card, ok := s.manager.ByEID(r.PathValue("eid"))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", manager.ErrNotFound, r.PathValue("eid"))
+	}
+	return card, nil
+}
+
+// do runs the function with the LPA client of the card.
+func (s *Server) do(ctx context.Context, card *manager.Card, fn func(client *lpa.Client) error) error {
+	return card.Do(ctx, func(client *lpa.Client) error {
+		if s.httpClient != nil {
+			client.HTTP.Client = s.httpClient
+		}
+		return fn(client)
+	})
+}
+
+func (s *Server) listCards(w http.ResponseWriter, r *http.Request) {
+	cards := s.manager.Cards()
+	response := make([]*Card, len(cards))
+	for i, card := range cards {
+		response[i] = newCard(card)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getCard(r *http.Request, card *manager.Card) (any, error) {
+	return newCard(card), nil
+}
+
+func (s *Server) chipInfo(r *http.Request, card *manager.Card) (response any, err error) {
+	err = s.do(r.Context(), card, func(client *lpa.Client) error {
+		info, err := client.ChipInfo(r.Context())
+		if err == nil {
+			response = lpac.NewChipInfo(info)
+		}
+		return err
+	})
+	return
+}
+
+func (s *Server) listProfiles(r *http.Request, card *manager.Card) (response any, err error) {
+	err = s.do(r.Context(), card, func(client *lpa.Client) error {
+		profiles, err := client.ListProfile(r.Context(), nil, nil)
+		if err == nil {
+			response = lpac.NewProfiles(profiles)
+		}
+		return err
+	})
+	return
+}
+
+func (s *Server) enableProfile(r *http.Request, card *manager.Card) (any, error) {
+	return s.setProfile(r, card, sgp22.EnableProfile)
+}
+
+func (s *Server) disableProfile(r *http.Request, card *manager.Card) (any, error) {
+	return s.setProfile(r, card, sgp22.DisableProfile)
+}
+
+func (s *Server) deleteProfile(r *http.Request, card *manager.Card) (any, error) {
+	return s.setProfile(r, card, sgp22.DeleteProfile)
+}
+
+func (s *Server) setProfile(r *http.Request, card *manager.Card, operation sgp22.ProfileOperation) (any, error) {
+	identifier, err := parseProfileIdentifier(r.PathValue("profile"))
+	if err != nil {
+		return nil, err
+	}
+	var request ProfileRequest
+	if operation != sgp22.DeleteProfile {
+		if err := decodeJSON(r, &request); err != nil {
+			return nil, err
+		}
+	}
+	return nil, s.do(r.Context(), card, func(client *lpa.Client) error {
+		switch operation {
+		case sgp22.EnableProfile:
+			return client.EnableProfile(r.Context(), identifier, request.Refresh)
+		case sgp22.DisableProfile:
+			return client.DisableProfile(r.Context(), identifier, request.Refresh)
+		}
+		return client.DeleteProfile(r.Context(), identifier)
+	})
+}
+
+func (s *Server) download(w http.ResponseWriter, r *http.Request) {
+	card, err := s.card(r)
+	var request DownloadRequest
+	if err == nil {
+		err = decodeJSON(r, &request)
+	}
+	var ac lpa.ActivationCode
+	if err == nil {
+		if err = ac.UnmarshalText([]byte(request.ActivationCode)); err != nil {
+			err = fmt.Errorf("%w: %w", errBadRequest, err)
+		}
+	}
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	ac.IMEI = request.IMEI
+	ac.ConfirmationCode = request.ConfirmationCode
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	controller := http.NewResponseController(w)
+	encoder := json.NewEncoder(w)
+	send := func(event *DownloadEvent) {
+		_ = encoder.Encode(event)
+		_ = controller.Flush()
+	}
+	var result *sgp22.LoadBoundProfilePackageResponse
+	err = s.do(r.Context(), card, func(client *lpa.Client) (err error) {
+		result, err = client.DownloadProfile(r.Context(), &ac, &lpa.DownloadOptions{
+			OnProgress: func(stage lpa.DownloadStage) {
+				send(&DownloadEvent{Stage: lpac.DownloadStageFunction(stage), Description: stage.String()})
+			},
+			OnConfirm: func(metadata *sgp22.ProfileInfo) bool {
+				send(&DownloadEvent{Profile: lpac.NewProfiles([]*sgp22.ProfileInfo{metadata})[0]})
+				return true
+			},
+		})
+		return err
+	})
+	if err != nil {
+		s.logger.Warn("[lpad] download failed", "eid", card.EID, "error", err)
+		_, e := newError(err)
+		send(&DownloadEvent{Error: e})
+		return
+	}
+	download := &DownloadResult{ISDPAID: result.ISDPAID().String(), SequenceNumber: result.Notification.SequenceNumber}
+	if len(result.Notification.ICCID) > 0 {
+		download.ICCID = result.Notification.ICCID.String()
+	}
+	send(&DownloadEvent{Result: download})
+}
+
+func (s *Server) listNotifications(r *http.Request, card *manager.Card) (response any, err error) {
+	err = s.do(r.Context(), card, func(client *lpa.Client) error {
+		notifications, err := client.ListNotification(r.Context())
+		if err == nil {
+			response = lpac.NewNotifications(notifications)
+		}
+		return err
+	})
+	return
+}
+
+func (s *Server) processNotifications(r *http.Request, card *manager.Card) (any, error) {
+	var request ProcessNotificationsRequest
+	if err := decodeJSON(r, &request); err != nil {
+		return nil, err
+	}
+	opts := &lpa.ProcessNotificationsOptions{AutoRemove: request.Remove, ContinueOnError: true}
+	var results []*lpa.NotificationProcessResult
+	err := s.do(r.Context(), card, func(client *lpa.Client) (err error) {
+		if len(request.SequenceNumbers) == 0 {
+			results, err = client.ProcessAllNotifications(r.Context(), opts)
+		} else {
+			results, err = client.ProcessNotifications(r.Context(), opts, request.SequenceNumbers...)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	response := make([]*NotificationResult, len(results))
+	for i, result := range results {
+		response[i] = &NotificationResult{SequenceNumber: result.SequenceNumber, Removed: result.Removed}
+		if result.Error != nil {
+			_, response[i].Error = newError(result.Error)
+		}
+	}
+	return response, nil
+}
+
+func (s *Server) removeNotification(r *http.Request, card *manager.Card) (any, error) {
+	sequenceNumber, err := strconv.ParseInt(r.PathValue("seqNumber"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid sequence number %q", errBadRequest, r.PathValue("seqNumber"))
+	}
+	return nil, s.do(r.Context(), card, func(client *lpa.Client) error {
+		return client.RemoveNotificationFromList(r.Context(), sgp22.SequenceNumber(sequenceNumber))
+	})
+}
+
+// parseProfileIdentifier parses an ISD-P AID given in hex or an ICCID.
+func parseProfileIdentifier(value string) (any, error) {
+	if len(value) == 32 {
+		if aid, err := hex.DecodeString(value); err == nil {
+			return sgp22.ISDPAID(aid), nil
+		}
+	}
+	iccid, err := sgp22.NewICCID(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid ICCID or ISD-P AID %q", errBadRequest, value)
+	}
+	return iccid, nil
+}
+
+// decodeJSON decodes the body of the request, an empty body leaves the value unchanged.
+func decodeJSON(r *http.Request, v any) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("%w: %w", errBadRequest, err)
+	}
+	return nil
+}
+
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, e := newError(err)
+	if status >= http.StatusInternalServerError {
+		s.logger.Warn("[lpad] request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+	writeJSON(w, status, e)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,155 @@
+package lpad_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/http/smdptest"
+	"github.com/KilimcininKorOglu/euicc-go/lpac"
+	"github.com/KilimcininKorOglu/euicc-go/lpad"
+	"github.com/KilimcininKorOglu/euicc-go/manager"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const eid = "89049032000000000000000000000001"
+
+var card *virtual.EUICC
+
+func init() {
+	driver.Register("lpadtest", func(*url.URL) (apdu.SmartCardChannel, error) { return card, nil })
+}
+
+func setup(t *testing.T) (*smdptest.Server, string) {
+	card = virtual.New()
+	iccid, err := sgp22.NewICCID("8944476500001224158")
+	require.NoError(t, err)
+	card.AddProfile(&virtual.Profile{ICCID: iccid, State: sgp22.ProfileEnabled, ProfileName: "Profile A"})
+
+	smdp := smdptest.NewServer()
+	t.Cleanup(smdp.Close)
+	iccid, err = sgp22.NewICCID("8944476500001224166")
+	require.NoError(t, err)
+	smdp.Orders["QR-G-5C-1LS-1W1Z9P7"] = &smdptest.Order{
+		Profile: &sgp22.ProfileInfo{ICCID: iccid, ProfileName: "Profile B", ProfileClass: sgp22.ProfileClassOperational},
+	}
+
+	m := manager.New(nil)
+	t.Cleanup(func() { _ = m.Close() })
+	_, err = m.Add(context.Background(), "lpadtest:///dev/modem0")
+	require.NoError(t, err)
+	server := httptest.NewServer(lpad.NewServer(&lpad.Options{Manager: m, HTTPClient: smdp.Client()}))
+	t.Cleanup(server.Close)
+	return smdp, server.URL + "/v1/cards/"
+}
+
+func request(t *testing.T, method, url, body string, response any) int {
+	r, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(r)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if response != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+	}
+	return resp.StatusCode
+}
+
+func TestServer_Profiles(t *testing.T) {
+	_, cards := setup(t)
+
+	var listed []*lpad.Card
+	assert.Equal(t, http.StatusOK, request(t, http.MethodGet, strings.TrimSuffix(cards, "/"), "", &listed))
+	assert.Equal(t, []*lpad.Card{{EID: eid, Driver: "lpadtest", Device: "/dev/modem0", URI: "lpadtest:///dev/modem0"}}, listed)
+
+	var info lpac.ChipInfo
+	assert.Equal(t, http.StatusOK, request(t, http.MethodGet, cards+eid+"/chip", "", &info))
+	assert.Equal(t, eid, info.EID)
+
+	var profiles []*lpac.Profile
+	assert.Equal(t, http.StatusOK, request(t, http.MethodGet, cards+eid+"/profiles", "", &profiles))
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "8944476500001224158", profiles[0].ICCID)
+	assert.Equal(t, "enabled", profiles[0].ProfileState)
+
+	var e lpad.Error
+	assert.Equal(t, http.StatusConflict, request(t, http.MethodDelete, cards+eid+"/profiles/8944476500001224158", "", &e))
+	assert.Equal(t, lpad.Error{Message: "profile not in disabled state", Function: "ES10c.DeleteProfile", Result: 2}, e)
+	assert.Equal(t, http.StatusNoContent, request(t, http.MethodPost, cards+eid+"/profiles/8944476500001224158/disable", `{"refresh":false}`, nil))
+	assert.Equal(t, sgp22.ProfileDisabled, card.Profiles[0].State)
+	assert.Equal(t, http.StatusNoContent, request(t, http.MethodPost, cards+eid+"/profiles/"+card.Profiles[0].ISDPAID.String()+"/enable", "", nil))
+	assert.Equal(t, sgp22.ProfileEnabled, card.Profiles[0].State)
+
+	assert.Equal(t, http.StatusBadRequest, request(t, http.MethodPost, cards+eid+"/profiles/not-a-profile/enable", "", &e))
+	assert.Equal(t, http.StatusNotFound, request(t, http.MethodGet, cards+"89049032000000000000000000000002/profiles", "", &e))
+}
+
+func TestServer_Download(t *testing.T) {
+	smdp, cards := setup(t)
+	// The IMEI is optional.
+	body := `{"activationCode":"LPA:1$` + smdp.SMDP().Host + `$QR-G-5C-1LS-1W1Z9P7"}`
+	resp, err := http.Post(cards+eid+"/downloads", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	var events []*lpad.DownloadEvent
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var event lpad.DownloadEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, &event)
+	}
+	require.Len(t, events, 5)
+	assert.Equal(t, "es9p_initiate_authentication", events[0].Stage)
+	assert.Equal(t, "Profile B", *events[1].Profile.ProfileName)
+	assert.Equal(t, "es10b_prepare_download", events[2].Stage)
+	assert.Equal(t, "es10b_load_bound_profile_package", events[3].Stage)
+	require.NotNil(t, events[4].Result)
+	assert.Equal(t, "8944476500001224166", events[4].Result.ICCID)
+	assert.Equal(t, card.Profiles[1].ISDPAID.String(), events[4].Result.ISDPAID)
+
+	var notifications []*lpac.Notification
+	assert.Equal(t, http.StatusOK, request(t, http.MethodGet, cards+eid+"/notifications", "", &notifications))
+	require.Len(t, notifications, 1)
+	assert.Equal(t, events[4].Result.SequenceNumber, notifications[0].SequenceNumber)
+	var results []*lpad.NotificationResult
+	assert.Equal(t, http.StatusOK, request(t, http.MethodPost, cards+eid+"/notifications/process", `{"remove":true}`, &results))
+	assert.Equal(t, []*lpad.NotificationResult{{SequenceNumber: notifications[0].SequenceNumber, Removed: true}}, results)
+	assert.Len(t, smdp.Notifications, 1)
+	assert.Empty(t, card.Notifications)
+}
+
+func TestServer_DownloadError(t *testing.T) {
+	smdp, cards := setup(t)
+	var e lpad.Error
+	assert.Equal(t, http.StatusBadRequest, request(t, http.MethodPost, cards+eid+"/downloads", `{"activationCode":"X"}`, &e))
+
+	event := download(t, cards, `{"activationCode":"LPA:1$`+smdp.SMDP().Host+`$UNKNOWN","imei":"356938035643809"}`)
+	require.NotNil(t, event.Error)
+	assert.Equal(t, "8.2.6", event.Error.SubjectCode)
+	assert.Equal(t, "3.8", event.Error.ReasonCode)
+}
+
+// download starts a download and returns its last event.
+func download(t *testing.T, cards, body string) (event lpad.DownloadEvent) {
+	resp, err := http.Post(cards+eid+"/downloads", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	decoder := json.NewDecoder(resp.Body)
+	for decoder.More() {
+		event = lpad.DownloadEvent{}
+		require.NoError(t, decoder.Decode(&event))
+	}
+	return event
+}
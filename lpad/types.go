@@ -0,0 +1,108 @@
+package lpad
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/KilimcininKorOglu/euicc-go/lpac"
+	"github.com/KilimcininKorOglu/euicc-go/manager"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+// Card is an eUICC served by the daemon.
+type Card struct {
+	EID    string `json:"eid"`
+	Driver string `json:"driver"`
+	Device string `json:"device,omitempty"`
+	Slot   uint8  `json:"slot,omitempty"`
+	URI    string `json:"uri"`
+}
+
+func newCard(card *manager.Card) *Card {
+	return &Card{EID: card.EID, Driver: card.Driver, Device: card.Device, Slot: card.Slot, URI: card.URI}
+}
+
+// ProfileRequest is the optional body of the enable and disable requests.
+type ProfileRequest struct {
+	// Refresh asks the modem to refresh the UICC after the operation.
+	Refresh bool `json:"refresh"`
+}
+
+// DownloadRequest is the body of a download request.
+type DownloadRequest struct {
+	// ActivationCode is the activation code, such as LPA:1$smdp.io$QR-G-5C-1LS-1W1Z9P7.
+	ActivationCode string `json:"activationCode"`
+	// IMEI is the IMEI of the device, it is optional and read from the modem when it is empty.
+	IMEI string `json:"imei"`
+	// ConfirmationCode is the confirmation code, when the profile requires one.
+	ConfirmationCode string `json:"confirmationCode,omitempty"`
+}
+
+// DownloadEvent is a line of the response of a download request, which is a stream of JSON lines.
+// The stream reports each stage of the download, the profile being downloaded,
+// and ends with either the result or the error.
+type DownloadEvent struct {
+	// Stage is the lpac name of the step starting, such as es10b_load_bound_profile_package, and Description describes it.
+	Stage       string `json:"stage,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Profile is the metadata of the profile offered by the SM-DP+.
+	Profile *lpac.Profile   `json:"profile,omitempty"`
+	Result  *DownloadResult `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// DownloadResult is the result of a successful download.
+type DownloadResult struct {
+	ISDPAID string `json:"isdpAid"`
+	ICCID   string `json:"iccid,omitempty"`
+	// SequenceNumber is the sequence number of the install notification to send to the SM-DP+.
+	SequenceNumber sgp22.SequenceNumber `json:"seqNumber"`
+}
+
+// ProcessNotificationsRequest is the body of a request sending notifications to their SM-DP+.
+type ProcessNotificationsRequest struct {
+	// SequenceNumbers are the notifications to send, all the pending notifications when empty.
+	SequenceNumbers []sgp22.SequenceNumber `json:"seqNumbers,omitempty"`
+	// Remove removes the notifications from the eUICC once sent.
+	Remove bool `json:"remove"`
+}
+
+// NotificationResult is the result of sending a notification.
+type NotificationResult struct {
+	SequenceNumber sgp22.SequenceNumber `json:"seqNumber"`
+	Removed        bool                 `json:"removed"`
+	Error          *Error               `json:"error,omitempty"`
+}
+
+// Error is the body of a failed request.
+type Error struct {
+	Message string `json:"message"`
+	// Function and Result are set when an ES10 function returned an error result, see sgp22.ResultError.
+	Function string `json:"function,omitempty"`
+	Result   int8   `json:"result,omitempty"`
+	// SubjectCode and ReasonCode are set when an SM-DP+ or SM-DS rejected a request, see sgp22.RSPError.
+	SubjectCode string `json:"subjectCode,omitempty"`
+	ReasonCode  string `json:"reasonCode,omitempty"`
+}
+
+// errBadRequest marks the errors caused by the request.
+var errBadRequest = errors.New("bad request")
+
+func newError(err error) (int, *Error) {
+	e := &Error{Message: err.Error()}
+	var resultErr *sgp22.ResultError
+	var rspErr *sgp22.RSPError
+	switch {
+	case errors.Is(err, errBadRequest):
+		return http.StatusBadRequest, e
+	case errors.Is(err, manager.ErrNotFound):
+		return http.StatusNotFound, e
+	case errors.As(err, &resultErr):
+		e.Function, e.Result = resultErr.Function, resultErr.Result
+		return http.StatusConflict, e
+	case errors.As(err, &rspErr):
+		e.Function, e.SubjectCode, e.ReasonCode = rspErr.Function, rspErr.SubjectCode, rspErr.ReasonCode
+		return http.StatusBadGateway, e
+	}
+	return http.StatusInternalServerError, e
+}
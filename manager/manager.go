@@ -0,0 +1,349 @@
+// Package manager coordinates the LPA clients of the eUICCs of a host with several modems and slots.
+//
+// The manager holds one card per EID. The channel of a card is opened when the card is used and closed once it is idle,
+// and the cards of one modem are used one at a time, so that two goroutines never open the same slot or
+// two slots of the same modem at once:
+//
+//	m := manager.New(nil)
+//	defer m.Close()
+//	if _, err := m.Discover(ctx, nil); err != nil {
+//		return err
+//	}
+//	card, ok := m.ByDevice("/dev/cdc-wdm0", 2)
+//	err := card.Do(ctx, func(client *lpa.Client) error {
+//		return client.EnableProfile(ctx, iccid, true)
+//	})
+package manager
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"github.com/KilimcininKorOglu/euicc-go/driver/probe"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+var (
+	// ErrNotFound is returned when no card matches a lookup.
+	ErrNotFound = errors.New("manager: card not found")
+	// ErrClosed is returned when a card is used after the manager is closed.
+	ErrClosed = errors.New("manager: closed")
+)
+
+// Options configure the manager.
+type Options struct {
+	// Client are the options of the LPA clients, the Channel is set for each card.
+	Client lpa.Options
+	// IdleTimeout is how long the channel of a card stays open after it was used.
+	// The channel is closed as soon as the card is released when it is zero.
+	IdleTimeout time.Duration
+	// Lock locks the lock file of a card, see driver.LockPath, while its channel is open,
+	// so that other processes do not use the card meanwhile.
+	Lock bool
+}
+
+// Manager holds the cards of the host, one per EID.
+type Manager struct {
+	opts      Options
+	mutex     sync.Mutex
+	cards     map[string]*Card
+	resources map[string]*resource
+	closed    bool
+}
+
+// resource serialises the cards sharing a modem or the PC/SC library, see driver.Resource.
+type resource struct {
+	lock chan struct{}
+	// card is the card whose channel is open.
+	card *Card
+}
+
+// Card is an eUICC of the host.
+type Card struct {
+	probe.EUICC
+
+	manager  *Manager
+	resource *resource
+	// client and timer are guarded by the lock of the resource.
+	client *lpa.Client
+	timer  *time.Timer
+}
+
+// New creates a manager without any card.
+func New(opts *Options) *Manager {
+	if opts == nil {
+		opts = new(Options)
+	}
+	return &Manager{
+		opts:      *opts,
+		cards:     make(map[string]*Card),
+		resources: make(map[string]*resource),
+	}
+}
+
+// Add adds the eUICC reached through the URI, opening its channel to read the EID.
+// It returns the card already added when the eUICC is reachable through another URI.
+func (m *Manager) Add(ctx context.Context, uri string) (*Card, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("manager: invalid URI %q: %w", uri, err)
+	}
+	card, err := m.newCard(u, &probe.EUICC{URI: uri})
+	if err != nil {
+		return nil, err
+	}
+	var eid []byte
+	if err = card.Do(ctx, func(client *lpa.Client) (err error) {
+		eid, err = client.EID(ctx)
+		return err
+	}); err != nil {
+		_ = card.release()
+		return nil, err
+	}
+	euicc, err := probe.Describe(u, eid)
+	if err != nil {
+		_ = card.release()
+		return nil, err
+	}
+	card.EUICC = *euicc
+	return m.add(card)
+}
+
+// Discover adds the eUICCs found by probe.Discover and returns the cards of the manager.
+func (m *Manager) Discover(ctx context.Context, opts *probe.Options) ([]*Card, error) {
+	euiccs, err := probe.Discover(ctx, opts)
+	for _, euicc := range euiccs {
+		u, parseErr := url.Parse(euicc.URI)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		card, addErr := m.newCard(u, euicc)
+		if addErr == nil {
+			_, addErr = m.add(card)
+		}
+		if addErr != nil {
+			return nil, addErr
+		}
+	}
+	return m.Cards(), err
+}
+
+func (m *Manager) newCard(uri *url.URL, euicc *probe.EUICC) (*Card, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.closed {
+		return nil, ErrClosed
+	}
+	key := driver.Resource(uri)
+	r, ok := m.resources[key]
+	if !ok {
+		r = &resource{lock: make(chan struct{}, 1)}
+		m.resources[key] = r
+	}
+	return &Card{EUICC: *euicc, manager: m, resource: r}, nil
+}
+
+// add registers the card under its EID, or releases it in favor of the card already registered.
+func (m *Manager) add(card *Card) (*Card, error) {
+	m.mutex.Lock()
+	existing, ok := m.cards[card.EID]
+	if !ok && !m.closed {
+		m.cards[card.EID] = card
+	}
+	closed := m.closed
+	m.mutex.Unlock()
+	switch {
+	case closed:
+		_ = card.release()
+		return nil, ErrClosed
+	case ok:
+		_ = card.release()
+		return existing, nil
+	}
+	return card, nil
+}
+
+// Cards returns the cards of the manager sorted by driver, device and slot.
+func (m *Manager) Cards() []*Card {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	cards := make([]*Card, 0, len(m.cards))
+	for _, card := range m.cards {
+		cards = append(cards, card)
+	}
+	slices.SortFunc(cards, func(a, b *Card) int {
+		return cmp.Or(
+			strings.Compare(a.Driver, b.Driver),
+			strings.Compare(a.Device, b.Device),
+			cmp.Compare(a.Slot, b.Slot),
+		)
+	})
+	return cards
+}
+
+// ByEID returns the card with the EID, the case of the hex digits does not matter.
+func (m *Manager) ByEID(eid string) (*Card, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	card, ok := m.cards[strings.ToUpper(eid)]
+	return card, ok
+}
+
+// ByDevice returns the card in the slot of the modem, or of the PC/SC reader with slot 0.
+func (m *Manager) ByDevice(device string, slot uint8) (*Card, bool) {
+	for _, card := range m.Cards() {
+		if card.Device == device && card.Slot == slot {
+			return card, true
+		}
+	}
+	return nil, false
+}
+
+// ByICCID returns the card holding the profile, asking each card in turn.
+// The cards which fail to answer are skipped, their errors are returned when no card holds the profile.
+func (m *Manager) ByICCID(ctx context.Context, iccid sgp22.ICCID) (*Card, error) {
+	errs := []error{ErrNotFound}
+	for _, card := range m.Cards() {
+		var found bool
+		err := card.Do(ctx, func(client *lpa.Client) error {
+			profiles, err := client.ListProfile(ctx, iccid, nil)
+			found = len(profiles) > 0
+			return err
+		})
+		if found {
+			return card, nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", card.EID, err))
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Close closes the channels of the cards, which can no longer be used.
+func (m *Manager) Close() error {
+	m.mutex.Lock()
+	m.closed = true
+	cards := m.cards
+	m.cards = make(map[string]*Card)
+	m.mutex.Unlock()
+	var errs []error
+	for _, card := range cards {
+		errs = append(errs, card.release())
+	}
+	return errors.Join(errs...)
+}
+
+// Do runs the function with the LPA client of the card, opening its channel when it is closed.
+// The calls on the cards of a modem are serialised, Do waits for the other cards of the modem to be released,
+// and returns the error of the context when it is done first.
+// The client must not be used once the function returns.
+func (c *Card) Do(ctx context.Context, fn func(client *lpa.Client) error) error {
+	r := c.resource
+	select {
+	case r.lock <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-r.lock }()
+
+	c.manager.mutex.Lock()
+	closed := c.manager.closed
+	c.manager.mutex.Unlock()
+	if closed {
+		return ErrClosed
+	}
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if r.card != c {
+		if r.card != nil {
+			// Another slot of the modem is open, it is closed before the slot of this card is selected.
+			_ = r.card.close()
+		}
+		if err := c.open(); err != nil {
+			return err
+		}
+	}
+	err := fn(c.client)
+	if c.manager.opts.IdleTimeout == 0 {
+		if closeErr := c.close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(c.manager.opts.IdleTimeout, func() {
+		r.lock <- struct{}{}
+		defer func() { <-r.lock }()
+		if c.timer == timer {
+			_ = c.close()
+		}
+	})
+	c.timer = timer
+	return err
+}
+
+// open opens the channel of the card, the lock of the resource must be held.
+func (c *Card) open() error {
+	opts := c.manager.opts.Client
+	if c.manager.opts.Lock {
+		lockFile, err := driver.LockPath(c.URI)
+		if err != nil {
+			return err
+		}
+		if opts.Lock, err = driver.Lock(lockFile, cmp.Or(opts.LockTimeout, lpa.DefaultLockTimeout)); err != nil {
+			return err
+		}
+	}
+	channel, err := driver.Open(c.URI)
+	if err != nil {
+		if opts.Lock != nil {
+			_ = opts.Lock.Unlock()
+		}
+		return err
+	}
+	opts.Channel = channel
+	if c.client, err = lpa.New(&opts); err != nil {
+		return err
+	}
+	c.resource.card = c
+	return nil
+}
+
+// close closes the channel of the card if it is open, the lock of the resource must be held.
+func (c *Card) close() error {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if c.client == nil {
+		return nil
+	}
+	err := c.client.Close()
+	c.client = nil
+	if c.resource.card == c {
+		c.resource.card = nil
+	}
+	return err
+}
+
+// release closes the channel of the card, waiting for the card to be released.
+func (c *Card) release() error {
+	c.resource.lock <- struct{}{}
+	defer func() { <-c.resource.lock }()
+	return c.close()
+}
@@ -0,0 +1,196 @@
+package manager_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KilimcininKorOglu/euicc-go/apdu"
+	"github.com/KilimcininKorOglu/euicc-go/driver"
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/KilimcininKorOglu/euicc-go/manager"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// modem is a fake modem whose slots hold virtual eUICCs, it records how its channels are used.
+type modem struct {
+	mutex sync.Mutex
+	slots map[string]*virtual.EUICC
+	// open is the number of channels connected at the same time, overlapped is set when it exceeds one.
+	open       int
+	opened     int
+	overlapped bool
+}
+
+type channel struct {
+	*virtual.EUICC
+	modem *modem
+}
+
+func (c *channel) Connect() error {
+	c.modem.mutex.Lock()
+	c.modem.open++
+	c.modem.opened++
+	c.modem.overlapped = c.modem.overlapped || c.modem.open > 1
+	c.modem.mutex.Unlock()
+	return c.EUICC.Connect()
+}
+
+func (c *channel) Disconnect() error {
+	c.modem.mutex.Lock()
+	c.modem.open--
+	c.modem.mutex.Unlock()
+	return c.EUICC.Disconnect()
+}
+
+var testModem = &modem{slots: make(map[string]*virtual.EUICC)}
+
+func init() {
+	for slot, eid := range map[string]byte{"1": 0x01, "2": 0x02} {
+		euicc := virtual.New()
+		euicc.AID = lpa.GSMAISDRApplicationAID
+		euicc.EID[15] = eid
+		testModem.slots[slot] = euicc
+	}
+	testModem.slots["1"].AddProfile(&virtual.Profile{ICCID: sgp22.ICCID{0x98, 0x44, 0x47, 0x56, 0x00, 0x00, 0x21, 0x14, 0x85, 0xF1}})
+	driver.Register("managertest", func(uri *url.URL) (apdu.SmartCardChannel, error) {
+		euicc, ok := testModem.slots[uri.Query().Get("slot")]
+		// The serial port of the modem reaches the eUICCs too.
+		if device := driver.Device(uri, ""); !ok || device != "/dev/modem0" && device != "/dev/ttyUSB0" {
+			return nil, errors.New("no such device")
+		}
+		return &channel{EUICC: euicc, modem: testModem}, nil
+	})
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	m := manager.New(nil)
+	defer m.Close()
+
+	slot1, err := m.Add(ctx, "managertest:///dev/modem0?slot=1")
+	require.NoError(t, err)
+	assert.Equal(t, "89049032000000000000000000000001", slot1.EID)
+	assert.Equal(t, "/dev/modem0", slot1.Device)
+	assert.Equal(t, uint8(1), slot1.Slot)
+	slot2, err := m.Add(ctx, "managertest:///dev/modem0?slot=2")
+	require.NoError(t, err)
+	assert.Equal(t, "89049032000000000000000000000002", slot2.EID)
+
+	duplicate, err := m.Add(ctx, "managertest:///dev/ttyUSB0?slot=1")
+	require.NoError(t, err)
+	assert.Same(t, slot1, duplicate)
+	_, err = m.Add(ctx, "managertest:///dev/modem1?slot=1")
+	assert.EqualError(t, err, "no such device")
+	assert.Equal(t, []*manager.Card{slot1, slot2}, m.Cards())
+
+	card, ok := m.ByEID("89049032000000000000000000000002")
+	assert.True(t, ok)
+	assert.Same(t, slot2, card)
+	card, ok = m.ByDevice("/dev/modem0", 1)
+	assert.True(t, ok)
+	assert.Same(t, slot1, card)
+	_, ok = m.ByDevice("/dev/modem0", 3)
+	assert.False(t, ok)
+
+	card, err = m.ByICCID(ctx, sgp22.ICCID{0x98, 0x44, 0x47, 0x56, 0x00, 0x00, 0x21, 0x14, 0x85, 0xF1})
+	require.NoError(t, err)
+	assert.Same(t, slot1, card)
+	_, err = m.ByICCID(ctx, sgp22.ICCID{0x98, 0x44, 0x47, 0x56, 0x00, 0x00, 0x21, 0x14, 0x85, 0xF2})
+	assert.ErrorIs(t, err, manager.ErrNotFound)
+
+	// The slots of the modem are used concurrently, but their channels are never open at the same time.
+	var wg sync.WaitGroup
+	for range 10 {
+		for _, card := range []*manager.Card{slot1, slot2} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, card.Do(ctx, func(client *lpa.Client) error {
+					_, err := client.ListProfile(ctx, nil, nil)
+					return err
+				}))
+			}()
+		}
+	}
+	wg.Wait()
+	testModem.mutex.Lock()
+	assert.False(t, testModem.overlapped)
+	assert.Zero(t, testModem.open)
+	testModem.mutex.Unlock()
+
+	require.NoError(t, m.Close())
+	assert.ErrorIs(t, slot1.Do(ctx, func(*lpa.Client) error { return nil }), manager.ErrClosed)
+}
+
+func TestManager_IdleTimeout(t *testing.T) {
+	ctx := context.Background()
+	m := manager.New(&manager.Options{IdleTimeout: time.Hour})
+	defer m.Close()
+	card, err := m.Add(ctx, "managertest:///dev/modem0?slot=2")
+	require.NoError(t, err)
+
+	testModem.mutex.Lock()
+	opened := testModem.opened
+	testModem.mutex.Unlock()
+	for range 3 {
+		require.NoError(t, card.Do(ctx, func(client *lpa.Client) error {
+			_, err := client.EID(ctx)
+			return err
+		}))
+	}
+	testModem.mutex.Lock()
+	assert.Equal(t, opened, testModem.opened, "the channel is kept open while the card is in use")
+	assert.Equal(t, 1, testModem.open)
+	testModem.mutex.Unlock()
+
+	require.NoError(t, m.Close())
+	testModem.mutex.Lock()
+	assert.Zero(t, testModem.open)
+	testModem.mutex.Unlock()
+}
+
+func TestManager_Locked(t *testing.T) {
+	lockFile, err := driver.LockPath("managertest:///dev/modem0?slot=2")
+	require.NoError(t, err)
+	lock, err := driver.Lock(lockFile, time.Second)
+	require.NoError(t, err)
+	defer lock.Unlock()
+	m := manager.New(&manager.Options{Client: lpa.Options{LockTimeout: 10 * time.Millisecond}, Lock: true})
+	defer m.Close()
+
+	testModem.mutex.Lock()
+	opened := testModem.opened
+	testModem.mutex.Unlock()
+	_, err = m.Add(context.Background(), "managertest:///dev/modem0?slot=2")
+	assert.ErrorIs(t, err, driver.ErrLocked)
+	testModem.mutex.Lock()
+	assert.Equal(t, opened, testModem.opened, "the channel is not opened without the lock")
+	testModem.mutex.Unlock()
+}
+
+func TestCard_DoCanceled(t *testing.T) {
+	m := manager.New(&manager.Options{IdleTimeout: time.Hour})
+	defer m.Close()
+	card, err := m.Add(context.Background(), "managertest:///dev/modem0?slot=1")
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go card.Do(context.Background(), func(*lpa.Client) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, card.Do(ctx, func(*lpa.Client) error { return nil }), context.DeadlineExceeded)
+	close(release)
+}
@@ -0,0 +1,282 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/KilimcininKorOglu/euicc-go/bertlv"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/KilimcininKorOglu/euicc-go/remote/lpapb"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Client is an lpa.Interface calling a Server.
+//
+// The errors of the eUICC, the SM-DP+ and the SM-DS are returned as on the server,
+// errors.As gives access to the sgp22.ResultError, sgp22.RSPError and sgp22.LoadBoundProfilePackageError.
+type Client struct {
+	conn *grpc.ClientConn
+	lpa  lpapb.LPAClient
+}
+
+var _ lpa.Interface = (*Client)(nil)
+
+// Dial returns a client calling the server of the target, see grpc.NewClient for the target and the options.
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// NewClient returns a client calling the server of the connection, Close closes the connection.
+func NewClient(conn *grpc.ClientConn) *Client {
+	return &Client{conn: conn, lpa: lpapb.NewLPAClient(conn)}
+}
+
+func (c *Client) EID(ctx context.Context) ([]byte, error) {
+	response, err := c.lpa.GetEID(ctx, new(emptypb.Empty))
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return response.GetEid(), nil
+}
+
+func (c *Client) ChipInfo(ctx context.Context) (*lpa.ChipInfo, error) {
+	response, err := c.lpa.GetChipInfo(ctx, new(emptypb.Empty))
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromChipInfo(response), nil
+}
+
+func (c *Client) EUICCConfiguredAddresses(ctx context.Context) (*lpa.EUICCConfiguredAddresses, error) {
+	response, err := c.lpa.GetConfiguredAddresses(ctx, new(emptypb.Empty))
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromConfiguredAddresses(response), nil
+}
+
+func (c *Client) SetDefaultDPAddress(ctx context.Context, address string) error {
+	_, err := c.lpa.SetDefaultDPAddress(ctx, &lpapb.SetDefaultDPAddressRequest{Address: address})
+	return fromStatus(err)
+}
+
+func (c *Client) ListProfile(ctx context.Context, searchCriteria any, tags []bertlv.Tag) ([]*sgp22.ProfileInfo, error) {
+	request, err := toListProfilesRequest(searchCriteria, tags)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.lpa.ListProfiles(ctx, request)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	profiles := make([]*sgp22.ProfileInfo, len(response.GetProfiles()))
+	for i, profile := range response.GetProfiles() {
+		if profiles[i], err = fromProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
+
+func (c *Client) EnableProfile(ctx context.Context, identifier any, refresh bool) error {
+	request, err := toProfileOperationRequest(identifier, refresh)
+	if err != nil {
+		return err
+	}
+	_, err = c.lpa.EnableProfile(ctx, request)
+	return fromStatus(err)
+}
+
+func (c *Client) DisableProfile(ctx context.Context, identifier any, refresh bool) error {
+	request, err := toProfileOperationRequest(identifier, refresh)
+	if err != nil {
+		return err
+	}
+	_, err = c.lpa.DisableProfile(ctx, request)
+	return fromStatus(err)
+}
+
+func (c *Client) DeleteProfile(ctx context.Context, identifier any) error {
+	request, err := toProfileOperationRequest(identifier, false)
+	if err != nil {
+		return err
+	}
+	_, err = c.lpa.DeleteProfile(ctx, request)
+	return fromStatus(err)
+}
+
+func (c *Client) SetNickname(ctx context.Context, iccid sgp22.ICCID, nickname string) error {
+	_, err := c.lpa.SetNickname(ctx, &lpapb.SetNicknameRequest{Iccid: iccid.String(), Nickname: nickname})
+	return fromStatus(err)
+}
+
+func (c *Client) MemoryReset(ctx context.Context) error {
+	_, err := c.lpa.MemoryReset(ctx, new(emptypb.Empty))
+	return fromStatus(err)
+}
+
+func (c *Client) ListNotification(ctx context.Context, filters ...sgp22.NotificationEvent) ([]*sgp22.NotificationMetadata, error) {
+	request := &lpapb.ListNotificationsRequest{Filters: make([]lpapb.NotificationEvent, len(filters))}
+	for i, filter := range filters {
+		request.Filters[i] = lpapb.NotificationEvent(filter)
+	}
+	response, err := c.lpa.ListNotifications(ctx, request)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	notifications := make([]*sgp22.NotificationMetadata, len(response.GetNotifications()))
+	for i, notification := range response.GetNotifications() {
+		if notifications[i], err = fromNotification(notification); err != nil {
+			return nil, err
+		}
+	}
+	return notifications, nil
+}
+
+func (c *Client) RemoveNotificationFromList(ctx context.Context, sequenceNumber sgp22.SequenceNumber) error {
+	_, err := c.lpa.RemoveNotification(ctx, &lpapb.RemoveNotificationRequest{SequenceNumber: int64(sequenceNumber)})
+	return fromStatus(err)
+}
+
+func (c *Client) ProcessNotifications(ctx context.Context, opts *lpa.ProcessNotificationsOptions, sequenceNumbers ...sgp22.SequenceNumber) ([]*lpa.NotificationProcessResult, error) {
+	request := &lpapb.ProcessNotificationsRequest{SequenceNumbers: make([]int64, len(sequenceNumbers))}
+	for i, sequenceNumber := range sequenceNumbers {
+		request.SequenceNumbers[i] = int64(sequenceNumber)
+	}
+	return c.processNotifications(ctx, opts, request)
+}
+
+func (c *Client) ProcessAllNotifications(ctx context.Context, opts *lpa.ProcessNotificationsOptions) ([]*lpa.NotificationProcessResult, error) {
+	return c.processNotifications(ctx, opts, &lpapb.ProcessNotificationsRequest{All: true})
+}
+
+func (c *Client) processNotifications(ctx context.Context, opts *lpa.ProcessNotificationsOptions, request *lpapb.ProcessNotificationsRequest) ([]*lpa.NotificationProcessResult, error) {
+	if opts != nil {
+		request.AutoRemove, request.ContinueOnError = opts.AutoRemove, opts.ContinueOnError
+	}
+	response, err := c.lpa.ProcessNotifications(ctx, request)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	results := make([]*lpa.NotificationProcessResult, len(response.GetResults()))
+	for i, result := range response.GetResults() {
+		results[i] = &lpa.NotificationProcessResult{
+			SequenceNumber: sgp22.SequenceNumber(result.GetSequenceNumber()),
+			Success:        result.GetSuccess(),
+			Removed:        result.GetRemoved(),
+		}
+		if result.Error != nil {
+			results[i].Error = fromError(result.Error)
+		}
+	}
+	return results, nil
+}
+
+func (c *Client) DiscoverProfiles(ctx context.Context, opts *lpa.DiscoverProfilesOptions) ([]*lpa.DiscoveredProfile, error) {
+	request := new(lpapb.DiscoverProfilesRequest)
+	if opts != nil {
+		request.SmdsAddress, request.Imei = opts.SMDSAddress, opts.IMEI
+	}
+	response, err := c.lpa.DiscoverProfiles(ctx, request)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	profiles := make([]*lpa.DiscoveredProfile, len(response.GetProfiles()))
+	for i, profile := range response.GetProfiles() {
+		profiles[i] = &lpa.DiscoveredProfile{EventID: profile.GetEventId(), SMDPAddress: profile.GetSmdpAddress()}
+	}
+	return profiles, nil
+}
+
+// DownloadProfile downloads a profile, the callbacks of the options are called as the server reaches them.
+func (c *Client) DownloadProfile(ctx context.Context, ac *lpa.ActivationCode, opts *lpa.DownloadOptions) (*sgp22.LoadBoundProfilePackageResponse, error) {
+	text, err := ac.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = new(lpa.DownloadOptions)
+	}
+	// Canceling the context ends the stream, and the download on the server, when returning early.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.lpa.DownloadProfile(ctx)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	// Send returns io.EOF when the server ended the stream, Recv then returns its status.
+	send := func(request *lpapb.DownloadProfileRequest) error {
+		if err := stream.Send(request); err != nil && !errors.Is(err, io.EOF) {
+			return fromStatus(err)
+		}
+		return nil
+	}
+	if err := send(&lpapb.DownloadProfileRequest{Request: &lpapb.DownloadProfileRequest_Start{Start: &lpapb.DownloadProfileStart{
+		ActivationCode:        string(text),
+		Imei:                  ac.IMEI,
+		ConfirmationCode:      ac.ConfirmationCode,
+		Confirm:               opts.OnConfirm != nil,
+		EnterConfirmationCode: opts.OnEnterConfirmationCode != nil,
+	}}}); err != nil {
+		return nil, err
+	}
+
+	var result *sgp22.LoadBoundProfilePackageResponse
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return result, fromStatus(err)
+		}
+		switch r := response.GetResponse().(type) {
+		case *lpapb.DownloadProfileResponse_Stage:
+			if opts.OnProgress != nil {
+				opts.OnProgress(lpa.DownloadStage(r.Stage))
+			}
+		case *lpapb.DownloadProfileResponse_Confirm:
+			profile, err := fromProfile(r.Confirm)
+			if err != nil {
+				return nil, err
+			}
+			err = send(&lpapb.DownloadProfileRequest{Request: &lpapb.DownloadProfileRequest_Confirm{
+				Confirm: opts.OnConfirm(profile),
+			}})
+			if err != nil {
+				return nil, err
+			}
+		case *lpapb.DownloadProfileResponse_EnterConfirmationCode:
+			err = send(&lpapb.DownloadProfileRequest{Request: &lpapb.DownloadProfileRequest_ConfirmationCode{
+				ConfirmationCode: opts.OnEnterConfirmationCode(),
+			}})
+			if err != nil {
+				return nil, err
+			}
+		case *lpapb.DownloadProfileResponse_Result:
+			if len(r.Result.GetProfileInstallationResult()) == 0 {
+				continue
+			}
+			var tlv bertlv.TLV
+			if err := tlv.UnmarshalBinary(r.Result.GetProfileInstallationResult()); err != nil {
+				return nil, err
+			}
+			result = new(sgp22.LoadBoundProfilePackageResponse)
+			if err := result.UnmarshalBERTLV(&tlv); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
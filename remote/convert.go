@@ -0,0 +1,224 @@
+package remote
+
+import (
+	"errors"
+
+	"github.com/KilimcininKorOglu/euicc-go/bertlv"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/KilimcininKorOglu/euicc-go/remote/lpapb"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+)
+
+func toChipInfo(info *lpa.ChipInfo) *lpapb.ChipInfo {
+	pb := &lpapb.ChipInfo{Eid: info.EID}
+	if info.ConfiguredAddresses != nil {
+		pb.ConfiguredAddresses = toConfiguredAddresses(info.ConfiguredAddresses)
+	}
+	if i := info.Info2; i != nil {
+		pb.Info2 = &lpapb.EUICCInfo2{
+			ProfileVersion:   i.ProfileVersion,
+			Svn:              i.SVN,
+			EuiccFirmwareVer: i.EUICCFirmwareVer,
+			ExtCardResource: &lpapb.ExtCardResource{
+				InstalledApplication:  i.ExtCardResource.InstalledApplication,
+				FreeNonVolatileMemory: i.ExtCardResource.FreeNonVolatileMemory,
+				FreeVolatileMemory:    i.ExtCardResource.FreeVolatileMemory,
+			},
+			UiccCapability:                 i.UICCCapability,
+			Ts102241Version:                i.TS102241Version,
+			GlobalPlatformVersion:          i.GlobalPlatformVersion,
+			RspCapability:                  i.RSPCapability,
+			EuiccCiPkIdListForVerification: i.EUICCCiPKIdListForVerification,
+			EuiccCiPkIdListForSigning:      i.EUICCCiPKIdListForSigning,
+			EuiccCategory:                  i.EUICCCategory,
+			ForbiddenProfilePolicyRules:    i.ForbiddenProfilePolicyRules,
+			PpVersion:                      i.PPVersion,
+			SasAccreditationNumber:         i.SASAccreditationNumber,
+			CertificationDataObject: &lpapb.CertificationDataObject{
+				PlatformLabel:    i.CertificationDataObject.PlatformLabel,
+				DiscoveryBaseUrl: i.CertificationDataObject.DiscoveryBaseURL,
+			},
+		}
+	}
+	for _, rat := range info.RulesAuthorisationTable {
+		table := &lpapb.RulesAuthorisationTable{PprIds: rat.PPRIds, PprFlags: rat.PPRFlags}
+		for _, operator := range rat.AllowedOperators {
+			table.AllowedOperators = append(table.AllowedOperators, &lpapb.AllowedOperator{
+				Plmn: operator.PLMN,
+				Gid1: operator.GID1,
+				Gid2: operator.GID2,
+			})
+		}
+		pb.RulesAuthorisationTable = append(pb.RulesAuthorisationTable, table)
+	}
+	return pb
+}
+
+func fromChipInfo(pb *lpapb.ChipInfo) *lpa.ChipInfo {
+	info := &lpa.ChipInfo{EID: pb.GetEid()}
+	if pb.ConfiguredAddresses != nil {
+		info.ConfiguredAddresses = fromConfiguredAddresses(pb.ConfiguredAddresses)
+	}
+	if i := pb.Info2; i != nil {
+		info.Info2 = &sgp22.EUICCInfo2{
+			ProfileVersion:   i.GetProfileVersion(),
+			SVN:              i.GetSvn(),
+			EUICCFirmwareVer: i.GetEuiccFirmwareVer(),
+			ExtCardResource: sgp22.ExtCardResource{
+				InstalledApplication:  i.GetExtCardResource().GetInstalledApplication(),
+				FreeNonVolatileMemory: i.GetExtCardResource().GetFreeNonVolatileMemory(),
+				FreeVolatileMemory:    i.GetExtCardResource().GetFreeVolatileMemory(),
+			},
+			UICCCapability:                 i.GetUiccCapability(),
+			TS102241Version:                i.GetTs102241Version(),
+			GlobalPlatformVersion:          i.GetGlobalPlatformVersion(),
+			RSPCapability:                  i.GetRspCapability(),
+			EUICCCiPKIdListForVerification: i.GetEuiccCiPkIdListForVerification(),
+			EUICCCiPKIdListForSigning:      i.GetEuiccCiPkIdListForSigning(),
+			EUICCCategory:                  i.GetEuiccCategory(),
+			ForbiddenProfilePolicyRules:    i.GetForbiddenProfilePolicyRules(),
+			PPVersion:                      i.GetPpVersion(),
+			SASAccreditationNumber:         i.GetSasAccreditationNumber(),
+			CertificationDataObject: sgp22.CertificationDataObject{
+				PlatformLabel:    i.GetCertificationDataObject().GetPlatformLabel(),
+				DiscoveryBaseURL: i.GetCertificationDataObject().GetDiscoveryBaseUrl(),
+			},
+		}
+	}
+	for _, table := range pb.RulesAuthorisationTable {
+		rat := &sgp22.RulesAuthorisationTable{PPRIds: table.PprIds, PPRFlags: table.PprFlags}
+		for _, operator := range table.AllowedOperators {
+			rat.AllowedOperators = append(rat.AllowedOperators, &sgp22.OperatorID{
+				PLMN: operator.Plmn,
+				GID1: operator.Gid1,
+				GID2: operator.Gid2,
+			})
+		}
+		info.RulesAuthorisationTable = append(info.RulesAuthorisationTable, rat)
+	}
+	return info
+}
+
+func toConfiguredAddresses(addresses *lpa.EUICCConfiguredAddresses) *lpapb.ConfiguredAddresses {
+	return &lpapb.ConfiguredAddresses{
+		DefaultSmdpAddress: addresses.DefaultSMDPAddress,
+		RootSmdsAddress:    addresses.RootSMDSAddress,
+	}
+}
+
+func fromConfiguredAddresses(pb *lpapb.ConfiguredAddresses) *lpa.EUICCConfiguredAddresses {
+	return &lpa.EUICCConfiguredAddresses{
+		DefaultSMDPAddress: pb.GetDefaultSmdpAddress(),
+		RootSMDSAddress:    pb.GetRootSmdsAddress(),
+	}
+}
+
+func toProfile(profile *sgp22.ProfileInfo) *lpapb.Profile {
+	pb := &lpapb.Profile{
+		Iccid:               profile.ICCID.String(),
+		IsdpAid:             profile.ISDPAID,
+		ProfileState:        lpapb.ProfileState(profile.ProfileState),
+		ProfileNickname:     profile.ProfileNickname,
+		ServiceProviderName: profile.ServiceProviderName,
+		ProfileName:         profile.ProfileName,
+		Icon:                profile.Icon,
+		ProfileClass:        lpapb.ProfileClass(profile.ProfileClass),
+		ProfileOwner: &lpapb.OperatorID{
+			Plmn: profile.ProfileOwner.PLMN,
+			Gid1: profile.ProfileOwner.GID1,
+			Gid2: profile.ProfileOwner.GID2,
+		},
+	}
+	for _, configuration := range profile.NotificationConfigurationInfo {
+		pb.NotificationConfigurationInfo = append(pb.NotificationConfigurationInfo, &lpapb.NotificationConfiguration{
+			ProfileManagementOperation: lpapb.NotificationEvent(configuration.ProfileManagementOperation),
+			Address:                    configuration.Address,
+		})
+	}
+	return pb
+}
+
+func fromProfile(pb *lpapb.Profile) (*sgp22.ProfileInfo, error) {
+	iccid, err := sgp22.NewICCID(pb.GetIccid())
+	if err != nil {
+		return nil, err
+	}
+	profile := &sgp22.ProfileInfo{
+		ICCID:               iccid,
+		ISDPAID:             pb.GetIsdpAid(),
+		ProfileState:        sgp22.ProfileState(pb.GetProfileState()),
+		ProfileNickname:     pb.GetProfileNickname(),
+		ServiceProviderName: pb.GetServiceProviderName(),
+		ProfileName:         pb.GetProfileName(),
+		Icon:                pb.GetIcon(),
+		ProfileClass:        sgp22.ProfileClass(pb.GetProfileClass()),
+		ProfileOwner: sgp22.OperatorId{
+			PLMN: pb.GetProfileOwner().GetPlmn(),
+			GID1: pb.GetProfileOwner().GetGid1(),
+			GID2: pb.GetProfileOwner().GetGid2(),
+		},
+	}
+	for _, configuration := range pb.NotificationConfigurationInfo {
+		profile.NotificationConfigurationInfo = append(profile.NotificationConfigurationInfo, &sgp22.NotificationConfiguration{
+			ProfileManagementOperation: sgp22.NotificationEvent(configuration.GetProfileManagementOperation()),
+			Address:                    configuration.GetAddress(),
+		})
+	}
+	return profile, nil
+}
+
+func toNotification(notification *sgp22.NotificationMetadata) *lpapb.NotificationMetadata {
+	return &lpapb.NotificationMetadata{
+		SequenceNumber:             int64(notification.SequenceNumber),
+		ProfileManagementOperation: lpapb.NotificationEvent(notification.ProfileManagementOperation),
+		Address:                    notification.Address,
+		Iccid:                      notification.ICCID.String(),
+	}
+}
+
+func fromNotification(pb *lpapb.NotificationMetadata) (*sgp22.NotificationMetadata, error) {
+	notification := &sgp22.NotificationMetadata{
+		SequenceNumber:             sgp22.SequenceNumber(pb.GetSequenceNumber()),
+		ProfileManagementOperation: sgp22.NotificationEvent(pb.GetProfileManagementOperation()),
+		Address:                    pb.GetAddress(),
+	}
+	if pb.GetIccid() != "" {
+		var err error
+		if notification.ICCID, err = sgp22.NewICCID(pb.GetIccid()); err != nil {
+			return nil, err
+		}
+	}
+	return notification, nil
+}
+
+func toListProfilesRequest(searchCriteria any, tags []bertlv.Tag) (*lpapb.ListProfilesRequest, error) {
+	request := new(lpapb.ListProfilesRequest)
+	switch v := searchCriteria.(type) {
+	case nil:
+	case sgp22.ICCID:
+		request.SearchCriteria = &lpapb.ListProfilesRequest_Iccid{Iccid: v.String()}
+	case sgp22.ISDPAID:
+		request.SearchCriteria = &lpapb.ListProfilesRequest_IsdpAid{IsdpAid: v}
+	case sgp22.ProfileClass:
+		request.SearchCriteria = &lpapb.ListProfilesRequest_ProfileClass{ProfileClass: lpapb.ProfileClass(v)}
+	default:
+		return nil, errors.New("invalid search criteria")
+	}
+	for _, tag := range tags {
+		request.Tags = append(request.Tags, tag)
+	}
+	return request, nil
+}
+
+func toProfileOperationRequest(identifier any, refresh bool) (*lpapb.ProfileOperationRequest, error) {
+	request := &lpapb.ProfileOperationRequest{Refresh: refresh}
+	switch v := identifier.(type) {
+	case sgp22.ICCID:
+		request.Identifier = &lpapb.ProfileOperationRequest_Iccid{Iccid: v.String()}
+	case sgp22.ISDPAID:
+		request.Identifier = &lpapb.ProfileOperationRequest_IsdpAid{IsdpAid: v}
+	default:
+		return nil, errors.New("invalid profile identifier")
+	}
+	return request, nil
+}
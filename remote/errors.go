@@ -0,0 +1,148 @@
+package remote
+
+import (
+	"context"
+	"errors"
+
+	"github.com/KilimcininKorOglu/euicc-go/remote/lpapb"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// resultErrors are the errors of the ES10 result codes, matched by their message on the client side
+// so that errors.Is(err, sgp22.ErrProfileNotInDisabledState) holds for the errors of a remote eUICC too.
+var resultErrors = []error{
+	sgp22.ErrNothingToDelete,
+	sgp22.ErrCatBusy,
+	sgp22.ErrUndefined,
+	sgp22.ErrIncorrectInputValues,
+	sgp22.ErrICCIDOrAIDNotFound,
+	sgp22.ErrProfileNotInDisabledState,
+	sgp22.ErrProfileNotInEnabledState,
+	sgp22.ErrDisallowedByPolicy,
+	sgp22.ErrWrongProfileReenabling,
+	sgp22.ErrNoResultAvailable,
+}
+
+// remoteError is an error returned by the server, its message is the message of the server
+// and it unwraps to the error of the eUICC, the SM-DP+ or the SM-DS when the status has an Error detail.
+type remoteError struct {
+	message string
+	err     error
+}
+
+func (e *remoteError) Error() string { return e.message }
+
+func (e *remoteError) Unwrap() error { return e.err }
+
+// toError returns the Error of err, which has only the message when err is not an error of the eUICC, the SM-DP+ or the SM-DS.
+func toError(err error) *lpapb.Error {
+	var resultErr *sgp22.ResultError
+	var rspErr *sgp22.RSPError
+	var bppErr *sgp22.LoadBoundProfilePackageError
+	detail := &lpapb.Error{Message: err.Error()}
+	switch {
+	case errors.As(err, &resultErr):
+		detail.Error = &lpapb.Error_ResultError{ResultError: &lpapb.ResultError{
+			Function: resultErr.Function,
+			Result:   int32(resultErr.Result),
+		}}
+	case errors.As(err, &rspErr):
+		detail.Error = &lpapb.Error_RspError{RspError: &lpapb.RSPError{
+			Function:      rspErr.Function,
+			TransactionId: rspErr.TransactionID,
+			Status:        rspErr.Status,
+			SubjectCode:   rspErr.SubjectCode,
+			ReasonCode:    rspErr.ReasonCode,
+			Message:       rspErr.Message,
+		}}
+	case errors.As(err, &bppErr):
+		detail.Error = &lpapb.Error_LoadBoundProfilePackageError{LoadBoundProfilePackageError: &lpapb.LoadBoundProfilePackageError{
+			BppCommandId: uint32(bppErr.BPPCommandID),
+			ErrorReason:  uint32(bppErr.ErrorReason),
+		}}
+	}
+	return detail
+}
+
+// fromError returns the error of an Error.
+func fromError(detail *lpapb.Error) error {
+	var err error
+	switch e := detail.GetError().(type) {
+	case *lpapb.Error_ResultError:
+		resultErr := &sgp22.ResultError{Function: e.ResultError.GetFunction(), Result: int8(e.ResultError.GetResult())}
+		for _, target := range resultErrors {
+			if target.Error() == detail.GetMessage() {
+				resultErr.Err = target
+			}
+		}
+		if resultErr.Err == nil {
+			resultErr.Err = errors.New(detail.GetMessage())
+		}
+		err = resultErr
+	case *lpapb.Error_RspError:
+		err = &sgp22.RSPError{
+			Function:      e.RspError.GetFunction(),
+			TransactionID: e.RspError.GetTransactionId(),
+			Status:        e.RspError.GetStatus(),
+			SubjectCode:   e.RspError.GetSubjectCode(),
+			ReasonCode:    e.RspError.GetReasonCode(),
+			Message:       e.RspError.GetMessage(),
+		}
+	case *lpapb.Error_LoadBoundProfilePackageError:
+		err = &sgp22.LoadBoundProfilePackageError{
+			BPPCommandID: byte(e.LoadBoundProfilePackageError.GetBppCommandId()),
+			ErrorReason:  byte(e.LoadBoundProfilePackageError.GetErrorReason()),
+		}
+	default:
+		return errors.New(detail.GetMessage())
+	}
+	if err.Error() == detail.GetMessage() {
+		return err
+	}
+	return &remoteError{message: detail.GetMessage(), err: err}
+}
+
+// toStatus returns the status of the error of a call.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	detail := toError(err)
+	if detail.Error == nil {
+		return status.Error(codes.Unknown, err.Error())
+	}
+	code := codes.Aborted
+	if _, ok := detail.Error.(*lpapb.Error_ResultError); ok {
+		code = codes.FailedPrecondition
+	}
+	s, _ := status.New(code, err.Error()).WithDetails(detail)
+	return s.Err()
+}
+
+// fromStatus returns the error of the status of a call.
+func fromStatus(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch s.Code() {
+	case codes.Canceled:
+		return &remoteError{message: s.Message(), err: context.Canceled}
+	case codes.DeadlineExceeded:
+		return &remoteError{message: s.Message(), err: context.DeadlineExceeded}
+	}
+	for _, detail := range s.Details() {
+		if detail, ok := detail.(*lpapb.Error); ok {
+			return fromError(detail)
+		}
+	}
+	return err
+}
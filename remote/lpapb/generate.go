@@ -0,0 +1,4 @@
+// Package lpapb holds the protobuf messages and the gRPC service of the remote LPA API, see lpa.proto.
+package lpapb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative lpa.proto
@@ -0,0 +1,2877 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: lpa.proto
+
+// Package euicc.lpa.v1 is the remote API of an LPA, it mirrors the operations of lpa.Client.
+
+package lpapb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ProfileState, ProfileClass and NotificationEvent have the values defined by SGP.22.
+type ProfileState int32
+
+const (
+	ProfileState_PROFILE_STATE_DISABLED ProfileState = 0
+	ProfileState_PROFILE_STATE_ENABLED  ProfileState = 1
+)
+
+// Enum value maps for ProfileState.
+var (
+	ProfileState_name = map[int32]string{
+		0: "PROFILE_STATE_DISABLED",
+		1: "PROFILE_STATE_ENABLED",
+	}
+	ProfileState_value = map[string]int32{
+		"PROFILE_STATE_DISABLED": 0,
+		"PROFILE_STATE_ENABLED":  1,
+	}
+)
+
+func (x ProfileState) Enum() *ProfileState {
+	p := new(ProfileState)
+	*p = x
+	return p
+}
+
+func (x ProfileState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProfileState) Descriptor() protoreflect.EnumDescriptor {
+	return file_lpa_proto_enumTypes[0].Descriptor()
+}
+
+func (ProfileState) Type() protoreflect.EnumType {
+	return &file_lpa_proto_enumTypes[0]
+}
+
+func (x ProfileState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProfileState.Descriptor instead.
+func (ProfileState) EnumDescriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{0}
+}
+
+type ProfileClass int32
+
+const (
+	ProfileClass_PROFILE_CLASS_TEST         ProfileClass = 0
+	ProfileClass_PROFILE_CLASS_PROVISIONING ProfileClass = 1
+	ProfileClass_PROFILE_CLASS_OPERATIONAL  ProfileClass = 2
+)
+
+// Enum value maps for ProfileClass.
+var (
+	ProfileClass_name = map[int32]string{
+		0: "PROFILE_CLASS_TEST",
+		1: "PROFILE_CLASS_PROVISIONING",
+		2: "PROFILE_CLASS_OPERATIONAL",
+	}
+	ProfileClass_value = map[string]int32{
+		"PROFILE_CLASS_TEST":         0,
+		"PROFILE_CLASS_PROVISIONING": 1,
+		"PROFILE_CLASS_OPERATIONAL":  2,
+	}
+)
+
+func (x ProfileClass) Enum() *ProfileClass {
+	p := new(ProfileClass)
+	*p = x
+	return p
+}
+
+func (x ProfileClass) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProfileClass) Descriptor() protoreflect.EnumDescriptor {
+	return file_lpa_proto_enumTypes[1].Descriptor()
+}
+
+func (ProfileClass) Type() protoreflect.EnumType {
+	return &file_lpa_proto_enumTypes[1]
+}
+
+func (x ProfileClass) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProfileClass.Descriptor instead.
+func (ProfileClass) EnumDescriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{1}
+}
+
+type NotificationEvent int32
+
+const (
+	NotificationEvent_NOTIFICATION_EVENT_INSTALL NotificationEvent = 0
+	NotificationEvent_NOTIFICATION_EVENT_ENABLE  NotificationEvent = 1
+	NotificationEvent_NOTIFICATION_EVENT_DISABLE NotificationEvent = 2
+	NotificationEvent_NOTIFICATION_EVENT_DELETE  NotificationEvent = 3
+)
+
+// Enum value maps for NotificationEvent.
+var (
+	NotificationEvent_name = map[int32]string{
+		0: "NOTIFICATION_EVENT_INSTALL",
+		1: "NOTIFICATION_EVENT_ENABLE",
+		2: "NOTIFICATION_EVENT_DISABLE",
+		3: "NOTIFICATION_EVENT_DELETE",
+	}
+	NotificationEvent_value = map[string]int32{
+		"NOTIFICATION_EVENT_INSTALL": 0,
+		"NOTIFICATION_EVENT_ENABLE":  1,
+		"NOTIFICATION_EVENT_DISABLE": 2,
+		"NOTIFICATION_EVENT_DELETE":  3,
+	}
+)
+
+func (x NotificationEvent) Enum() *NotificationEvent {
+	p := new(NotificationEvent)
+	*p = x
+	return p
+}
+
+func (x NotificationEvent) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotificationEvent) Descriptor() protoreflect.EnumDescriptor {
+	return file_lpa_proto_enumTypes[2].Descriptor()
+}
+
+func (NotificationEvent) Type() protoreflect.EnumType {
+	return &file_lpa_proto_enumTypes[2]
+}
+
+func (x NotificationEvent) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NotificationEvent.Descriptor instead.
+func (NotificationEvent) EnumDescriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{2}
+}
+
+type DownloadStage int32
+
+const (
+	DownloadStage_DOWNLOAD_STAGE_AUTHENTICATE_CLIENT DownloadStage = 0
+	DownloadStage_DOWNLOAD_STAGE_AUTHENTICATE_SERVER DownloadStage = 1
+	DownloadStage_DOWNLOAD_STAGE_INSTALL             DownloadStage = 2
+)
+
+// Enum value maps for DownloadStage.
+var (
+	DownloadStage_name = map[int32]string{
+		0: "DOWNLOAD_STAGE_AUTHENTICATE_CLIENT",
+		1: "DOWNLOAD_STAGE_AUTHENTICATE_SERVER",
+		2: "DOWNLOAD_STAGE_INSTALL",
+	}
+	DownloadStage_value = map[string]int32{
+		"DOWNLOAD_STAGE_AUTHENTICATE_CLIENT": 0,
+		"DOWNLOAD_STAGE_AUTHENTICATE_SERVER": 1,
+		"DOWNLOAD_STAGE_INSTALL":             2,
+	}
+)
+
+func (x DownloadStage) Enum() *DownloadStage {
+	p := new(DownloadStage)
+	*p = x
+	return p
+}
+
+func (x DownloadStage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DownloadStage) Descriptor() protoreflect.EnumDescriptor {
+	return file_lpa_proto_enumTypes[3].Descriptor()
+}
+
+func (DownloadStage) Type() protoreflect.EnumType {
+	return &file_lpa_proto_enumTypes[3]
+}
+
+func (x DownloadStage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DownloadStage.Descriptor instead.
+func (DownloadStage) EnumDescriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{3}
+}
+
+type EID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Eid           []byte                 `protobuf:"bytes,1,opt,name=eid,proto3" json:"eid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EID) Reset() {
+	*x = EID{}
+	mi := &file_lpa_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EID) ProtoMessage() {}
+
+func (x *EID) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EID.ProtoReflect.Descriptor instead.
+func (*EID) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EID) GetEid() []byte {
+	if x != nil {
+		return x.Eid
+	}
+	return nil
+}
+
+type ConfiguredAddresses struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	DefaultSmdpAddress string                 `protobuf:"bytes,1,opt,name=default_smdp_address,json=defaultSmdpAddress,proto3" json:"default_smdp_address,omitempty"`
+	RootSmdsAddress    string                 `protobuf:"bytes,2,opt,name=root_smds_address,json=rootSmdsAddress,proto3" json:"root_smds_address,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ConfiguredAddresses) Reset() {
+	*x = ConfiguredAddresses{}
+	mi := &file_lpa_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfiguredAddresses) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfiguredAddresses) ProtoMessage() {}
+
+func (x *ConfiguredAddresses) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfiguredAddresses.ProtoReflect.Descriptor instead.
+func (*ConfiguredAddresses) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConfiguredAddresses) GetDefaultSmdpAddress() string {
+	if x != nil {
+		return x.DefaultSmdpAddress
+	}
+	return ""
+}
+
+func (x *ConfiguredAddresses) GetRootSmdsAddress() string {
+	if x != nil {
+		return x.RootSmdsAddress
+	}
+	return ""
+}
+
+type SetDefaultDPAddressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDefaultDPAddressRequest) Reset() {
+	*x = SetDefaultDPAddressRequest{}
+	mi := &file_lpa_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDefaultDPAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDefaultDPAddressRequest) ProtoMessage() {}
+
+func (x *SetDefaultDPAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDefaultDPAddressRequest.ProtoReflect.Descriptor instead.
+func (*SetDefaultDPAddressRequest) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SetDefaultDPAddressRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type ChipInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// eid is the EID in upper-case hexadecimal.
+	Eid                     string                     `protobuf:"bytes,1,opt,name=eid,proto3" json:"eid,omitempty"`
+	ConfiguredAddresses     *ConfiguredAddresses       `protobuf:"bytes,2,opt,name=configured_addresses,json=configuredAddresses,proto3" json:"configured_addresses,omitempty"`
+	Info2                   *EUICCInfo2                `protobuf:"bytes,3,opt,name=info2,proto3" json:"info2,omitempty"`
+	RulesAuthorisationTable []*RulesAuthorisationTable `protobuf:"bytes,4,rep,name=rules_authorisation_table,json=rulesAuthorisationTable,proto3" json:"rules_authorisation_table,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *ChipInfo) Reset() {
+	*x = ChipInfo{}
+	mi := &file_lpa_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChipInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChipInfo) ProtoMessage() {}
+
+func (x *ChipInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChipInfo.ProtoReflect.Descriptor instead.
+func (*ChipInfo) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ChipInfo) GetEid() string {
+	if x != nil {
+		return x.Eid
+	}
+	return ""
+}
+
+func (x *ChipInfo) GetConfiguredAddresses() *ConfiguredAddresses {
+	if x != nil {
+		return x.ConfiguredAddresses
+	}
+	return nil
+}
+
+func (x *ChipInfo) GetInfo2() *EUICCInfo2 {
+	if x != nil {
+		return x.Info2
+	}
+	return nil
+}
+
+func (x *ChipInfo) GetRulesAuthorisationTable() []*RulesAuthorisationTable {
+	if x != nil {
+		return x.RulesAuthorisationTable
+	}
+	return nil
+}
+
+type EUICCInfo2 struct {
+	state                          protoimpl.MessageState   `protogen:"open.v1"`
+	ProfileVersion                 string                   `protobuf:"bytes,1,opt,name=profile_version,json=profileVersion,proto3" json:"profile_version,omitempty"`
+	Svn                            string                   `protobuf:"bytes,2,opt,name=svn,proto3" json:"svn,omitempty"`
+	EuiccFirmwareVer               string                   `protobuf:"bytes,3,opt,name=euicc_firmware_ver,json=euiccFirmwareVer,proto3" json:"euicc_firmware_ver,omitempty"`
+	ExtCardResource                *ExtCardResource         `protobuf:"bytes,4,opt,name=ext_card_resource,json=extCardResource,proto3" json:"ext_card_resource,omitempty"`
+	UiccCapability                 []string                 `protobuf:"bytes,5,rep,name=uicc_capability,json=uiccCapability,proto3" json:"uicc_capability,omitempty"`
+	Ts102241Version                string                   `protobuf:"bytes,6,opt,name=ts102241_version,json=ts102241Version,proto3" json:"ts102241_version,omitempty"`
+	GlobalPlatformVersion          string                   `protobuf:"bytes,7,opt,name=global_platform_version,json=globalPlatformVersion,proto3" json:"global_platform_version,omitempty"`
+	RspCapability                  []string                 `protobuf:"bytes,8,rep,name=rsp_capability,json=rspCapability,proto3" json:"rsp_capability,omitempty"`
+	EuiccCiPkIdListForVerification []string                 `protobuf:"bytes,9,rep,name=euicc_ci_pk_id_list_for_verification,json=euiccCiPkIdListForVerification,proto3" json:"euicc_ci_pk_id_list_for_verification,omitempty"`
+	EuiccCiPkIdListForSigning      []string                 `protobuf:"bytes,10,rep,name=euicc_ci_pk_id_list_for_signing,json=euiccCiPkIdListForSigning,proto3" json:"euicc_ci_pk_id_list_for_signing,omitempty"`
+	EuiccCategory                  string                   `protobuf:"bytes,11,opt,name=euicc_category,json=euiccCategory,proto3" json:"euicc_category,omitempty"`
+	ForbiddenProfilePolicyRules    []string                 `protobuf:"bytes,12,rep,name=forbidden_profile_policy_rules,json=forbiddenProfilePolicyRules,proto3" json:"forbidden_profile_policy_rules,omitempty"`
+	PpVersion                      string                   `protobuf:"bytes,13,opt,name=pp_version,json=ppVersion,proto3" json:"pp_version,omitempty"`
+	SasAccreditationNumber         string                   `protobuf:"bytes,14,opt,name=sas_accreditation_number,json=sasAccreditationNumber,proto3" json:"sas_accreditation_number,omitempty"`
+	CertificationDataObject        *CertificationDataObject `protobuf:"bytes,15,opt,name=certification_data_object,json=certificationDataObject,proto3" json:"certification_data_object,omitempty"`
+	unknownFields                  protoimpl.UnknownFields
+	sizeCache                      protoimpl.SizeCache
+}
+
+func (x *EUICCInfo2) Reset() {
+	*x = EUICCInfo2{}
+	mi := &file_lpa_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EUICCInfo2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EUICCInfo2) ProtoMessage() {}
+
+func (x *EUICCInfo2) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EUICCInfo2.ProtoReflect.Descriptor instead.
+func (*EUICCInfo2) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EUICCInfo2) GetProfileVersion() string {
+	if x != nil {
+		return x.ProfileVersion
+	}
+	return ""
+}
+
+func (x *EUICCInfo2) GetSvn() string {
+	if x != nil {
+		return x.Svn
+	}
+	return ""
+}
+
+func (x *EUICCInfo2) GetEuiccFirmwareVer() string {
+	if x != nil {
+		return x.EuiccFirmwareVer
+	}
+	return ""
+}
+
+func (x *EUICCInfo2) GetExtCardResource() *ExtCardResource {
+	if x != nil {
+		return x.ExtCardResource
+	}
+	return nil
+}
+
+func (x *EUICCInfo2) GetUiccCapability() []string {
+	if x != nil {
+		return x.UiccCapability
+	}
+	return nil
+}
+
+func (x *EUICCInfo2) GetTs102241Version() string {
+	if x != nil {
+		return x.Ts102241Version
+	}
+	return ""
+}
+
+func (x *EUICCInfo2) GetGlobalPlatformVersion() string {
+	if x != nil {
+		return x.GlobalPlatformVersion
+	}
+	return ""
+}
+
+func (x *EUICCInfo2) GetRspCapability() []string {
+	if x != nil {
+		return x.RspCapability
+	}
+	return nil
+}
+
+func (x *EUICCInfo2) GetEuiccCiPkIdListForVerification() []string {
+	if x != nil {
+		return x.EuiccCiPkIdListForVerification
+	}
+	return nil
+}
+
+func (x *EUICCInfo2) GetEuiccCiPkIdListForSigning() []string {
+	if x != nil {
+		return x.EuiccCiPkIdListForSigning
+	}
+	return nil
+}
+
+func (x *EUICCInfo2) GetEuiccCategory() string {
+	if x != nil {
+		return x.EuiccCategory
+	}
+	return ""
+}
+
+func (x *EUICCInfo2) GetForbiddenProfilePolicyRules() []string {
+	if x != nil {
+		return x.ForbiddenProfilePolicyRules
+	}
+	return nil
+}
+
+func (x *EUICCInfo2) GetPpVersion() string {
+	if x != nil {
+		return x.PpVersion
+	}
+	return ""
+}
+
+func (x *EUICCInfo2) GetSasAccreditationNumber() string {
+	if x != nil {
+		return x.SasAccreditationNumber
+	}
+	return ""
+}
+
+func (x *EUICCInfo2) GetCertificationDataObject() *CertificationDataObject {
+	if x != nil {
+		return x.CertificationDataObject
+	}
+	return nil
+}
+
+type ExtCardResource struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	InstalledApplication  uint32                 `protobuf:"varint,1,opt,name=installed_application,json=installedApplication,proto3" json:"installed_application,omitempty"`
+	FreeNonVolatileMemory uint32                 `protobuf:"varint,2,opt,name=free_non_volatile_memory,json=freeNonVolatileMemory,proto3" json:"free_non_volatile_memory,omitempty"`
+	FreeVolatileMemory    uint32                 `protobuf:"varint,3,opt,name=free_volatile_memory,json=freeVolatileMemory,proto3" json:"free_volatile_memory,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *ExtCardResource) Reset() {
+	*x = ExtCardResource{}
+	mi := &file_lpa_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtCardResource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtCardResource) ProtoMessage() {}
+
+func (x *ExtCardResource) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtCardResource.ProtoReflect.Descriptor instead.
+func (*ExtCardResource) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ExtCardResource) GetInstalledApplication() uint32 {
+	if x != nil {
+		return x.InstalledApplication
+	}
+	return 0
+}
+
+func (x *ExtCardResource) GetFreeNonVolatileMemory() uint32 {
+	if x != nil {
+		return x.FreeNonVolatileMemory
+	}
+	return 0
+}
+
+func (x *ExtCardResource) GetFreeVolatileMemory() uint32 {
+	if x != nil {
+		return x.FreeVolatileMemory
+	}
+	return 0
+}
+
+type CertificationDataObject struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PlatformLabel    string                 `protobuf:"bytes,1,opt,name=platform_label,json=platformLabel,proto3" json:"platform_label,omitempty"`
+	DiscoveryBaseUrl string                 `protobuf:"bytes,2,opt,name=discovery_base_url,json=discoveryBaseUrl,proto3" json:"discovery_base_url,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CertificationDataObject) Reset() {
+	*x = CertificationDataObject{}
+	mi := &file_lpa_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CertificationDataObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CertificationDataObject) ProtoMessage() {}
+
+func (x *CertificationDataObject) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CertificationDataObject.ProtoReflect.Descriptor instead.
+func (*CertificationDataObject) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CertificationDataObject) GetPlatformLabel() string {
+	if x != nil {
+		return x.PlatformLabel
+	}
+	return ""
+}
+
+func (x *CertificationDataObject) GetDiscoveryBaseUrl() string {
+	if x != nil {
+		return x.DiscoveryBaseUrl
+	}
+	return ""
+}
+
+type RulesAuthorisationTable struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PprIds           []string               `protobuf:"bytes,1,rep,name=ppr_ids,json=pprIds,proto3" json:"ppr_ids,omitempty"`
+	AllowedOperators []*AllowedOperator     `protobuf:"bytes,2,rep,name=allowed_operators,json=allowedOperators,proto3" json:"allowed_operators,omitempty"`
+	PprFlags         []string               `protobuf:"bytes,3,rep,name=ppr_flags,json=pprFlags,proto3" json:"ppr_flags,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RulesAuthorisationTable) Reset() {
+	*x = RulesAuthorisationTable{}
+	mi := &file_lpa_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RulesAuthorisationTable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RulesAuthorisationTable) ProtoMessage() {}
+
+func (x *RulesAuthorisationTable) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RulesAuthorisationTable.ProtoReflect.Descriptor instead.
+func (*RulesAuthorisationTable) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RulesAuthorisationTable) GetPprIds() []string {
+	if x != nil {
+		return x.PprIds
+	}
+	return nil
+}
+
+func (x *RulesAuthorisationTable) GetAllowedOperators() []*AllowedOperator {
+	if x != nil {
+		return x.AllowedOperators
+	}
+	return nil
+}
+
+func (x *RulesAuthorisationTable) GetPprFlags() []string {
+	if x != nil {
+		return x.PprFlags
+	}
+	return nil
+}
+
+// AllowedOperator is an operator of the rules authorisation table, its fields are in hexadecimal.
+type AllowedOperator struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plmn          string                 `protobuf:"bytes,1,opt,name=plmn,proto3" json:"plmn,omitempty"`
+	Gid1          string                 `protobuf:"bytes,2,opt,name=gid1,proto3" json:"gid1,omitempty"`
+	Gid2          string                 `protobuf:"bytes,3,opt,name=gid2,proto3" json:"gid2,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AllowedOperator) Reset() {
+	*x = AllowedOperator{}
+	mi := &file_lpa_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllowedOperator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllowedOperator) ProtoMessage() {}
+
+func (x *AllowedOperator) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllowedOperator.ProtoReflect.Descriptor instead.
+func (*AllowedOperator) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AllowedOperator) GetPlmn() string {
+	if x != nil {
+		return x.Plmn
+	}
+	return ""
+}
+
+func (x *AllowedOperator) GetGid1() string {
+	if x != nil {
+		return x.Gid1
+	}
+	return ""
+}
+
+func (x *AllowedOperator) GetGid2() string {
+	if x != nil {
+		return x.Gid2
+	}
+	return ""
+}
+
+type Profile struct {
+	state                         protoimpl.MessageState       `protogen:"open.v1"`
+	Iccid                         string                       `protobuf:"bytes,1,opt,name=iccid,proto3" json:"iccid,omitempty"`
+	IsdpAid                       []byte                       `protobuf:"bytes,2,opt,name=isdp_aid,json=isdpAid,proto3" json:"isdp_aid,omitempty"`
+	ProfileState                  ProfileState                 `protobuf:"varint,3,opt,name=profile_state,json=profileState,proto3,enum=euicc.lpa.v1.ProfileState" json:"profile_state,omitempty"`
+	ProfileNickname               string                       `protobuf:"bytes,4,opt,name=profile_nickname,json=profileNickname,proto3" json:"profile_nickname,omitempty"`
+	ServiceProviderName           string                       `protobuf:"bytes,5,opt,name=service_provider_name,json=serviceProviderName,proto3" json:"service_provider_name,omitempty"`
+	ProfileName                   string                       `protobuf:"bytes,6,opt,name=profile_name,json=profileName,proto3" json:"profile_name,omitempty"`
+	Icon                          []byte                       `protobuf:"bytes,7,opt,name=icon,proto3" json:"icon,omitempty"`
+	ProfileClass                  ProfileClass                 `protobuf:"varint,8,opt,name=profile_class,json=profileClass,proto3,enum=euicc.lpa.v1.ProfileClass" json:"profile_class,omitempty"`
+	ProfileOwner                  *OperatorID                  `protobuf:"bytes,9,opt,name=profile_owner,json=profileOwner,proto3" json:"profile_owner,omitempty"`
+	NotificationConfigurationInfo []*NotificationConfiguration `protobuf:"bytes,10,rep,name=notification_configuration_info,json=notificationConfigurationInfo,proto3" json:"notification_configuration_info,omitempty"`
+	unknownFields                 protoimpl.UnknownFields
+	sizeCache                     protoimpl.SizeCache
+}
+
+func (x *Profile) Reset() {
+	*x = Profile{}
+	mi := &file_lpa_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Profile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Profile) ProtoMessage() {}
+
+func (x *Profile) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Profile) GetIccid() string {
+	if x != nil {
+		return x.Iccid
+	}
+	return ""
+}
+
+func (x *Profile) GetIsdpAid() []byte {
+	if x != nil {
+		return x.IsdpAid
+	}
+	return nil
+}
+
+func (x *Profile) GetProfileState() ProfileState {
+	if x != nil {
+		return x.ProfileState
+	}
+	return ProfileState_PROFILE_STATE_DISABLED
+}
+
+func (x *Profile) GetProfileNickname() string {
+	if x != nil {
+		return x.ProfileNickname
+	}
+	return ""
+}
+
+func (x *Profile) GetServiceProviderName() string {
+	if x != nil {
+		return x.ServiceProviderName
+	}
+	return ""
+}
+
+func (x *Profile) GetProfileName() string {
+	if x != nil {
+		return x.ProfileName
+	}
+	return ""
+}
+
+func (x *Profile) GetIcon() []byte {
+	if x != nil {
+		return x.Icon
+	}
+	return nil
+}
+
+func (x *Profile) GetProfileClass() ProfileClass {
+	if x != nil {
+		return x.ProfileClass
+	}
+	return ProfileClass_PROFILE_CLASS_TEST
+}
+
+func (x *Profile) GetProfileOwner() *OperatorID {
+	if x != nil {
+		return x.ProfileOwner
+	}
+	return nil
+}
+
+func (x *Profile) GetNotificationConfigurationInfo() []*NotificationConfiguration {
+	if x != nil {
+		return x.NotificationConfigurationInfo
+	}
+	return nil
+}
+
+type OperatorID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plmn          []byte                 `protobuf:"bytes,1,opt,name=plmn,proto3" json:"plmn,omitempty"`
+	Gid1          []byte                 `protobuf:"bytes,2,opt,name=gid1,proto3" json:"gid1,omitempty"`
+	Gid2          []byte                 `protobuf:"bytes,3,opt,name=gid2,proto3" json:"gid2,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperatorID) Reset() {
+	*x = OperatorID{}
+	mi := &file_lpa_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperatorID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperatorID) ProtoMessage() {}
+
+func (x *OperatorID) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperatorID.ProtoReflect.Descriptor instead.
+func (*OperatorID) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *OperatorID) GetPlmn() []byte {
+	if x != nil {
+		return x.Plmn
+	}
+	return nil
+}
+
+func (x *OperatorID) GetGid1() []byte {
+	if x != nil {
+		return x.Gid1
+	}
+	return nil
+}
+
+func (x *OperatorID) GetGid2() []byte {
+	if x != nil {
+		return x.Gid2
+	}
+	return nil
+}
+
+type NotificationConfiguration struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	ProfileManagementOperation NotificationEvent      `protobuf:"varint,1,opt,name=profile_management_operation,json=profileManagementOperation,proto3,enum=euicc.lpa.v1.NotificationEvent" json:"profile_management_operation,omitempty"`
+	Address                    string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *NotificationConfiguration) Reset() {
+	*x = NotificationConfiguration{}
+	mi := &file_lpa_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationConfiguration) ProtoMessage() {}
+
+func (x *NotificationConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationConfiguration.ProtoReflect.Descriptor instead.
+func (*NotificationConfiguration) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *NotificationConfiguration) GetProfileManagementOperation() NotificationEvent {
+	if x != nil {
+		return x.ProfileManagementOperation
+	}
+	return NotificationEvent_NOTIFICATION_EVENT_INSTALL
+}
+
+func (x *NotificationConfiguration) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type ListProfilesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// search_criteria selects the profiles, all the profiles are listed when it is not set.
+	//
+	// Types that are valid to be assigned to SearchCriteria:
+	//
+	//	*ListProfilesRequest_Iccid
+	//	*ListProfilesRequest_IsdpAid
+	//	*ListProfilesRequest_ProfileClass
+	SearchCriteria isListProfilesRequest_SearchCriteria `protobuf_oneof:"search_criteria"`
+	// tags are the additional BER-TLV tags to request for each profile.
+	Tags          [][]byte `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProfilesRequest) Reset() {
+	*x = ListProfilesRequest{}
+	mi := &file_lpa_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProfilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProfilesRequest) ProtoMessage() {}
+
+func (x *ListProfilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProfilesRequest.ProtoReflect.Descriptor instead.
+func (*ListProfilesRequest) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListProfilesRequest) GetSearchCriteria() isListProfilesRequest_SearchCriteria {
+	if x != nil {
+		return x.SearchCriteria
+	}
+	return nil
+}
+
+func (x *ListProfilesRequest) GetIccid() string {
+	if x != nil {
+		if x, ok := x.SearchCriteria.(*ListProfilesRequest_Iccid); ok {
+			return x.Iccid
+		}
+	}
+	return ""
+}
+
+func (x *ListProfilesRequest) GetIsdpAid() []byte {
+	if x != nil {
+		if x, ok := x.SearchCriteria.(*ListProfilesRequest_IsdpAid); ok {
+			return x.IsdpAid
+		}
+	}
+	return nil
+}
+
+func (x *ListProfilesRequest) GetProfileClass() ProfileClass {
+	if x != nil {
+		if x, ok := x.SearchCriteria.(*ListProfilesRequest_ProfileClass); ok {
+			return x.ProfileClass
+		}
+	}
+	return ProfileClass_PROFILE_CLASS_TEST
+}
+
+func (x *ListProfilesRequest) GetTags() [][]byte {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type isListProfilesRequest_SearchCriteria interface {
+	isListProfilesRequest_SearchCriteria()
+}
+
+type ListProfilesRequest_Iccid struct {
+	Iccid string `protobuf:"bytes,1,opt,name=iccid,proto3,oneof"`
+}
+
+type ListProfilesRequest_IsdpAid struct {
+	IsdpAid []byte `protobuf:"bytes,2,opt,name=isdp_aid,json=isdpAid,proto3,oneof"`
+}
+
+type ListProfilesRequest_ProfileClass struct {
+	ProfileClass ProfileClass `protobuf:"varint,3,opt,name=profile_class,json=profileClass,proto3,enum=euicc.lpa.v1.ProfileClass,oneof"`
+}
+
+func (*ListProfilesRequest_Iccid) isListProfilesRequest_SearchCriteria() {}
+
+func (*ListProfilesRequest_IsdpAid) isListProfilesRequest_SearchCriteria() {}
+
+func (*ListProfilesRequest_ProfileClass) isListProfilesRequest_SearchCriteria() {}
+
+type ListProfilesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profiles      []*Profile             `protobuf:"bytes,1,rep,name=profiles,proto3" json:"profiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProfilesResponse) Reset() {
+	*x = ListProfilesResponse{}
+	mi := &file_lpa_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProfilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProfilesResponse) ProtoMessage() {}
+
+func (x *ListProfilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProfilesResponse.ProtoReflect.Descriptor instead.
+func (*ListProfilesResponse) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListProfilesResponse) GetProfiles() []*Profile {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+type ProfileOperationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Identifier:
+	//
+	//	*ProfileOperationRequest_Iccid
+	//	*ProfileOperationRequest_IsdpAid
+	Identifier isProfileOperationRequest_Identifier `protobuf_oneof:"identifier"`
+	// refresh is ignored by DeleteProfile.
+	Refresh       bool `protobuf:"varint,3,opt,name=refresh,proto3" json:"refresh,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileOperationRequest) Reset() {
+	*x = ProfileOperationRequest{}
+	mi := &file_lpa_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileOperationRequest) ProtoMessage() {}
+
+func (x *ProfileOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileOperationRequest.ProtoReflect.Descriptor instead.
+func (*ProfileOperationRequest) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ProfileOperationRequest) GetIdentifier() isProfileOperationRequest_Identifier {
+	if x != nil {
+		return x.Identifier
+	}
+	return nil
+}
+
+func (x *ProfileOperationRequest) GetIccid() string {
+	if x != nil {
+		if x, ok := x.Identifier.(*ProfileOperationRequest_Iccid); ok {
+			return x.Iccid
+		}
+	}
+	return ""
+}
+
+func (x *ProfileOperationRequest) GetIsdpAid() []byte {
+	if x != nil {
+		if x, ok := x.Identifier.(*ProfileOperationRequest_IsdpAid); ok {
+			return x.IsdpAid
+		}
+	}
+	return nil
+}
+
+func (x *ProfileOperationRequest) GetRefresh() bool {
+	if x != nil {
+		return x.Refresh
+	}
+	return false
+}
+
+type isProfileOperationRequest_Identifier interface {
+	isProfileOperationRequest_Identifier()
+}
+
+type ProfileOperationRequest_Iccid struct {
+	Iccid string `protobuf:"bytes,1,opt,name=iccid,proto3,oneof"`
+}
+
+type ProfileOperationRequest_IsdpAid struct {
+	IsdpAid []byte `protobuf:"bytes,2,opt,name=isdp_aid,json=isdpAid,proto3,oneof"`
+}
+
+func (*ProfileOperationRequest_Iccid) isProfileOperationRequest_Identifier() {}
+
+func (*ProfileOperationRequest_IsdpAid) isProfileOperationRequest_Identifier() {}
+
+type SetNicknameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Iccid         string                 `protobuf:"bytes,1,opt,name=iccid,proto3" json:"iccid,omitempty"`
+	Nickname      string                 `protobuf:"bytes,2,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNicknameRequest) Reset() {
+	*x = SetNicknameRequest{}
+	mi := &file_lpa_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNicknameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNicknameRequest) ProtoMessage() {}
+
+func (x *SetNicknameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNicknameRequest.ProtoReflect.Descriptor instead.
+func (*SetNicknameRequest) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SetNicknameRequest) GetIccid() string {
+	if x != nil {
+		return x.Iccid
+	}
+	return ""
+}
+
+func (x *SetNicknameRequest) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+type NotificationMetadata struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	SequenceNumber             int64                  `protobuf:"varint,1,opt,name=sequence_number,json=sequenceNumber,proto3" json:"sequence_number,omitempty"`
+	ProfileManagementOperation NotificationEvent      `protobuf:"varint,2,opt,name=profile_management_operation,json=profileManagementOperation,proto3,enum=euicc.lpa.v1.NotificationEvent" json:"profile_management_operation,omitempty"`
+	Address                    string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Iccid                      string                 `protobuf:"bytes,4,opt,name=iccid,proto3" json:"iccid,omitempty"`
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *NotificationMetadata) Reset() {
+	*x = NotificationMetadata{}
+	mi := &file_lpa_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationMetadata) ProtoMessage() {}
+
+func (x *NotificationMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationMetadata.ProtoReflect.Descriptor instead.
+func (*NotificationMetadata) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *NotificationMetadata) GetSequenceNumber() int64 {
+	if x != nil {
+		return x.SequenceNumber
+	}
+	return 0
+}
+
+func (x *NotificationMetadata) GetProfileManagementOperation() NotificationEvent {
+	if x != nil {
+		return x.ProfileManagementOperation
+	}
+	return NotificationEvent_NOTIFICATION_EVENT_INSTALL
+}
+
+func (x *NotificationMetadata) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *NotificationMetadata) GetIccid() string {
+	if x != nil {
+		return x.Iccid
+	}
+	return ""
+}
+
+type ListNotificationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// filters selects the events of the notifications, all the notifications are listed when it is empty.
+	Filters       []NotificationEvent `protobuf:"varint,1,rep,packed,name=filters,proto3,enum=euicc.lpa.v1.NotificationEvent" json:"filters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotificationsRequest) Reset() {
+	*x = ListNotificationsRequest{}
+	mi := &file_lpa_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotificationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotificationsRequest) ProtoMessage() {}
+
+func (x *ListNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotificationsRequest.ProtoReflect.Descriptor instead.
+func (*ListNotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListNotificationsRequest) GetFilters() []NotificationEvent {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+type ListNotificationsResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Notifications []*NotificationMetadata `protobuf:"bytes,1,rep,name=notifications,proto3" json:"notifications,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotificationsResponse) Reset() {
+	*x = ListNotificationsResponse{}
+	mi := &file_lpa_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotificationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotificationsResponse) ProtoMessage() {}
+
+func (x *ListNotificationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotificationsResponse.ProtoReflect.Descriptor instead.
+func (*ListNotificationsResponse) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListNotificationsResponse) GetNotifications() []*NotificationMetadata {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+type RemoveNotificationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SequenceNumber int64                  `protobuf:"varint,1,opt,name=sequence_number,json=sequenceNumber,proto3" json:"sequence_number,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RemoveNotificationRequest) Reset() {
+	*x = RemoveNotificationRequest{}
+	mi := &file_lpa_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveNotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveNotificationRequest) ProtoMessage() {}
+
+func (x *RemoveNotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveNotificationRequest.ProtoReflect.Descriptor instead.
+func (*RemoveNotificationRequest) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RemoveNotificationRequest) GetSequenceNumber() int64 {
+	if x != nil {
+		return x.SequenceNumber
+	}
+	return 0
+}
+
+type ProcessNotificationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sequence_numbers are the notifications to send, all the pending notifications are sent when all is set.
+	SequenceNumbers []int64 `protobuf:"varint,1,rep,packed,name=sequence_numbers,json=sequenceNumbers,proto3" json:"sequence_numbers,omitempty"`
+	All             bool    `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
+	AutoRemove      bool    `protobuf:"varint,3,opt,name=auto_remove,json=autoRemove,proto3" json:"auto_remove,omitempty"`
+	ContinueOnError bool    `protobuf:"varint,4,opt,name=continue_on_error,json=continueOnError,proto3" json:"continue_on_error,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ProcessNotificationsRequest) Reset() {
+	*x = ProcessNotificationsRequest{}
+	mi := &file_lpa_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessNotificationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessNotificationsRequest) ProtoMessage() {}
+
+func (x *ProcessNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessNotificationsRequest.ProtoReflect.Descriptor instead.
+func (*ProcessNotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ProcessNotificationsRequest) GetSequenceNumbers() []int64 {
+	if x != nil {
+		return x.SequenceNumbers
+	}
+	return nil
+}
+
+func (x *ProcessNotificationsRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+func (x *ProcessNotificationsRequest) GetAutoRemove() bool {
+	if x != nil {
+		return x.AutoRemove
+	}
+	return false
+}
+
+func (x *ProcessNotificationsRequest) GetContinueOnError() bool {
+	if x != nil {
+		return x.ContinueOnError
+	}
+	return false
+}
+
+type ProcessNotificationsResponse struct {
+	state         protoimpl.MessageState       `protogen:"open.v1"`
+	Results       []*NotificationProcessResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessNotificationsResponse) Reset() {
+	*x = ProcessNotificationsResponse{}
+	mi := &file_lpa_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessNotificationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessNotificationsResponse) ProtoMessage() {}
+
+func (x *ProcessNotificationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessNotificationsResponse.ProtoReflect.Descriptor instead.
+func (*ProcessNotificationsResponse) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ProcessNotificationsResponse) GetResults() []*NotificationProcessResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type NotificationProcessResult struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SequenceNumber int64                  `protobuf:"varint,1,opt,name=sequence_number,json=sequenceNumber,proto3" json:"sequence_number,omitempty"`
+	Success        bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Removed        bool                   `protobuf:"varint,3,opt,name=removed,proto3" json:"removed,omitempty"`
+	Error          *Error                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *NotificationProcessResult) Reset() {
+	*x = NotificationProcessResult{}
+	mi := &file_lpa_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationProcessResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationProcessResult) ProtoMessage() {}
+
+func (x *NotificationProcessResult) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationProcessResult.ProtoReflect.Descriptor instead.
+func (*NotificationProcessResult) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *NotificationProcessResult) GetSequenceNumber() int64 {
+	if x != nil {
+		return x.SequenceNumber
+	}
+	return 0
+}
+
+func (x *NotificationProcessResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *NotificationProcessResult) GetRemoved() bool {
+	if x != nil {
+		return x.Removed
+	}
+	return false
+}
+
+func (x *NotificationProcessResult) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type DiscoverProfilesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SmdsAddress   string                 `protobuf:"bytes,1,opt,name=smds_address,json=smdsAddress,proto3" json:"smds_address,omitempty"`
+	Imei          []byte                 `protobuf:"bytes,2,opt,name=imei,proto3" json:"imei,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverProfilesRequest) Reset() {
+	*x = DiscoverProfilesRequest{}
+	mi := &file_lpa_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverProfilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverProfilesRequest) ProtoMessage() {}
+
+func (x *DiscoverProfilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverProfilesRequest.ProtoReflect.Descriptor instead.
+func (*DiscoverProfilesRequest) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DiscoverProfilesRequest) GetSmdsAddress() string {
+	if x != nil {
+		return x.SmdsAddress
+	}
+	return ""
+}
+
+func (x *DiscoverProfilesRequest) GetImei() []byte {
+	if x != nil {
+		return x.Imei
+	}
+	return nil
+}
+
+type DiscoverProfilesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profiles      []*DiscoveredProfile   `protobuf:"bytes,1,rep,name=profiles,proto3" json:"profiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverProfilesResponse) Reset() {
+	*x = DiscoverProfilesResponse{}
+	mi := &file_lpa_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverProfilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverProfilesResponse) ProtoMessage() {}
+
+func (x *DiscoverProfilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverProfilesResponse.ProtoReflect.Descriptor instead.
+func (*DiscoverProfilesResponse) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DiscoverProfilesResponse) GetProfiles() []*DiscoveredProfile {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+type DiscoveredProfile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	SmdpAddress   string                 `protobuf:"bytes,2,opt,name=smdp_address,json=smdpAddress,proto3" json:"smdp_address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoveredProfile) Reset() {
+	*x = DiscoveredProfile{}
+	mi := &file_lpa_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoveredProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoveredProfile) ProtoMessage() {}
+
+func (x *DiscoveredProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoveredProfile.ProtoReflect.Descriptor instead.
+func (*DiscoveredProfile) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DiscoveredProfile) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *DiscoveredProfile) GetSmdpAddress() string {
+	if x != nil {
+		return x.SmdpAddress
+	}
+	return ""
+}
+
+type DownloadProfileRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Request:
+	//
+	//	*DownloadProfileRequest_Start
+	//	*DownloadProfileRequest_Confirm
+	//	*DownloadProfileRequest_ConfirmationCode
+	Request       isDownloadProfileRequest_Request `protobuf_oneof:"request"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadProfileRequest) Reset() {
+	*x = DownloadProfileRequest{}
+	mi := &file_lpa_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadProfileRequest) ProtoMessage() {}
+
+func (x *DownloadProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadProfileRequest.ProtoReflect.Descriptor instead.
+func (*DownloadProfileRequest) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DownloadProfileRequest) GetRequest() isDownloadProfileRequest_Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *DownloadProfileRequest) GetStart() *DownloadProfileStart {
+	if x != nil {
+		if x, ok := x.Request.(*DownloadProfileRequest_Start); ok {
+			return x.Start
+		}
+	}
+	return nil
+}
+
+func (x *DownloadProfileRequest) GetConfirm() bool {
+	if x != nil {
+		if x, ok := x.Request.(*DownloadProfileRequest_Confirm); ok {
+			return x.Confirm
+		}
+	}
+	return false
+}
+
+func (x *DownloadProfileRequest) GetConfirmationCode() string {
+	if x != nil {
+		if x, ok := x.Request.(*DownloadProfileRequest_ConfirmationCode); ok {
+			return x.ConfirmationCode
+		}
+	}
+	return ""
+}
+
+type isDownloadProfileRequest_Request interface {
+	isDownloadProfileRequest_Request()
+}
+
+type DownloadProfileRequest_Start struct {
+	Start *DownloadProfileStart `protobuf:"bytes,1,opt,name=start,proto3,oneof"`
+}
+
+type DownloadProfileRequest_Confirm struct {
+	// confirm answers DownloadProfileResponse.confirm.
+	Confirm bool `protobuf:"varint,2,opt,name=confirm,proto3,oneof"`
+}
+
+type DownloadProfileRequest_ConfirmationCode struct {
+	// confirmation_code answers DownloadProfileResponse.enter_confirmation_code.
+	ConfirmationCode string `protobuf:"bytes,3,opt,name=confirmation_code,json=confirmationCode,proto3,oneof"`
+}
+
+func (*DownloadProfileRequest_Start) isDownloadProfileRequest_Request() {}
+
+func (*DownloadProfileRequest_Confirm) isDownloadProfileRequest_Request() {}
+
+func (*DownloadProfileRequest_ConfirmationCode) isDownloadProfileRequest_Request() {}
+
+type DownloadProfileStart struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// activation_code is the activation code, such as LPA:1$smdp.io$QR-G-5C-1LS-1W1Z9P7.
+	ActivationCode   string `protobuf:"bytes,1,opt,name=activation_code,json=activationCode,proto3" json:"activation_code,omitempty"`
+	Imei             string `protobuf:"bytes,2,opt,name=imei,proto3" json:"imei,omitempty"`
+	ConfirmationCode string `protobuf:"bytes,3,opt,name=confirmation_code,json=confirmationCode,proto3" json:"confirmation_code,omitempty"`
+	// confirm asks the server to let the client confirm the profile before downloading it.
+	Confirm bool `protobuf:"varint,4,opt,name=confirm,proto3" json:"confirm,omitempty"`
+	// enter_confirmation_code asks the server to let the client enter the confirmation code when it is required.
+	EnterConfirmationCode bool `protobuf:"varint,5,opt,name=enter_confirmation_code,json=enterConfirmationCode,proto3" json:"enter_confirmation_code,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *DownloadProfileStart) Reset() {
+	*x = DownloadProfileStart{}
+	mi := &file_lpa_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadProfileStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadProfileStart) ProtoMessage() {}
+
+func (x *DownloadProfileStart) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadProfileStart.ProtoReflect.Descriptor instead.
+func (*DownloadProfileStart) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DownloadProfileStart) GetActivationCode() string {
+	if x != nil {
+		return x.ActivationCode
+	}
+	return ""
+}
+
+func (x *DownloadProfileStart) GetImei() string {
+	if x != nil {
+		return x.Imei
+	}
+	return ""
+}
+
+func (x *DownloadProfileStart) GetConfirmationCode() string {
+	if x != nil {
+		return x.ConfirmationCode
+	}
+	return ""
+}
+
+func (x *DownloadProfileStart) GetConfirm() bool {
+	if x != nil {
+		return x.Confirm
+	}
+	return false
+}
+
+func (x *DownloadProfileStart) GetEnterConfirmationCode() bool {
+	if x != nil {
+		return x.EnterConfirmationCode
+	}
+	return false
+}
+
+type DownloadProfileResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Response:
+	//
+	//	*DownloadProfileResponse_Stage
+	//	*DownloadProfileResponse_Confirm
+	//	*DownloadProfileResponse_EnterConfirmationCode
+	//	*DownloadProfileResponse_Result
+	Response      isDownloadProfileResponse_Response `protobuf_oneof:"response"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadProfileResponse) Reset() {
+	*x = DownloadProfileResponse{}
+	mi := &file_lpa_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadProfileResponse) ProtoMessage() {}
+
+func (x *DownloadProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadProfileResponse.ProtoReflect.Descriptor instead.
+func (*DownloadProfileResponse) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DownloadProfileResponse) GetResponse() isDownloadProfileResponse_Response {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *DownloadProfileResponse) GetStage() DownloadStage {
+	if x != nil {
+		if x, ok := x.Response.(*DownloadProfileResponse_Stage); ok {
+			return x.Stage
+		}
+	}
+	return DownloadStage_DOWNLOAD_STAGE_AUTHENTICATE_CLIENT
+}
+
+func (x *DownloadProfileResponse) GetConfirm() *Profile {
+	if x != nil {
+		if x, ok := x.Response.(*DownloadProfileResponse_Confirm); ok {
+			return x.Confirm
+		}
+	}
+	return nil
+}
+
+func (x *DownloadProfileResponse) GetEnterConfirmationCode() *emptypb.Empty {
+	if x != nil {
+		if x, ok := x.Response.(*DownloadProfileResponse_EnterConfirmationCode); ok {
+			return x.EnterConfirmationCode
+		}
+	}
+	return nil
+}
+
+func (x *DownloadProfileResponse) GetResult() *DownloadResult {
+	if x != nil {
+		if x, ok := x.Response.(*DownloadProfileResponse_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isDownloadProfileResponse_Response interface {
+	isDownloadProfileResponse_Response()
+}
+
+type DownloadProfileResponse_Stage struct {
+	Stage DownloadStage `protobuf:"varint,1,opt,name=stage,proto3,enum=euicc.lpa.v1.DownloadStage,oneof"`
+}
+
+type DownloadProfileResponse_Confirm struct {
+	// confirm is the profile offered by the SM-DP+, the client answers whether to download it.
+	Confirm *Profile `protobuf:"bytes,2,opt,name=confirm,proto3,oneof"`
+}
+
+type DownloadProfileResponse_EnterConfirmationCode struct {
+	// enter_confirmation_code asks the client for the confirmation code.
+	EnterConfirmationCode *emptypb.Empty `protobuf:"bytes,3,opt,name=enter_confirmation_code,json=enterConfirmationCode,proto3,oneof"`
+}
+
+type DownloadProfileResponse_Result struct {
+	Result *DownloadResult `protobuf:"bytes,4,opt,name=result,proto3,oneof"`
+}
+
+func (*DownloadProfileResponse_Stage) isDownloadProfileResponse_Response() {}
+
+func (*DownloadProfileResponse_Confirm) isDownloadProfileResponse_Response() {}
+
+func (*DownloadProfileResponse_EnterConfirmationCode) isDownloadProfileResponse_Response() {}
+
+func (*DownloadProfileResponse_Result) isDownloadProfileResponse_Response() {}
+
+type DownloadResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// profile_installation_result is the ProfileInstallationResult signed by the eUICC,
+	// it is empty when the download was not confirmed.
+	ProfileInstallationResult []byte `protobuf:"bytes,1,opt,name=profile_installation_result,json=profileInstallationResult,proto3" json:"profile_installation_result,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *DownloadResult) Reset() {
+	*x = DownloadResult{}
+	mi := &file_lpa_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadResult) ProtoMessage() {}
+
+func (x *DownloadResult) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadResult.ProtoReflect.Descriptor instead.
+func (*DownloadResult) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DownloadResult) GetProfileInstallationResult() []byte {
+	if x != nil {
+		return x.ProfileInstallationResult
+	}
+	return nil
+}
+
+// Error is the detail of a failed call.
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Error:
+	//
+	//	*Error_ResultError
+	//	*Error_RspError
+	//	*Error_LoadBoundProfilePackageError
+	Error isError_Error `protobuf_oneof:"error"`
+	// message is the message of the error, the status message for the error of a call.
+	Message       string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_lpa_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *Error) GetError() isError_Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *Error) GetResultError() *ResultError {
+	if x != nil {
+		if x, ok := x.Error.(*Error_ResultError); ok {
+			return x.ResultError
+		}
+	}
+	return nil
+}
+
+func (x *Error) GetRspError() *RSPError {
+	if x != nil {
+		if x, ok := x.Error.(*Error_RspError); ok {
+			return x.RspError
+		}
+	}
+	return nil
+}
+
+func (x *Error) GetLoadBoundProfilePackageError() *LoadBoundProfilePackageError {
+	if x != nil {
+		if x, ok := x.Error.(*Error_LoadBoundProfilePackageError); ok {
+			return x.LoadBoundProfilePackageError
+		}
+	}
+	return nil
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type isError_Error interface {
+	isError_Error()
+}
+
+type Error_ResultError struct {
+	ResultError *ResultError `protobuf:"bytes,1,opt,name=result_error,json=resultError,proto3,oneof"`
+}
+
+type Error_RspError struct {
+	RspError *RSPError `protobuf:"bytes,2,opt,name=rsp_error,json=rspError,proto3,oneof"`
+}
+
+type Error_LoadBoundProfilePackageError struct {
+	LoadBoundProfilePackageError *LoadBoundProfilePackageError `protobuf:"bytes,3,opt,name=load_bound_profile_package_error,json=loadBoundProfilePackageError,proto3,oneof"`
+}
+
+func (*Error_ResultError) isError_Error() {}
+
+func (*Error_RspError) isError_Error() {}
+
+func (*Error_LoadBoundProfilePackageError) isError_Error() {}
+
+// ResultError is the error result of an ES10 function.
+type ResultError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Function      string                 `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
+	Result        int32                  `protobuf:"varint,2,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResultError) Reset() {
+	*x = ResultError{}
+	mi := &file_lpa_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResultError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResultError) ProtoMessage() {}
+
+func (x *ResultError) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResultError.ProtoReflect.Descriptor instead.
+func (*ResultError) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ResultError) GetFunction() string {
+	if x != nil {
+		return x.Function
+	}
+	return ""
+}
+
+func (x *ResultError) GetResult() int32 {
+	if x != nil {
+		return x.Result
+	}
+	return 0
+}
+
+// RSPError is the error of an ES9+ or ES11 function.
+type RSPError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Function      string                 `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
+	TransactionId []byte                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	SubjectCode   string                 `protobuf:"bytes,4,opt,name=subject_code,json=subjectCode,proto3" json:"subject_code,omitempty"`
+	ReasonCode    string                 `protobuf:"bytes,5,opt,name=reason_code,json=reasonCode,proto3" json:"reason_code,omitempty"`
+	Message       string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RSPError) Reset() {
+	*x = RSPError{}
+	mi := &file_lpa_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RSPError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RSPError) ProtoMessage() {}
+
+func (x *RSPError) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RSPError.ProtoReflect.Descriptor instead.
+func (*RSPError) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *RSPError) GetFunction() string {
+	if x != nil {
+		return x.Function
+	}
+	return ""
+}
+
+func (x *RSPError) GetTransactionId() []byte {
+	if x != nil {
+		return x.TransactionId
+	}
+	return nil
+}
+
+func (x *RSPError) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RSPError) GetSubjectCode() string {
+	if x != nil {
+		return x.SubjectCode
+	}
+	return ""
+}
+
+func (x *RSPError) GetReasonCode() string {
+	if x != nil {
+		return x.ReasonCode
+	}
+	return ""
+}
+
+func (x *RSPError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// LoadBoundProfilePackageError is the error result of the installation of a profile.
+type LoadBoundProfilePackageError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BppCommandId  uint32                 `protobuf:"varint,1,opt,name=bpp_command_id,json=bppCommandId,proto3" json:"bpp_command_id,omitempty"`
+	ErrorReason   uint32                 `protobuf:"varint,2,opt,name=error_reason,json=errorReason,proto3" json:"error_reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadBoundProfilePackageError) Reset() {
+	*x = LoadBoundProfilePackageError{}
+	mi := &file_lpa_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadBoundProfilePackageError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBoundProfilePackageError) ProtoMessage() {}
+
+func (x *LoadBoundProfilePackageError) ProtoReflect() protoreflect.Message {
+	mi := &file_lpa_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBoundProfilePackageError.ProtoReflect.Descriptor instead.
+func (*LoadBoundProfilePackageError) Descriptor() ([]byte, []int) {
+	return file_lpa_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *LoadBoundProfilePackageError) GetBppCommandId() uint32 {
+	if x != nil {
+		return x.BppCommandId
+	}
+	return 0
+}
+
+func (x *LoadBoundProfilePackageError) GetErrorReason() uint32 {
+	if x != nil {
+		return x.ErrorReason
+	}
+	return 0
+}
+
+var File_lpa_proto protoreflect.FileDescriptor
+
+const file_lpa_proto_rawDesc = "" +
+	"\n" +
+	"\tlpa.proto\x12\feuicc.lpa.v1\x1a\x1bgoogle/protobuf/empty.proto\"\x17\n" +
+	"\x03EID\x12\x10\n" +
+	"\x03eid\x18\x01 \x01(\fR\x03eid\"s\n" +
+	"\x13ConfiguredAddresses\x120\n" +
+	"\x14default_smdp_address\x18\x01 \x01(\tR\x12defaultSmdpAddress\x12*\n" +
+	"\x11root_smds_address\x18\x02 \x01(\tR\x0frootSmdsAddress\"6\n" +
+	"\x1aSetDefaultDPAddressRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\"\x85\x02\n" +
+	"\bChipInfo\x12\x10\n" +
+	"\x03eid\x18\x01 \x01(\tR\x03eid\x12T\n" +
+	"\x14configured_addresses\x18\x02 \x01(\v2!.euicc.lpa.v1.ConfiguredAddressesR\x13configuredAddresses\x12.\n" +
+	"\x05info2\x18\x03 \x01(\v2\x18.euicc.lpa.v1.EUICCInfo2R\x05info2\x12a\n" +
+	"\x19rules_authorisation_table\x18\x04 \x03(\v2%.euicc.lpa.v1.RulesAuthorisationTableR\x17rulesAuthorisationTable\"\xad\x06\n" +
+	"\n" +
+	"EUICCInfo2\x12'\n" +
+	"\x0fprofile_version\x18\x01 \x01(\tR\x0eprofileVersion\x12\x10\n" +
+	"\x03svn\x18\x02 \x01(\tR\x03svn\x12,\n" +
+	"\x12euicc_firmware_ver\x18\x03 \x01(\tR\x10euiccFirmwareVer\x12I\n" +
+	"\x11ext_card_resource\x18\x04 \x01(\v2\x1d.euicc.lpa.v1.ExtCardResourceR\x0fextCardResource\x12'\n" +
+	"\x0fuicc_capability\x18\x05 \x03(\tR\x0euiccCapability\x12)\n" +
+	"\x10ts102241_version\x18\x06 \x01(\tR\x0fts102241Version\x126\n" +
+	"\x17global_platform_version\x18\a \x01(\tR\x15globalPlatformVersion\x12%\n" +
+	"\x0ersp_capability\x18\b \x03(\tR\rrspCapability\x12L\n" +
+	"$euicc_ci_pk_id_list_for_verification\x18\t \x03(\tR\x1eeuiccCiPkIdListForVerification\x12B\n" +
+	"\x1feuicc_ci_pk_id_list_for_signing\x18\n" +
+	" \x03(\tR\x19euiccCiPkIdListForSigning\x12%\n" +
+	"\x0eeuicc_category\x18\v \x01(\tR\reuiccCategory\x12C\n" +
+	"\x1eforbidden_profile_policy_rules\x18\f \x03(\tR\x1bforbiddenProfilePolicyRules\x12\x1d\n" +
+	"\n" +
+	"pp_version\x18\r \x01(\tR\tppVersion\x128\n" +
+	"\x18sas_accreditation_number\x18\x0e \x01(\tR\x16sasAccreditationNumber\x12a\n" +
+	"\x19certification_data_object\x18\x0f \x01(\v2%.euicc.lpa.v1.CertificationDataObjectR\x17certificationDataObject\"\xb1\x01\n" +
+	"\x0fExtCardResource\x123\n" +
+	"\x15installed_application\x18\x01 \x01(\rR\x14installedApplication\x127\n" +
+	"\x18free_non_volatile_memory\x18\x02 \x01(\rR\x15freeNonVolatileMemory\x120\n" +
+	"\x14free_volatile_memory\x18\x03 \x01(\rR\x12freeVolatileMemory\"n\n" +
+	"\x17CertificationDataObject\x12%\n" +
+	"\x0eplatform_label\x18\x01 \x01(\tR\rplatformLabel\x12,\n" +
+	"\x12discovery_base_url\x18\x02 \x01(\tR\x10discoveryBaseUrl\"\x9b\x01\n" +
+	"\x17RulesAuthorisationTable\x12\x17\n" +
+	"\appr_ids\x18\x01 \x03(\tR\x06pprIds\x12J\n" +
+	"\x11allowed_operators\x18\x02 \x03(\v2\x1d.euicc.lpa.v1.AllowedOperatorR\x10allowedOperators\x12\x1b\n" +
+	"\tppr_flags\x18\x03 \x03(\tR\bpprFlags\"M\n" +
+	"\x0fAllowedOperator\x12\x12\n" +
+	"\x04plmn\x18\x01 \x01(\tR\x04plmn\x12\x12\n" +
+	"\x04gid1\x18\x02 \x01(\tR\x04gid1\x12\x12\n" +
+	"\x04gid2\x18\x03 \x01(\tR\x04gid2\"\x82\x04\n" +
+	"\aProfile\x12\x14\n" +
+	"\x05iccid\x18\x01 \x01(\tR\x05iccid\x12\x19\n" +
+	"\bisdp_aid\x18\x02 \x01(\fR\aisdpAid\x12?\n" +
+	"\rprofile_state\x18\x03 \x01(\x0e2\x1a.euicc.lpa.v1.ProfileStateR\fprofileState\x12)\n" +
+	"\x10profile_nickname\x18\x04 \x01(\tR\x0fprofileNickname\x122\n" +
+	"\x15service_provider_name\x18\x05 \x01(\tR\x13serviceProviderName\x12!\n" +
+	"\fprofile_name\x18\x06 \x01(\tR\vprofileName\x12\x12\n" +
+	"\x04icon\x18\a \x01(\fR\x04icon\x12?\n" +
+	"\rprofile_class\x18\b \x01(\x0e2\x1a.euicc.lpa.v1.ProfileClassR\fprofileClass\x12=\n" +
+	"\rprofile_owner\x18\t \x01(\v2\x18.euicc.lpa.v1.OperatorIDR\fprofileOwner\x12o\n" +
+	"\x1fnotification_configuration_info\x18\n" +
+	" \x03(\v2'.euicc.lpa.v1.NotificationConfigurationR\x1dnotificationConfigurationInfo\"H\n" +
+	"\n" +
+	"OperatorID\x12\x12\n" +
+	"\x04plmn\x18\x01 \x01(\fR\x04plmn\x12\x12\n" +
+	"\x04gid1\x18\x02 \x01(\fR\x04gid1\x12\x12\n" +
+	"\x04gid2\x18\x03 \x01(\fR\x04gid2\"\x98\x01\n" +
+	"\x19NotificationConfiguration\x12a\n" +
+	"\x1cprofile_management_operation\x18\x01 \x01(\x0e2\x1f.euicc.lpa.v1.NotificationEventR\x1aprofileManagementOperation\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\"\xb4\x01\n" +
+	"\x13ListProfilesRequest\x12\x16\n" +
+	"\x05iccid\x18\x01 \x01(\tH\x00R\x05iccid\x12\x1b\n" +
+	"\bisdp_aid\x18\x02 \x01(\fH\x00R\aisdpAid\x12A\n" +
+	"\rprofile_class\x18\x03 \x01(\x0e2\x1a.euicc.lpa.v1.ProfileClassH\x00R\fprofileClass\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\fR\x04tagsB\x11\n" +
+	"\x0fsearch_criteria\"I\n" +
+	"\x14ListProfilesResponse\x121\n" +
+	"\bprofiles\x18\x01 \x03(\v2\x15.euicc.lpa.v1.ProfileR\bprofiles\"v\n" +
+	"\x17ProfileOperationRequest\x12\x16\n" +
+	"\x05iccid\x18\x01 \x01(\tH\x00R\x05iccid\x12\x1b\n" +
+	"\bisdp_aid\x18\x02 \x01(\fH\x00R\aisdpAid\x12\x18\n" +
+	"\arefresh\x18\x03 \x01(\bR\arefreshB\f\n" +
+	"\n" +
+	"identifier\"F\n" +
+	"\x12SetNicknameRequest\x12\x14\n" +
+	"\x05iccid\x18\x01 \x01(\tR\x05iccid\x12\x1a\n" +
+	"\bnickname\x18\x02 \x01(\tR\bnickname\"\xd2\x01\n" +
+	"\x14NotificationMetadata\x12'\n" +
+	"\x0fsequence_number\x18\x01 \x01(\x03R\x0esequenceNumber\x12a\n" +
+	"\x1cprofile_management_operation\x18\x02 \x01(\x0e2\x1f.euicc.lpa.v1.NotificationEventR\x1aprofileManagementOperation\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12\x14\n" +
+	"\x05iccid\x18\x04 \x01(\tR\x05iccid\"U\n" +
+	"\x18ListNotificationsRequest\x129\n" +
+	"\afilters\x18\x01 \x03(\x0e2\x1f.euicc.lpa.v1.NotificationEventR\afilters\"e\n" +
+	"\x19ListNotificationsResponse\x12H\n" +
+	"\rnotifications\x18\x01 \x03(\v2\".euicc.lpa.v1.NotificationMetadataR\rnotifications\"D\n" +
+	"\x19RemoveNotificationRequest\x12'\n" +
+	"\x0fsequence_number\x18\x01 \x01(\x03R\x0esequenceNumber\"\xa7\x01\n" +
+	"\x1bProcessNotificationsRequest\x12)\n" +
+	"\x10sequence_numbers\x18\x01 \x03(\x03R\x0fsequenceNumbers\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\x12\x1f\n" +
+	"\vauto_remove\x18\x03 \x01(\bR\n" +
+	"autoRemove\x12*\n" +
+	"\x11continue_on_error\x18\x04 \x01(\bR\x0fcontinueOnError\"a\n" +
+	"\x1cProcessNotificationsResponse\x12A\n" +
+	"\aresults\x18\x01 \x03(\v2'.euicc.lpa.v1.NotificationProcessResultR\aresults\"\xa3\x01\n" +
+	"\x19NotificationProcessResult\x12'\n" +
+	"\x0fsequence_number\x18\x01 \x01(\x03R\x0esequenceNumber\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x18\n" +
+	"\aremoved\x18\x03 \x01(\bR\aremoved\x12)\n" +
+	"\x05error\x18\x04 \x01(\v2\x13.euicc.lpa.v1.ErrorR\x05error\"P\n" +
+	"\x17DiscoverProfilesRequest\x12!\n" +
+	"\fsmds_address\x18\x01 \x01(\tR\vsmdsAddress\x12\x12\n" +
+	"\x04imei\x18\x02 \x01(\fR\x04imei\"W\n" +
+	"\x18DiscoverProfilesResponse\x12;\n" +
+	"\bprofiles\x18\x01 \x03(\v2\x1f.euicc.lpa.v1.DiscoveredProfileR\bprofiles\"Q\n" +
+	"\x11DiscoveredProfile\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12!\n" +
+	"\fsmdp_address\x18\x02 \x01(\tR\vsmdpAddress\"\xaa\x01\n" +
+	"\x16DownloadProfileRequest\x12:\n" +
+	"\x05start\x18\x01 \x01(\v2\".euicc.lpa.v1.DownloadProfileStartH\x00R\x05start\x12\x1a\n" +
+	"\aconfirm\x18\x02 \x01(\bH\x00R\aconfirm\x12-\n" +
+	"\x11confirmation_code\x18\x03 \x01(\tH\x00R\x10confirmationCodeB\t\n" +
+	"\arequest\"\xd2\x01\n" +
+	"\x14DownloadProfileStart\x12'\n" +
+	"\x0factivation_code\x18\x01 \x01(\tR\x0eactivationCode\x12\x12\n" +
+	"\x04imei\x18\x02 \x01(\tR\x04imei\x12+\n" +
+	"\x11confirmation_code\x18\x03 \x01(\tR\x10confirmationCode\x12\x18\n" +
+	"\aconfirm\x18\x04 \x01(\bR\aconfirm\x126\n" +
+	"\x17enter_confirmation_code\x18\x05 \x01(\bR\x15enterConfirmationCode\"\x97\x02\n" +
+	"\x17DownloadProfileResponse\x123\n" +
+	"\x05stage\x18\x01 \x01(\x0e2\x1b.euicc.lpa.v1.DownloadStageH\x00R\x05stage\x121\n" +
+	"\aconfirm\x18\x02 \x01(\v2\x15.euicc.lpa.v1.ProfileH\x00R\aconfirm\x12P\n" +
+	"\x17enter_confirmation_code\x18\x03 \x01(\v2\x16.google.protobuf.EmptyH\x00R\x15enterConfirmationCode\x126\n" +
+	"\x06result\x18\x04 \x01(\v2\x1c.euicc.lpa.v1.DownloadResultH\x00R\x06resultB\n" +
+	"\n" +
+	"\bresponse\"P\n" +
+	"\x0eDownloadResult\x12>\n" +
+	"\x1bprofile_installation_result\x18\x01 \x01(\fR\x19profileInstallationResult\"\x97\x02\n" +
+	"\x05Error\x12>\n" +
+	"\fresult_error\x18\x01 \x01(\v2\x19.euicc.lpa.v1.ResultErrorH\x00R\vresultError\x125\n" +
+	"\trsp_error\x18\x02 \x01(\v2\x16.euicc.lpa.v1.RSPErrorH\x00R\brspError\x12t\n" +
+	" load_bound_profile_package_error\x18\x03 \x01(\v2*.euicc.lpa.v1.LoadBoundProfilePackageErrorH\x00R\x1cloadBoundProfilePackageError\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessageB\a\n" +
+	"\x05error\"A\n" +
+	"\vResultError\x12\x1a\n" +
+	"\bfunction\x18\x01 \x01(\tR\bfunction\x12\x16\n" +
+	"\x06result\x18\x02 \x01(\x05R\x06result\"\xc3\x01\n" +
+	"\bRSPError\x12\x1a\n" +
+	"\bfunction\x18\x01 \x01(\tR\bfunction\x12%\n" +
+	"\x0etransaction_id\x18\x02 \x01(\fR\rtransactionId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12!\n" +
+	"\fsubject_code\x18\x04 \x01(\tR\vsubjectCode\x12\x1f\n" +
+	"\vreason_code\x18\x05 \x01(\tR\n" +
+	"reasonCode\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\"g\n" +
+	"\x1cLoadBoundProfilePackageError\x12$\n" +
+	"\x0ebpp_command_id\x18\x01 \x01(\rR\fbppCommandId\x12!\n" +
+	"\ferror_reason\x18\x02 \x01(\rR\verrorReason*E\n" +
+	"\fProfileState\x12\x1a\n" +
+	"\x16PROFILE_STATE_DISABLED\x10\x00\x12\x19\n" +
+	"\x15PROFILE_STATE_ENABLED\x10\x01*e\n" +
+	"\fProfileClass\x12\x16\n" +
+	"\x12PROFILE_CLASS_TEST\x10\x00\x12\x1e\n" +
+	"\x1aPROFILE_CLASS_PROVISIONING\x10\x01\x12\x1d\n" +
+	"\x19PROFILE_CLASS_OPERATIONAL\x10\x02*\x91\x01\n" +
+	"\x11NotificationEvent\x12\x1e\n" +
+	"\x1aNOTIFICATION_EVENT_INSTALL\x10\x00\x12\x1d\n" +
+	"\x19NOTIFICATION_EVENT_ENABLE\x10\x01\x12\x1e\n" +
+	"\x1aNOTIFICATION_EVENT_DISABLE\x10\x02\x12\x1d\n" +
+	"\x19NOTIFICATION_EVENT_DELETE\x10\x03*{\n" +
+	"\rDownloadStage\x12&\n" +
+	"\"DOWNLOAD_STAGE_AUTHENTICATE_CLIENT\x10\x00\x12&\n" +
+	"\"DOWNLOAD_STAGE_AUTHENTICATE_SERVER\x10\x01\x12\x1a\n" +
+	"\x16DOWNLOAD_STAGE_INSTALL\x10\x022\xea\t\n" +
+	"\x03LPA\x123\n" +
+	"\x06GetEID\x12\x16.google.protobuf.Empty\x1a\x11.euicc.lpa.v1.EID\x12=\n" +
+	"\vGetChipInfo\x12\x16.google.protobuf.Empty\x1a\x16.euicc.lpa.v1.ChipInfo\x12S\n" +
+	"\x16GetConfiguredAddresses\x12\x16.google.protobuf.Empty\x1a!.euicc.lpa.v1.ConfiguredAddresses\x12W\n" +
+	"\x13SetDefaultDPAddress\x12(.euicc.lpa.v1.SetDefaultDPAddressRequest\x1a\x16.google.protobuf.Empty\x12U\n" +
+	"\fListProfiles\x12!.euicc.lpa.v1.ListProfilesRequest\x1a\".euicc.lpa.v1.ListProfilesResponse\x12N\n" +
+	"\rEnableProfile\x12%.euicc.lpa.v1.ProfileOperationRequest\x1a\x16.google.protobuf.Empty\x12O\n" +
+	"\x0eDisableProfile\x12%.euicc.lpa.v1.ProfileOperationRequest\x1a\x16.google.protobuf.Empty\x12N\n" +
+	"\rDeleteProfile\x12%.euicc.lpa.v1.ProfileOperationRequest\x1a\x16.google.protobuf.Empty\x12G\n" +
+	"\vSetNickname\x12 .euicc.lpa.v1.SetNicknameRequest\x1a\x16.google.protobuf.Empty\x12=\n" +
+	"\vMemoryReset\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\x12d\n" +
+	"\x11ListNotifications\x12&.euicc.lpa.v1.ListNotificationsRequest\x1a'.euicc.lpa.v1.ListNotificationsResponse\x12U\n" +
+	"\x12RemoveNotification\x12'.euicc.lpa.v1.RemoveNotificationRequest\x1a\x16.google.protobuf.Empty\x12m\n" +
+	"\x14ProcessNotifications\x12).euicc.lpa.v1.ProcessNotificationsRequest\x1a*.euicc.lpa.v1.ProcessNotificationsResponse\x12a\n" +
+	"\x10DiscoverProfiles\x12%.euicc.lpa.v1.DiscoverProfilesRequest\x1a&.euicc.lpa.v1.DiscoverProfilesResponse\x12b\n" +
+	"\x0fDownloadProfile\x12$.euicc.lpa.v1.DownloadProfileRequest\x1a%.euicc.lpa.v1.DownloadProfileResponse(\x010\x01B4Z2github.com/KilimcininKorOglu/euicc-go/remote/lpapbb\x06proto3"
+
+var (
+	file_lpa_proto_rawDescOnce sync.Once
+	file_lpa_proto_rawDescData []byte
+)
+
+func file_lpa_proto_rawDescGZIP() []byte {
+	file_lpa_proto_rawDescOnce.Do(func() {
+		file_lpa_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lpa_proto_rawDesc), len(file_lpa_proto_rawDesc)))
+	})
+	return file_lpa_proto_rawDescData
+}
+
+var file_lpa_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_lpa_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
+var file_lpa_proto_goTypes = []any{
+	(ProfileState)(0),                    // 0: euicc.lpa.v1.ProfileState
+	(ProfileClass)(0),                    // 1: euicc.lpa.v1.ProfileClass
+	(NotificationEvent)(0),               // 2: euicc.lpa.v1.NotificationEvent
+	(DownloadStage)(0),                   // 3: euicc.lpa.v1.DownloadStage
+	(*EID)(nil),                          // 4: euicc.lpa.v1.EID
+	(*ConfiguredAddresses)(nil),          // 5: euicc.lpa.v1.ConfiguredAddresses
+	(*SetDefaultDPAddressRequest)(nil),   // 6: euicc.lpa.v1.SetDefaultDPAddressRequest
+	(*ChipInfo)(nil),                     // 7: euicc.lpa.v1.ChipInfo
+	(*EUICCInfo2)(nil),                   // 8: euicc.lpa.v1.EUICCInfo2
+	(*ExtCardResource)(nil),              // 9: euicc.lpa.v1.ExtCardResource
+	(*CertificationDataObject)(nil),      // 10: euicc.lpa.v1.CertificationDataObject
+	(*RulesAuthorisationTable)(nil),      // 11: euicc.lpa.v1.RulesAuthorisationTable
+	(*AllowedOperator)(nil),              // 12: euicc.lpa.v1.AllowedOperator
+	(*Profile)(nil),                      // 13: euicc.lpa.v1.Profile
+	(*OperatorID)(nil),                   // 14: euicc.lpa.v1.OperatorID
+	(*NotificationConfiguration)(nil),    // 15: euicc.lpa.v1.NotificationConfiguration
+	(*ListProfilesRequest)(nil),          // 16: euicc.lpa.v1.ListProfilesRequest
+	(*ListProfilesResponse)(nil),         // 17: euicc.lpa.v1.ListProfilesResponse
+	(*ProfileOperationRequest)(nil),      // 18: euicc.lpa.v1.ProfileOperationRequest
+	(*SetNicknameRequest)(nil),           // 19: euicc.lpa.v1.SetNicknameRequest
+	(*NotificationMetadata)(nil),         // 20: euicc.lpa.v1.NotificationMetadata
+	(*ListNotificationsRequest)(nil),     // 21: euicc.lpa.v1.ListNotificationsRequest
+	(*ListNotificationsResponse)(nil),    // 22: euicc.lpa.v1.ListNotificationsResponse
+	(*RemoveNotificationRequest)(nil),    // 23: euicc.lpa.v1.RemoveNotificationRequest
+	(*ProcessNotificationsRequest)(nil),  // 24: euicc.lpa.v1.ProcessNotificationsRequest
+	(*ProcessNotificationsResponse)(nil), // 25: euicc.lpa.v1.ProcessNotificationsResponse
+	(*NotificationProcessResult)(nil),    // 26: euicc.lpa.v1.NotificationProcessResult
+	(*DiscoverProfilesRequest)(nil),      // 27: euicc.lpa.v1.DiscoverProfilesRequest
+	(*DiscoverProfilesResponse)(nil),     // 28: euicc.lpa.v1.DiscoverProfilesResponse
+	(*DiscoveredProfile)(nil),            // 29: euicc.lpa.v1.DiscoveredProfile
+	(*DownloadProfileRequest)(nil),       // 30: euicc.lpa.v1.DownloadProfileRequest
+	(*DownloadProfileStart)(nil),         // 31: euicc.lpa.v1.DownloadProfileStart
+	(*DownloadProfileResponse)(nil),      // 32: euicc.lpa.v1.DownloadProfileResponse
+	(*DownloadResult)(nil),               // 33: euicc.lpa.v1.DownloadResult
+	(*Error)(nil),                        // 34: euicc.lpa.v1.Error
+	(*ResultError)(nil),                  // 35: euicc.lpa.v1.ResultError
+	(*RSPError)(nil),                     // 36: euicc.lpa.v1.RSPError
+	(*LoadBoundProfilePackageError)(nil), // 37: euicc.lpa.v1.LoadBoundProfilePackageError
+	(*emptypb.Empty)(nil),                // 38: google.protobuf.Empty
+}
+var file_lpa_proto_depIdxs = []int32{
+	5,  // 0: euicc.lpa.v1.ChipInfo.configured_addresses:type_name -> euicc.lpa.v1.ConfiguredAddresses
+	8,  // 1: euicc.lpa.v1.ChipInfo.info2:type_name -> euicc.lpa.v1.EUICCInfo2
+	11, // 2: euicc.lpa.v1.ChipInfo.rules_authorisation_table:type_name -> euicc.lpa.v1.RulesAuthorisationTable
+	9,  // 3: euicc.lpa.v1.EUICCInfo2.ext_card_resource:type_name -> euicc.lpa.v1.ExtCardResource
+	10, // 4: euicc.lpa.v1.EUICCInfo2.certification_data_object:type_name -> euicc.lpa.v1.CertificationDataObject
+	12, // 5: euicc.lpa.v1.RulesAuthorisationTable.allowed_operators:type_name -> euicc.lpa.v1.AllowedOperator
+	0,  // 6: euicc.lpa.v1.Profile.profile_state:type_name -> euicc.lpa.v1.ProfileState
+	1,  // 7: euicc.lpa.v1.Profile.profile_class:type_name -> euicc.lpa.v1.ProfileClass
+	14, // 8: euicc.lpa.v1.Profile.profile_owner:type_name -> euicc.lpa.v1.OperatorID
+	15, // 9: euicc.lpa.v1.Profile.notification_configuration_info:type_name -> euicc.lpa.v1.NotificationConfiguration
+	2,  // 10: euicc.lpa.v1.NotificationConfiguration.profile_management_operation:type_name -> euicc.lpa.v1.NotificationEvent
+	1,  // 11: euicc.lpa.v1.ListProfilesRequest.profile_class:type_name -> euicc.lpa.v1.ProfileClass
+	13, // 12: euicc.lpa.v1.ListProfilesResponse.profiles:type_name -> euicc.lpa.v1.Profile
+	2,  // 13: euicc.lpa.v1.NotificationMetadata.profile_management_operation:type_name -> euicc.lpa.v1.NotificationEvent
+	2,  // 14: euicc.lpa.v1.ListNotificationsRequest.filters:type_name -> euicc.lpa.v1.NotificationEvent
+	20, // 15: euicc.lpa.v1.ListNotificationsResponse.notifications:type_name -> euicc.lpa.v1.NotificationMetadata
+	26, // 16: euicc.lpa.v1.ProcessNotificationsResponse.results:type_name -> euicc.lpa.v1.NotificationProcessResult
+	34, // 17: euicc.lpa.v1.NotificationProcessResult.error:type_name -> euicc.lpa.v1.Error
+	29, // 18: euicc.lpa.v1.DiscoverProfilesResponse.profiles:type_name -> euicc.lpa.v1.DiscoveredProfile
+	31, // 19: euicc.lpa.v1.DownloadProfileRequest.start:type_name -> euicc.lpa.v1.DownloadProfileStart
+	3,  // 20: euicc.lpa.v1.DownloadProfileResponse.stage:type_name -> euicc.lpa.v1.DownloadStage
+	13, // 21: euicc.lpa.v1.DownloadProfileResponse.confirm:type_name -> euicc.lpa.v1.Profile
+	38, // 22: euicc.lpa.v1.DownloadProfileResponse.enter_confirmation_code:type_name -> google.protobuf.Empty
+	33, // 23: euicc.lpa.v1.DownloadProfileResponse.result:type_name -> euicc.lpa.v1.DownloadResult
+	35, // 24: euicc.lpa.v1.Error.result_error:type_name -> euicc.lpa.v1.ResultError
+	36, // 25: euicc.lpa.v1.Error.rsp_error:type_name -> euicc.lpa.v1.RSPError
+	37, // 26: euicc.lpa.v1.Error.load_bound_profile_package_error:type_name -> euicc.lpa.v1.LoadBoundProfilePackageError
+	38, // 27: euicc.lpa.v1.LPA.GetEID:input_type -> google.protobuf.Empty
+	38, // 28: euicc.lpa.v1.LPA.GetChipInfo:input_type -> google.protobuf.Empty
+	38, // 29: euicc.lpa.v1.LPA.GetConfiguredAddresses:input_type -> google.protobuf.Empty
+	6,  // 30: euicc.lpa.v1.LPA.SetDefaultDPAddress:input_type -> euicc.lpa.v1.SetDefaultDPAddressRequest
+	16, // 31: euicc.lpa.v1.LPA.ListProfiles:input_type -> euicc.lpa.v1.ListProfilesRequest
+	18, // 32: euicc.lpa.v1.LPA.EnableProfile:input_type -> euicc.lpa.v1.ProfileOperationRequest
+	18, // 33: euicc.lpa.v1.LPA.DisableProfile:input_type -> euicc.lpa.v1.ProfileOperationRequest
+	18, // 34: euicc.lpa.v1.LPA.DeleteProfile:input_type -> euicc.lpa.v1.ProfileOperationRequest
+	19, // 35: euicc.lpa.v1.LPA.SetNickname:input_type -> euicc.lpa.v1.SetNicknameRequest
+	38, // 36: euicc.lpa.v1.LPA.MemoryReset:input_type -> google.protobuf.Empty
+	21, // 37: euicc.lpa.v1.LPA.ListNotifications:input_type -> euicc.lpa.v1.ListNotificationsRequest
+	23, // 38: euicc.lpa.v1.LPA.RemoveNotification:input_type -> euicc.lpa.v1.RemoveNotificationRequest
+	24, // 39: euicc.lpa.v1.LPA.ProcessNotifications:input_type -> euicc.lpa.v1.ProcessNotificationsRequest
+	27, // 40: euicc.lpa.v1.LPA.DiscoverProfiles:input_type -> euicc.lpa.v1.DiscoverProfilesRequest
+	30, // 41: euicc.lpa.v1.LPA.DownloadProfile:input_type -> euicc.lpa.v1.DownloadProfileRequest
+	4,  // 42: euicc.lpa.v1.LPA.GetEID:output_type -> euicc.lpa.v1.EID
+	7,  // 43: euicc.lpa.v1.LPA.GetChipInfo:output_type -> euicc.lpa.v1.ChipInfo
+	5,  // 44: euicc.lpa.v1.LPA.GetConfiguredAddresses:output_type -> euicc.lpa.v1.ConfiguredAddresses
+	38, // 45: euicc.lpa.v1.LPA.SetDefaultDPAddress:output_type -> google.protobuf.Empty
+	17, // 46: euicc.lpa.v1.LPA.ListProfiles:output_type -> euicc.lpa.v1.ListProfilesResponse
+	38, // 47: euicc.lpa.v1.LPA.EnableProfile:output_type -> google.protobuf.Empty
+	38, // 48: euicc.lpa.v1.LPA.DisableProfile:output_type -> google.protobuf.Empty
+	38, // 49: euicc.lpa.v1.LPA.DeleteProfile:output_type -> google.protobuf.Empty
+	38, // 50: euicc.lpa.v1.LPA.SetNickname:output_type -> google.protobuf.Empty
+	38, // 51: euicc.lpa.v1.LPA.MemoryReset:output_type -> google.protobuf.Empty
+	22, // 52: euicc.lpa.v1.LPA.ListNotifications:output_type -> euicc.lpa.v1.ListNotificationsResponse
+	38, // 53: euicc.lpa.v1.LPA.RemoveNotification:output_type -> google.protobuf.Empty
+	25, // 54: euicc.lpa.v1.LPA.ProcessNotifications:output_type -> euicc.lpa.v1.ProcessNotificationsResponse
+	28, // 55: euicc.lpa.v1.LPA.DiscoverProfiles:output_type -> euicc.lpa.v1.DiscoverProfilesResponse
+	32, // 56: euicc.lpa.v1.LPA.DownloadProfile:output_type -> euicc.lpa.v1.DownloadProfileResponse
+	42, // [42:57] is the sub-list for method output_type
+	27, // [27:42] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
+}
+
+func init() { file_lpa_proto_init() }
+func file_lpa_proto_init() {
+	if File_lpa_proto != nil {
+		return
+	}
+	file_lpa_proto_msgTypes[12].OneofWrappers = []any{
+		(*ListProfilesRequest_Iccid)(nil),
+		(*ListProfilesRequest_IsdpAid)(nil),
+		(*ListProfilesRequest_ProfileClass)(nil),
+	}
+	file_lpa_proto_msgTypes[14].OneofWrappers = []any{
+		(*ProfileOperationRequest_Iccid)(nil),
+		(*ProfileOperationRequest_IsdpAid)(nil),
+	}
+	file_lpa_proto_msgTypes[26].OneofWrappers = []any{
+		(*DownloadProfileRequest_Start)(nil),
+		(*DownloadProfileRequest_Confirm)(nil),
+		(*DownloadProfileRequest_ConfirmationCode)(nil),
+	}
+	file_lpa_proto_msgTypes[28].OneofWrappers = []any{
+		(*DownloadProfileResponse_Stage)(nil),
+		(*DownloadProfileResponse_Confirm)(nil),
+		(*DownloadProfileResponse_EnterConfirmationCode)(nil),
+		(*DownloadProfileResponse_Result)(nil),
+	}
+	file_lpa_proto_msgTypes[30].OneofWrappers = []any{
+		(*Error_ResultError)(nil),
+		(*Error_RspError)(nil),
+		(*Error_LoadBoundProfilePackageError)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lpa_proto_rawDesc), len(file_lpa_proto_rawDesc)),
+			NumEnums:      4,
+			NumMessages:   34,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lpa_proto_goTypes,
+		DependencyIndexes: file_lpa_proto_depIdxs,
+		EnumInfos:         file_lpa_proto_enumTypes,
+		MessageInfos:      file_lpa_proto_msgTypes,
+	}.Build()
+	File_lpa_proto = out.File
+	file_lpa_proto_goTypes = nil
+	file_lpa_proto_depIdxs = nil
+}
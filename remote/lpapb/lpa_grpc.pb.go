@@ -0,0 +1,673 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: lpa.proto
+
+// Package euicc.lpa.v1 is the remote API of an LPA, it mirrors the operations of lpa.Client.
+
+package lpapb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LPA_GetEID_FullMethodName                 = "/euicc.lpa.v1.LPA/GetEID"
+	LPA_GetChipInfo_FullMethodName            = "/euicc.lpa.v1.LPA/GetChipInfo"
+	LPA_GetConfiguredAddresses_FullMethodName = "/euicc.lpa.v1.LPA/GetConfiguredAddresses"
+	LPA_SetDefaultDPAddress_FullMethodName    = "/euicc.lpa.v1.LPA/SetDefaultDPAddress"
+	LPA_ListProfiles_FullMethodName           = "/euicc.lpa.v1.LPA/ListProfiles"
+	LPA_EnableProfile_FullMethodName          = "/euicc.lpa.v1.LPA/EnableProfile"
+	LPA_DisableProfile_FullMethodName         = "/euicc.lpa.v1.LPA/DisableProfile"
+	LPA_DeleteProfile_FullMethodName          = "/euicc.lpa.v1.LPA/DeleteProfile"
+	LPA_SetNickname_FullMethodName            = "/euicc.lpa.v1.LPA/SetNickname"
+	LPA_MemoryReset_FullMethodName            = "/euicc.lpa.v1.LPA/MemoryReset"
+	LPA_ListNotifications_FullMethodName      = "/euicc.lpa.v1.LPA/ListNotifications"
+	LPA_RemoveNotification_FullMethodName     = "/euicc.lpa.v1.LPA/RemoveNotification"
+	LPA_ProcessNotifications_FullMethodName   = "/euicc.lpa.v1.LPA/ProcessNotifications"
+	LPA_DiscoverProfiles_FullMethodName       = "/euicc.lpa.v1.LPA/DiscoverProfiles"
+	LPA_DownloadProfile_FullMethodName        = "/euicc.lpa.v1.LPA/DownloadProfile"
+)
+
+// LPAClient is the client API for LPA service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LPA manages the profiles of an eUICC.
+//
+// The failed calls return a status whose details hold an Error when the eUICC, the SM-DP+ or the SM-DS
+// rejected the operation.
+type LPAClient interface {
+	GetEID(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*EID, error)
+	GetChipInfo(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ChipInfo, error)
+	GetConfiguredAddresses(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ConfiguredAddresses, error)
+	SetDefaultDPAddress(ctx context.Context, in *SetDefaultDPAddressRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListProfiles(ctx context.Context, in *ListProfilesRequest, opts ...grpc.CallOption) (*ListProfilesResponse, error)
+	EnableProfile(ctx context.Context, in *ProfileOperationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DisableProfile(ctx context.Context, in *ProfileOperationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteProfile(ctx context.Context, in *ProfileOperationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SetNickname(ctx context.Context, in *SetNicknameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	MemoryReset(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListNotifications(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (*ListNotificationsResponse, error)
+	RemoveNotification(ctx context.Context, in *RemoveNotificationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ProcessNotifications(ctx context.Context, in *ProcessNotificationsRequest, opts ...grpc.CallOption) (*ProcessNotificationsResponse, error)
+	DiscoverProfiles(ctx context.Context, in *DiscoverProfilesRequest, opts ...grpc.CallOption) (*DiscoverProfilesResponse, error)
+	// DownloadProfile downloads a profile.
+	//
+	// The client sends DownloadProfileRequest.start first. The server then streams the stages of the download,
+	// and asks the client to confirm the profile or to enter the confirmation code when the start asked for it,
+	// which the client answers with DownloadProfileRequest.confirm or DownloadProfileRequest.confirmation_code.
+	// The stream ends with the result, followed by the error status when the installation failed.
+	DownloadProfile(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[DownloadProfileRequest, DownloadProfileResponse], error)
+}
+
+type lPAClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLPAClient(cc grpc.ClientConnInterface) LPAClient {
+	return &lPAClient{cc}
+}
+
+func (c *lPAClient) GetEID(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*EID, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EID)
+	err := c.cc.Invoke(ctx, LPA_GetEID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) GetChipInfo(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ChipInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChipInfo)
+	err := c.cc.Invoke(ctx, LPA_GetChipInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) GetConfiguredAddresses(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ConfiguredAddresses, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfiguredAddresses)
+	err := c.cc.Invoke(ctx, LPA_GetConfiguredAddresses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) SetDefaultDPAddress(ctx context.Context, in *SetDefaultDPAddressRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LPA_SetDefaultDPAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) ListProfiles(ctx context.Context, in *ListProfilesRequest, opts ...grpc.CallOption) (*ListProfilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProfilesResponse)
+	err := c.cc.Invoke(ctx, LPA_ListProfiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) EnableProfile(ctx context.Context, in *ProfileOperationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LPA_EnableProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) DisableProfile(ctx context.Context, in *ProfileOperationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LPA_DisableProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) DeleteProfile(ctx context.Context, in *ProfileOperationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LPA_DeleteProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) SetNickname(ctx context.Context, in *SetNicknameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LPA_SetNickname_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) MemoryReset(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LPA_MemoryReset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) ListNotifications(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (*ListNotificationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNotificationsResponse)
+	err := c.cc.Invoke(ctx, LPA_ListNotifications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) RemoveNotification(ctx context.Context, in *RemoveNotificationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LPA_RemoveNotification_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) ProcessNotifications(ctx context.Context, in *ProcessNotificationsRequest, opts ...grpc.CallOption) (*ProcessNotificationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProcessNotificationsResponse)
+	err := c.cc.Invoke(ctx, LPA_ProcessNotifications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) DiscoverProfiles(ctx context.Context, in *DiscoverProfilesRequest, opts ...grpc.CallOption) (*DiscoverProfilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiscoverProfilesResponse)
+	err := c.cc.Invoke(ctx, LPA_DiscoverProfiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lPAClient) DownloadProfile(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[DownloadProfileRequest, DownloadProfileResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LPA_ServiceDesc.Streams[0], LPA_DownloadProfile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadProfileRequest, DownloadProfileResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LPA_DownloadProfileClient = grpc.BidiStreamingClient[DownloadProfileRequest, DownloadProfileResponse]
+
+// LPAServer is the server API for LPA service.
+// All implementations must embed UnimplementedLPAServer
+// for forward compatibility.
+//
+// LPA manages the profiles of an eUICC.
+//
+// The failed calls return a status whose details hold an Error when the eUICC, the SM-DP+ or the SM-DS
+// rejected the operation.
+type LPAServer interface {
+	GetEID(context.Context, *emptypb.Empty) (*EID, error)
+	GetChipInfo(context.Context, *emptypb.Empty) (*ChipInfo, error)
+	GetConfiguredAddresses(context.Context, *emptypb.Empty) (*ConfiguredAddresses, error)
+	SetDefaultDPAddress(context.Context, *SetDefaultDPAddressRequest) (*emptypb.Empty, error)
+	ListProfiles(context.Context, *ListProfilesRequest) (*ListProfilesResponse, error)
+	EnableProfile(context.Context, *ProfileOperationRequest) (*emptypb.Empty, error)
+	DisableProfile(context.Context, *ProfileOperationRequest) (*emptypb.Empty, error)
+	DeleteProfile(context.Context, *ProfileOperationRequest) (*emptypb.Empty, error)
+	SetNickname(context.Context, *SetNicknameRequest) (*emptypb.Empty, error)
+	MemoryReset(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	ListNotifications(context.Context, *ListNotificationsRequest) (*ListNotificationsResponse, error)
+	RemoveNotification(context.Context, *RemoveNotificationRequest) (*emptypb.Empty, error)
+	ProcessNotifications(context.Context, *ProcessNotificationsRequest) (*ProcessNotificationsResponse, error)
+	DiscoverProfiles(context.Context, *DiscoverProfilesRequest) (*DiscoverProfilesResponse, error)
+	// DownloadProfile downloads a profile.
+	//
+	// The client sends DownloadProfileRequest.start first. The server then streams the stages of the download,
+	// and asks the client to confirm the profile or to enter the confirmation code when the start asked for it,
+	// which the client answers with DownloadProfileRequest.confirm or DownloadProfileRequest.confirmation_code.
+	// The stream ends with the result, followed by the error status when the installation failed.
+	DownloadProfile(grpc.BidiStreamingServer[DownloadProfileRequest, DownloadProfileResponse]) error
+	mustEmbedUnimplementedLPAServer()
+}
+
+// UnimplementedLPAServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLPAServer struct{}
+
+func (UnimplementedLPAServer) GetEID(context.Context, *emptypb.Empty) (*EID, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetEID not implemented")
+}
+func (UnimplementedLPAServer) GetChipInfo(context.Context, *emptypb.Empty) (*ChipInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetChipInfo not implemented")
+}
+func (UnimplementedLPAServer) GetConfiguredAddresses(context.Context, *emptypb.Empty) (*ConfiguredAddresses, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetConfiguredAddresses not implemented")
+}
+func (UnimplementedLPAServer) SetDefaultDPAddress(context.Context, *SetDefaultDPAddressRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetDefaultDPAddress not implemented")
+}
+func (UnimplementedLPAServer) ListProfiles(context.Context, *ListProfilesRequest) (*ListProfilesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListProfiles not implemented")
+}
+func (UnimplementedLPAServer) EnableProfile(context.Context, *ProfileOperationRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method EnableProfile not implemented")
+}
+func (UnimplementedLPAServer) DisableProfile(context.Context, *ProfileOperationRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method DisableProfile not implemented")
+}
+func (UnimplementedLPAServer) DeleteProfile(context.Context, *ProfileOperationRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteProfile not implemented")
+}
+func (UnimplementedLPAServer) SetNickname(context.Context, *SetNicknameRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetNickname not implemented")
+}
+func (UnimplementedLPAServer) MemoryReset(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method MemoryReset not implemented")
+}
+func (UnimplementedLPAServer) ListNotifications(context.Context, *ListNotificationsRequest) (*ListNotificationsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListNotifications not implemented")
+}
+func (UnimplementedLPAServer) RemoveNotification(context.Context, *RemoveNotificationRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveNotification not implemented")
+}
+func (UnimplementedLPAServer) ProcessNotifications(context.Context, *ProcessNotificationsRequest) (*ProcessNotificationsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ProcessNotifications not implemented")
+}
+func (UnimplementedLPAServer) DiscoverProfiles(context.Context, *DiscoverProfilesRequest) (*DiscoverProfilesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DiscoverProfiles not implemented")
+}
+func (UnimplementedLPAServer) DownloadProfile(grpc.BidiStreamingServer[DownloadProfileRequest, DownloadProfileResponse]) error {
+	return status.Error(codes.Unimplemented, "method DownloadProfile not implemented")
+}
+func (UnimplementedLPAServer) mustEmbedUnimplementedLPAServer() {}
+func (UnimplementedLPAServer) testEmbeddedByValue()             {}
+
+// UnsafeLPAServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LPAServer will
+// result in compilation errors.
+type UnsafeLPAServer interface {
+	mustEmbedUnimplementedLPAServer()
+}
+
+func RegisterLPAServer(s grpc.ServiceRegistrar, srv LPAServer) {
+	// If the following call panics, it indicates UnimplementedLPAServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LPA_ServiceDesc, srv)
+}
+
+func _LPA_GetEID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).GetEID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_GetEID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).GetEID(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_GetChipInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).GetChipInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_GetChipInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).GetChipInfo(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_GetConfiguredAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).GetConfiguredAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_GetConfiguredAddresses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).GetConfiguredAddresses(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_SetDefaultDPAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDefaultDPAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).SetDefaultDPAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_SetDefaultDPAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).SetDefaultDPAddress(ctx, req.(*SetDefaultDPAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_ListProfiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProfilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).ListProfiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_ListProfiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).ListProfiles(ctx, req.(*ListProfilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_EnableProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProfileOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).EnableProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_EnableProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).EnableProfile(ctx, req.(*ProfileOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_DisableProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProfileOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).DisableProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_DisableProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).DisableProfile(ctx, req.(*ProfileOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_DeleteProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProfileOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).DeleteProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_DeleteProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).DeleteProfile(ctx, req.(*ProfileOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_SetNickname_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNicknameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).SetNickname(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_SetNickname_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).SetNickname(ctx, req.(*SetNicknameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_MemoryReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).MemoryReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_MemoryReset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).MemoryReset(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_ListNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).ListNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_ListNotifications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).ListNotifications(ctx, req.(*ListNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_RemoveNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveNotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).RemoveNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_RemoveNotification_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).RemoveNotification(ctx, req.(*RemoveNotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_ProcessNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProcessNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).ProcessNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_ProcessNotifications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).ProcessNotifications(ctx, req.(*ProcessNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_DiscoverProfiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoverProfilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LPAServer).DiscoverProfiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LPA_DiscoverProfiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LPAServer).DiscoverProfiles(ctx, req.(*DiscoverProfilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LPA_DownloadProfile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LPAServer).DownloadProfile(&grpc.GenericServerStream[DownloadProfileRequest, DownloadProfileResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LPA_DownloadProfileServer = grpc.BidiStreamingServer[DownloadProfileRequest, DownloadProfileResponse]
+
+// LPA_ServiceDesc is the grpc.ServiceDesc for LPA service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LPA_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "euicc.lpa.v1.LPA",
+	HandlerType: (*LPAServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetEID",
+			Handler:    _LPA_GetEID_Handler,
+		},
+		{
+			MethodName: "GetChipInfo",
+			Handler:    _LPA_GetChipInfo_Handler,
+		},
+		{
+			MethodName: "GetConfiguredAddresses",
+			Handler:    _LPA_GetConfiguredAddresses_Handler,
+		},
+		{
+			MethodName: "SetDefaultDPAddress",
+			Handler:    _LPA_SetDefaultDPAddress_Handler,
+		},
+		{
+			MethodName: "ListProfiles",
+			Handler:    _LPA_ListProfiles_Handler,
+		},
+		{
+			MethodName: "EnableProfile",
+			Handler:    _LPA_EnableProfile_Handler,
+		},
+		{
+			MethodName: "DisableProfile",
+			Handler:    _LPA_DisableProfile_Handler,
+		},
+		{
+			MethodName: "DeleteProfile",
+			Handler:    _LPA_DeleteProfile_Handler,
+		},
+		{
+			MethodName: "SetNickname",
+			Handler:    _LPA_SetNickname_Handler,
+		},
+		{
+			MethodName: "MemoryReset",
+			Handler:    _LPA_MemoryReset_Handler,
+		},
+		{
+			MethodName: "ListNotifications",
+			Handler:    _LPA_ListNotifications_Handler,
+		},
+		{
+			MethodName: "RemoveNotification",
+			Handler:    _LPA_RemoveNotification_Handler,
+		},
+		{
+			MethodName: "ProcessNotifications",
+			Handler:    _LPA_ProcessNotifications_Handler,
+		},
+		{
+			MethodName: "DiscoverProfiles",
+			Handler:    _LPA_DiscoverProfiles_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DownloadProfile",
+			Handler:       _LPA_DownloadProfile_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "lpa.proto",
+}
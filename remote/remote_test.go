@@ -0,0 +1,187 @@
+package remote_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/KilimcininKorOglu/euicc-go/driver/virtual"
+	"github.com/KilimcininKorOglu/euicc-go/http/smdptest"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/KilimcininKorOglu/euicc-go/remote"
+	"github.com/KilimcininKorOglu/euicc-go/remote/lpapb"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const imei = "356938035643809"
+
+func mustICCID(t *testing.T, value string) sgp22.ICCID {
+	iccid, err := sgp22.NewICCID(value)
+	require.NoError(t, err)
+	return iccid
+}
+
+// setup returns a client of a local eUICC, and a remote client of the same eUICC.
+func setup(t *testing.T) (*smdptest.Server, *virtual.EUICC, *lpa.Client, *remote.Client) {
+	smdp := smdptest.NewServer()
+	t.Cleanup(smdp.Close)
+	smdp.Orders["QR-G-5C-1LS-1W1Z9P7"] = &smdptest.Order{
+		Profile: &sgp22.ProfileInfo{
+			ICCID:               mustICCID(t, "8944476500001224166"),
+			ServiceProviderName: "Operator B",
+			ProfileName:         "Profile B",
+			ProfileClass:        sgp22.ProfileClassOperational,
+		},
+		ConfirmationCode: "1234",
+	}
+
+	card := virtual.New()
+	card.DefaultSMDPAddress = "smdp.example.com"
+	card.AddProfile(&virtual.Profile{
+		ICCID:               mustICCID(t, "8944476500001224158"),
+		State:               sgp22.ProfileEnabled,
+		ServiceProviderName: "Operator A",
+		ProfileName:         "Profile A",
+		Class:               sgp22.ProfileClassOperational,
+		NotificationConfigurationInfo: sgp22.NotificationConfigurationInfo{
+			{ProfileManagementOperation: sgp22.NotificationEventDisable, Address: smdp.SMDP().Host},
+		},
+	})
+	local, err := lpa.New(&lpa.Options{Channel: card})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = local.Close() })
+	local.HTTP.Client = smdp.Client()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	lpapb.RegisterLPAServer(server, remote.NewServer(local))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	client, err := remote.Dial("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return smdp, card, local, client
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	_, card, local, client := setup(t)
+
+	eid, err := client.EID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, card.EID, eid)
+	expectedInfo, err := local.ChipInfo(ctx)
+	require.NoError(t, err)
+	info, err := client.ChipInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, expectedInfo, info)
+	require.NoError(t, client.SetDefaultDPAddress(ctx, "smdp.io"))
+	addresses, err := client.EUICCConfiguredAddresses(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "smdp.io", addresses.DefaultSMDPAddress)
+
+	expected, err := local.ListProfile(ctx, nil, nil)
+	require.NoError(t, err)
+	profiles, err := client.ListProfile(ctx, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, expected, profiles)
+	profiles, err = client.ListProfile(ctx, sgp22.ProfileClassTest, nil)
+	require.NoError(t, err)
+	assert.Empty(t, profiles)
+
+	iccid := mustICCID(t, "8944476500001224158")
+	err = client.DeleteProfile(ctx, iccid)
+	assert.ErrorIs(t, err, sgp22.ErrProfileNotInDisabledState)
+	var resultErr *sgp22.ResultError
+	require.ErrorAs(t, err, &resultErr)
+	assert.Equal(t, "ES10c.DeleteProfile", resultErr.Function)
+	require.NoError(t, client.SetNickname(ctx, iccid, "Work"))
+	require.NoError(t, client.DisableProfile(ctx, card.Profiles[0].ISDPAID, false))
+	profiles, err = client.ListProfile(ctx, iccid, nil)
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, sgp22.ProfileDisabled, profiles[0].ProfileState)
+	assert.Equal(t, "Work", profiles[0].ProfileNickname)
+	assert.EqualError(t, client.EnableProfile(ctx, "8944476500001224158", false), "invalid profile identifier")
+
+	notifications, err := client.ListNotification(ctx, sgp22.NotificationEventDisable)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, sgp22.NotificationEventDisable, notifications[0].ProfileManagementOperation)
+	assert.Equal(t, iccid, notifications[0].ICCID)
+	require.NoError(t, client.RemoveNotificationFromList(ctx, notifications[0].SequenceNumber))
+	notifications, err = client.ListNotification(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, notifications)
+}
+
+func TestClient_DownloadProfile(t *testing.T) {
+	ctx := context.Background()
+	smdp, card, _, client := setup(t)
+
+	var stages []lpa.DownloadStage
+	result, err := client.DownloadProfile(ctx, &lpa.ActivationCode{SMDP: smdp.SMDP(), MatchingID: "QR-G-5C-1LS-1W1Z9P7", IMEI: imei}, &lpa.DownloadOptions{
+		OnProgress: func(stage lpa.DownloadStage) { stages = append(stages, stage) },
+		OnConfirm: func(profile *sgp22.ProfileInfo) bool {
+			assert.Equal(t, "Profile B", profile.ProfileName)
+			return true
+		},
+		OnEnterConfirmationCode: func() string { return "1234" },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []lpa.DownloadStage{
+		lpa.DownloadStageAuthenticateClient,
+		lpa.DownloadStageAuthenticateServer,
+		lpa.DownloadStageInstall,
+	}, stages)
+	require.Len(t, card.Profiles, 2)
+	assert.Equal(t, card.Profiles[1].ISDPAID, result.ISDPAID())
+	assert.Equal(t, card.SMDPOID, result.SMDPOID)
+	assert.NotEmpty(t, result.Signature)
+
+	results, err := client.ProcessAllNotifications(ctx, &lpa.ProcessNotificationsOptions{AutoRemove: true})
+	require.NoError(t, err)
+	assert.Equal(t, []*lpa.NotificationProcessResult{
+		{SequenceNumber: result.Notification.SequenceNumber, Success: true, Removed: true},
+	}, results)
+	assert.Len(t, smdp.Notifications, 1)
+}
+
+func TestClient_DownloadProfileRejected(t *testing.T) {
+	ctx := context.Background()
+	smdp, card, _, client := setup(t)
+	ac := &lpa.ActivationCode{SMDP: smdp.SMDP(), MatchingID: "QR-G-5C-1LS-1W1Z9P7", IMEI: imei}
+
+	result, err := client.DownloadProfile(ctx, ac, &lpa.DownloadOptions{
+		OnConfirm: func(*sgp22.ProfileInfo) bool { return false },
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	_, err = client.DownloadProfile(ctx, ac, &lpa.DownloadOptions{
+		OnEnterConfirmationCode: func() string { return "0000" },
+	})
+	var rspErr *sgp22.RSPError
+	require.ErrorAs(t, err, &rspErr)
+	assert.True(t, rspErr.IsConfirmationCodeRefused())
+
+	ac.MatchingID = "UNKNOWN"
+	_, err = client.DownloadProfile(ctx, ac, nil)
+	require.ErrorAs(t, err, &rspErr)
+	assert.True(t, rspErr.IsMatchingIDRefused())
+	assert.Len(t, card.Profiles, 1)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = client.DownloadProfile(canceled, ac, nil)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,280 @@
+// Package remote serves an LPA over gRPC, so that the eUICC of a device is managed from another host.
+//
+// The API is the LPA service of lpapb, which mirrors the operations of lpa.Client.
+// Server serves an lpa.Interface, and Client is an lpa.Interface calling a Server:
+//
+//	server := grpc.NewServer()
+//	lpapb.RegisterLPAServer(server, remote.NewServer(client))
+//	server.Serve(listener)
+//
+//	client, err := remote.Dial("device:50051", grpc.WithTransportCredentials(creds))
+//	profiles, err := client.ListProfile(ctx, nil, nil)
+package remote
+
+import (
+	"context"
+
+	"github.com/KilimcininKorOglu/euicc-go/bertlv"
+	"github.com/KilimcininKorOglu/euicc-go/lpa"
+	"github.com/KilimcininKorOglu/euicc-go/remote/lpapb"
+	sgp22 "github.com/KilimcininKorOglu/euicc-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Server serves an lpa.Interface, register it with lpapb.RegisterLPAServer.
+type Server struct {
+	lpapb.UnimplementedLPAServer
+	client lpa.Interface
+}
+
+// NewServer returns a server calling the client, the client is not closed by the server.
+func NewServer(client lpa.Interface) *Server {
+	return &Server{client: client}
+}
+
+func (s *Server) GetEID(ctx context.Context, _ *emptypb.Empty) (*lpapb.EID, error) {
+	eid, err := s.client.EID(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &lpapb.EID{Eid: eid}, nil
+}
+
+func (s *Server) GetChipInfo(ctx context.Context, _ *emptypb.Empty) (*lpapb.ChipInfo, error) {
+	info, err := s.client.ChipInfo(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toChipInfo(info), nil
+}
+
+func (s *Server) GetConfiguredAddresses(ctx context.Context, _ *emptypb.Empty) (*lpapb.ConfiguredAddresses, error) {
+	addresses, err := s.client.EUICCConfiguredAddresses(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toConfiguredAddresses(addresses), nil
+}
+
+func (s *Server) SetDefaultDPAddress(ctx context.Context, request *lpapb.SetDefaultDPAddressRequest) (*emptypb.Empty, error) {
+	return new(emptypb.Empty), toStatus(s.client.SetDefaultDPAddress(ctx, request.GetAddress()))
+}
+
+func (s *Server) ListProfiles(ctx context.Context, request *lpapb.ListProfilesRequest) (*lpapb.ListProfilesResponse, error) {
+	var searchCriteria any
+	switch v := request.GetSearchCriteria().(type) {
+	case *lpapb.ListProfilesRequest_Iccid:
+		iccid, err := sgp22.NewICCID(v.Iccid)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ICCID: %v", err)
+		}
+		searchCriteria = iccid
+	case *lpapb.ListProfilesRequest_IsdpAid:
+		searchCriteria = sgp22.ISDPAID(v.IsdpAid)
+	case *lpapb.ListProfilesRequest_ProfileClass:
+		searchCriteria = sgp22.ProfileClass(v.ProfileClass)
+	}
+	tags := make([]bertlv.Tag, len(request.GetTags()))
+	for i, tag := range request.GetTags() {
+		tags[i] = tag
+	}
+	profiles, err := s.client.ListProfile(ctx, searchCriteria, tags)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	response := &lpapb.ListProfilesResponse{Profiles: make([]*lpapb.Profile, len(profiles))}
+	for i, profile := range profiles {
+		response.Profiles[i] = toProfile(profile)
+	}
+	return response, nil
+}
+
+func (s *Server) EnableProfile(ctx context.Context, request *lpapb.ProfileOperationRequest) (*emptypb.Empty, error) {
+	identifier, err := profileIdentifier(request)
+	if err != nil {
+		return nil, err
+	}
+	return new(emptypb.Empty), toStatus(s.client.EnableProfile(ctx, identifier, request.GetRefresh()))
+}
+
+func (s *Server) DisableProfile(ctx context.Context, request *lpapb.ProfileOperationRequest) (*emptypb.Empty, error) {
+	identifier, err := profileIdentifier(request)
+	if err != nil {
+		return nil, err
+	}
+	return new(emptypb.Empty), toStatus(s.client.DisableProfile(ctx, identifier, request.GetRefresh()))
+}
+
+func (s *Server) DeleteProfile(ctx context.Context, request *lpapb.ProfileOperationRequest) (*emptypb.Empty, error) {
+	identifier, err := profileIdentifier(request)
+	if err != nil {
+		return nil, err
+	}
+	return new(emptypb.Empty), toStatus(s.client.DeleteProfile(ctx, identifier))
+}
+
+func (s *Server) SetNickname(ctx context.Context, request *lpapb.SetNicknameRequest) (*emptypb.Empty, error) {
+	iccid, err := sgp22.NewICCID(request.GetIccid())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid ICCID: %v", err)
+	}
+	return new(emptypb.Empty), toStatus(s.client.SetNickname(ctx, iccid, request.GetNickname()))
+}
+
+func (s *Server) MemoryReset(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	return new(emptypb.Empty), toStatus(s.client.MemoryReset(ctx))
+}
+
+func (s *Server) ListNotifications(ctx context.Context, request *lpapb.ListNotificationsRequest) (*lpapb.ListNotificationsResponse, error) {
+	filters := make([]sgp22.NotificationEvent, len(request.GetFilters()))
+	for i, filter := range request.GetFilters() {
+		filters[i] = sgp22.NotificationEvent(filter)
+	}
+	notifications, err := s.client.ListNotification(ctx, filters...)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	response := &lpapb.ListNotificationsResponse{Notifications: make([]*lpapb.NotificationMetadata, len(notifications))}
+	for i, notification := range notifications {
+		response.Notifications[i] = toNotification(notification)
+	}
+	return response, nil
+}
+
+func (s *Server) RemoveNotification(ctx context.Context, request *lpapb.RemoveNotificationRequest) (*emptypb.Empty, error) {
+	return new(emptypb.Empty), toStatus(s.client.RemoveNotificationFromList(ctx, sgp22.SequenceNumber(request.GetSequenceNumber())))
+}
+
+func (s *Server) ProcessNotifications(ctx context.Context, request *lpapb.ProcessNotificationsRequest) (*lpapb.ProcessNotificationsResponse, error) {
+	opts := &lpa.ProcessNotificationsOptions{
+		AutoRemove:      request.GetAutoRemove(),
+		ContinueOnError: request.GetContinueOnError(),
+	}
+	var results []*lpa.NotificationProcessResult
+	var err error
+	if request.GetAll() {
+		results, err = s.client.ProcessAllNotifications(ctx, opts)
+	} else {
+		sequenceNumbers := make([]sgp22.SequenceNumber, len(request.GetSequenceNumbers()))
+		for i, sequenceNumber := range request.GetSequenceNumbers() {
+			sequenceNumbers[i] = sgp22.SequenceNumber(sequenceNumber)
+		}
+		results, err = s.client.ProcessNotifications(ctx, opts, sequenceNumbers...)
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	response := &lpapb.ProcessNotificationsResponse{Results: make([]*lpapb.NotificationProcessResult, len(results))}
+	for i, result := range results {
+		response.Results[i] = &lpapb.NotificationProcessResult{
+			SequenceNumber: int64(result.SequenceNumber),
+			Success:        result.Success,
+			Removed:        result.Removed,
+		}
+		if result.Error != nil {
+			response.Results[i].Error = toError(result.Error)
+		}
+	}
+	return response, nil
+}
+
+func (s *Server) DiscoverProfiles(ctx context.Context, request *lpapb.DiscoverProfilesRequest) (*lpapb.DiscoverProfilesResponse, error) {
+	profiles, err := s.client.DiscoverProfiles(ctx, &lpa.DiscoverProfilesOptions{
+		SMDSAddress: request.GetSmdsAddress(),
+		IMEI:        request.GetImei(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	response := &lpapb.DiscoverProfilesResponse{Profiles: make([]*lpapb.DiscoveredProfile, len(profiles))}
+	for i, profile := range profiles {
+		response.Profiles[i] = &lpapb.DiscoveredProfile{EventId: profile.EventID, SmdpAddress: profile.SMDPAddress}
+	}
+	return response, nil
+}
+
+func (s *Server) DownloadProfile(stream lpapb.LPA_DownloadProfileServer) error {
+	request, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	start := request.GetStart()
+	if start == nil {
+		return status.Error(codes.InvalidArgument, "the download is not started")
+	}
+	var ac lpa.ActivationCode
+	if err := ac.UnmarshalText([]byte(start.GetActivationCode())); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid activation code: %v", err)
+	}
+	ac.IMEI, ac.ConfirmationCode = start.GetImei(), start.GetConfirmationCode()
+
+	// The callbacks are called by DownloadProfile on this goroutine, so they use the stream in turn.
+	// Once the client is gone, the callbacks refuse the download and the stream context cancels it.
+	var streamErr error
+	send := func(response *lpapb.DownloadProfileResponse) bool {
+		if streamErr == nil {
+			streamErr = stream.Send(response)
+		}
+		return streamErr == nil
+	}
+	receive := func() *lpapb.DownloadProfileRequest {
+		if streamErr != nil {
+			return nil
+		}
+		var request *lpapb.DownloadProfileRequest
+		request, streamErr = stream.Recv()
+		return request
+	}
+	opts := &lpa.DownloadOptions{
+		OnProgress: func(stage lpa.DownloadStage) {
+			send(&lpapb.DownloadProfileResponse{Response: &lpapb.DownloadProfileResponse_Stage{
+				Stage: lpapb.DownloadStage(stage),
+			}})
+		},
+	}
+	if start.GetConfirm() {
+		opts.OnConfirm = func(profile *sgp22.ProfileInfo) bool {
+			send(&lpapb.DownloadProfileResponse{Response: &lpapb.DownloadProfileResponse_Confirm{
+				Confirm: toProfile(profile),
+			}})
+			return receive().GetConfirm()
+		}
+	}
+	if start.GetEnterConfirmationCode() {
+		opts.OnEnterConfirmationCode = func() string {
+			send(&lpapb.DownloadProfileResponse{Response: &lpapb.DownloadProfileResponse_EnterConfirmationCode{
+				EnterConfirmationCode: new(emptypb.Empty),
+			}})
+			return receive().GetConfirmationCode()
+		}
+	}
+
+	result, err := s.client.DownloadProfile(stream.Context(), &ac, opts)
+	if result != nil || err == nil {
+		response := new(lpapb.DownloadResult)
+		if result != nil {
+			response.ProfileInstallationResult = result.ProfileInstallationResult.Bytes()
+		}
+		send(&lpapb.DownloadProfileResponse{Response: &lpapb.DownloadProfileResponse_Result{Result: response}})
+	}
+	if err != nil {
+		return toStatus(err)
+	}
+	return streamErr
+}
+
+func profileIdentifier(request *lpapb.ProfileOperationRequest) (any, error) {
+	switch v := request.GetIdentifier().(type) {
+	case *lpapb.ProfileOperationRequest_Iccid:
+		iccid, err := sgp22.NewICCID(v.Iccid)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ICCID: %v", err)
+		}
+		return iccid, nil
+	case *lpapb.ProfileOperationRequest_IsdpAid:
+		return sgp22.ISDPAID(v.IsdpAid), nil
+	}
+	return nil, status.Error(codes.InvalidArgument, "missing profile identifier")
+}
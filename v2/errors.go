@@ -3,16 +3,55 @@ package sgp22
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
 	ErrUnexpectedTag   = errors.New("unexpected tag")
 	ErrNothingToDelete = errors.New("nothing to delete")
-	ErrICCIDNotFound   = errors.New("iccid not found")
 	ErrCatBusy         = errors.New("cat busy")
 	ErrUndefined       = errors.New("undefined error")
+
+	ErrIncorrectInputValues      = errors.New("incorrect input values")
+	ErrICCIDOrAIDNotFound        = errors.New("iccid or aid not found")
+	ErrProfileNotInDisabledState = errors.New("profile not in disabled state")
+	ErrProfileNotInEnabledState  = errors.New("profile not in enabled state")
+	ErrDisallowedByPolicy        = errors.New("disallowed by policy")
+	ErrWrongProfileReenabling    = errors.New("wrong profile re-enabling")
+	ErrNoResultAvailable         = errors.New("no result available")
 )
 
+// ErrICCIDNotFound is returned when no profile has the ICCID.
+//
+// Deprecated: it is [ErrICCIDOrAIDNotFound], returned by every profile operation.
+var ErrICCIDNotFound = ErrICCIDOrAIDNotFound
+
+// ResultError is the result code other than ok returned by the eUICC for an ES10 function.
+//
+// errors.Is matches the error of the result code, such as [ErrProfileNotInDisabledState],
+// and errors.As gives access to the function and the raw result code.
+type ResultError struct {
+	// Function is the name of the ES10 function, such as "ES10c.EnableProfile".
+	Function string
+	// Result is the raw result code returned by the eUICC.
+	Result int8
+	// Err is the error of the result code, [ErrUndefined] for the codes not defined by SGP.22.
+	Err error
+}
+
+func (e *ResultError) Error() string { return e.Err.Error() }
+
+func (e *ResultError) Unwrap() error { return e.Err }
+
+// newResultError returns the [ResultError] of the result code, errs maps the codes defined by the function.
+func newResultError(function string, result int8, errs map[int8]error) error {
+	err, ok := errs[result]
+	if !ok {
+		err = ErrUndefined
+	}
+	return &ResultError{Function: function, Result: result, Err: err}
+}
+
 type LoadBoundProfilePackageError struct{ BPPCommandID, ErrorReason byte }
 
 func (e LoadBoundProfilePackageError) CommandID() string {
@@ -72,3 +111,90 @@ func (e LoadBoundProfilePackageError) String() string {
 func (e LoadBoundProfilePackageError) Error() string {
 	return fmt.Sprintf("%s,%s", e.CommandID(), e.String())
 }
+
+// RSPError is the error of an ES9+ or ES11 function the SM-DP+ or the SM-DS did not execute successfully.
+type RSPError struct {
+	// Function is the name of the function, such as "ES9+.AuthenticateClient".
+	Function string
+	// TransactionID identifies the RSP session, it is empty for the functions outside of a session.
+	TransactionID HexString
+	// Status is the function execution status: Failed, Expired or Executed-WithWarning.
+	Status      string
+	SubjectCode string
+	ReasonCode  string
+	Message     string
+}
+
+func newRSPError(function string, transactionID HexString, status *ExecutionStatus) *RSPError {
+	err := &RSPError{Function: function, TransactionID: transactionID}
+	if status == nil {
+		return err
+	}
+	err.Status = status.Status
+	if data := status.StatusCodeData; data != nil {
+		err.SubjectCode = data.SubjectCode
+		err.ReasonCode = data.ReasonCode
+		err.Message = data.Message
+	}
+	return err
+}
+
+func (e *RSPError) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("%s: missing function execution status", e.Function)
+	}
+	if e.SubjectCode == "" && e.ReasonCode == "" && e.Message == "" {
+		return fmt.Sprintf("%s: %s", e.Function, e.Status)
+	}
+	return e.StatusCodeData().Error()
+}
+
+// StatusCodeData returns the status code data of the error.
+func (e *RSPError) StatusCodeData() StatusCodeData {
+	return StatusCodeData{SubjectCode: e.SubjectCode, ReasonCode: e.ReasonCode, Message: e.Message}
+}
+
+// Is reports whether the target has the same subject and reason codes, and the same function when it is set,
+// so that errors.Is(err, &RSPError{SubjectCode: "8.8.5", ReasonCode: "4.10"}) matches an expired order.
+func (e *RSPError) Is(target error) bool {
+	t, ok := target.(*RSPError)
+	return ok && t.SubjectCode == e.SubjectCode && t.ReasonCode == e.ReasonCode &&
+		(t.Function == "" || t.Function == e.Function)
+}
+
+func (e *RSPError) is(status StatusCodeData) bool {
+	return e.SubjectCode == status.SubjectCode && e.ReasonCode == status.ReasonCode
+}
+
+// IsConfirmationCodeMissing reports whether the SM-DP+ requires a confirmation code the request did not provide.
+func (e *RSPError) IsConfirmationCodeMissing() bool { return e.is(statusConfirmationCodeMissing) }
+
+// IsConfirmationCodeRefused reports whether the confirmation code is wrong, the download may be retried with another code.
+func (e *RSPError) IsConfirmationCodeRefused() bool { return e.is(statusConfirmationCodeRefused) }
+
+// IsConfirmationCodeRetriesExceeded reports whether the confirmation code was refused too many times.
+func (e *RSPError) IsConfirmationCodeRetriesExceeded() bool { return e.is(statusConfirmationRetries) }
+
+// IsMatchingIDRefused reports whether the activation code or the event of the SM-DS is unknown to the SM-DP+.
+func (e *RSPError) IsMatchingIDRefused() bool { return e.is(statusMatchingIDRefused) }
+
+// IsOrderExpired reports whether the download order has expired.
+func (e *RSPError) IsOrderExpired() bool { return e.is(statusOrderExpired) }
+
+// IsOrderRetriesExceeded reports whether the download order was attempted too many times.
+func (e *RSPError) IsOrderRetriesExceeded() bool { return e.is(statusOrderRetriesExceeded) }
+
+// IsProfileNotReleased reports whether the profile has not yet been released by the operator.
+func (e *RSPError) IsProfileNotReleased() bool { return e.is(statusProfileNotReleased) }
+
+// IsInsufficientMemory reports whether the eUICC does not have enough space for the profile.
+func (e *RSPError) IsInsufficientMemory() bool { return e.is(statusInsufficientMemory) }
+
+// IsTransactionIDUnknown reports whether the SM-DP+ does not know the RSP session anymore.
+func (e *RSPError) IsTransactionIDUnknown() bool { return e.is(statusTransactionIDUnknown) }
+
+// Transient reports whether the failure may not happen again when the function is retried:
+// the function expired or the reason code is a transport error (5.x).
+func (e *RSPError) Transient() bool {
+	return e.Status == "Expired" || strings.HasPrefix(e.ReasonCode, "5.")
+}
@@ -0,0 +1,54 @@
+package sgp22
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileOperationResponse_Valid(t *testing.T) {
+	tests := []struct {
+		Operation ProfileOperation
+		Result    int8
+		Err       error
+	}{
+		{EnableProfile, 0, nil},
+		{EnableProfile, 1, ErrICCIDOrAIDNotFound},
+		{EnableProfile, 2, ErrProfileNotInDisabledState},
+		{EnableProfile, 4, ErrWrongProfileReenabling},
+		{DisableProfile, 2, ErrProfileNotInEnabledState},
+		{DisableProfile, 3, ErrDisallowedByPolicy},
+		{DeleteProfile, 2, ErrProfileNotInDisabledState},
+		{DeleteProfile, 4, ErrUndefined},
+		{DeleteProfile, 127, ErrUndefined},
+	}
+	for _, test := range tests {
+		err := (&ProfileOperationResponse{Operation: test.Operation, Result: test.Result}).Valid()
+		if test.Err == nil {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.ErrorIs(t, err, test.Err, "%s %d", test.Operation, test.Result)
+		var resultErr *ResultError
+		require.True(t, errors.As(err, &resultErr))
+		assert.Equal(t, test.Operation.String(), resultErr.Function)
+		assert.Equal(t, test.Result, resultErr.Result)
+	}
+}
+
+func TestNotificationSentResponse_Valid(t *testing.T) {
+	err := (&NotificationSentResponse{DeleteNotificationStatus: 1}).Valid()
+	assert.ErrorIs(t, err, ErrNothingToDelete)
+	var resultErr *ResultError
+	require.ErrorAs(t, err, &resultErr)
+	assert.Equal(t, "ES10b.RemoveNotificationFromList", resultErr.Function)
+	assert.EqualError(t, err, "nothing to delete")
+}
+
+func TestSetNicknameResponse_Valid(t *testing.T) {
+	err := (&SetNicknameResponse{Result: 1}).Valid()
+	assert.ErrorIs(t, err, ErrICCIDOrAIDNotFound)
+	assert.ErrorIs(t, err, ErrICCIDNotFound)
+}
@@ -90,7 +90,7 @@ func (r *SetDefaultDPAddressResponse) Valid() error {
 	if r == nil || r.Result == 0 {
 		return nil
 	}
-	return ErrUndefined
+	return newResultError("ES10a.SetDefaultDpAddress", r.Result, nil)
 }
 
 // endregion
@@ -2,6 +2,7 @@ package sgp22
 
 import (
 	"crypto/sha256"
+	"encoding/asn1"
 	"errors"
 
 	"github.com/KilimcininKorOglu/euicc-go/bertlv"
@@ -96,39 +97,110 @@ func (r *LoadBoundProfilePackageRequest) CardResponse() *LoadBoundProfilePackage
 	return new(LoadBoundProfilePackageResponse)
 }
 
+// LoadBoundProfilePackageResponse is the ProfileInstallationResult signed by the eUICC at the end of the installation,
+// the same result is queued on the eUICC as the install notification.
+//
+// See https://aka.pw/sgp22/v2.5#page=35 (Section 2.5.6, ProfileInstallationResult)
 type LoadBoundProfilePackageResponse struct {
+	// ProfileInstallationResult is the whole ProfileInstallationResult TLV as returned by the eUICC.
+	ProfileInstallationResult *bertlv.TLV
+	ProfileInstallationResultData
+	// Signature is the euiccSignPIR signature of the ProfileInstallationResultData.
+	Signature []byte
+}
+
+// ProfileInstallationResultData is the signed part of the ProfileInstallationResult.
+type ProfileInstallationResultData struct {
 	TransactionID []byte
 	Notification  *NotificationMetadata
+	// SMDPOID is the OID of the SM-DP+ in dotted form, empty when the eUICC does not report it.
+	SMDPOID string
+	// FinalResult is the finalResult TLV, either SuccessResult or ErrorResult is set from it.
 	FinalResult   *bertlv.TLV
+	SuccessResult *ProfileInstallationSuccessResult
+	ErrorResult   *ProfileInstallationErrorResult
+}
+
+// ProfileInstallationSuccessResult is the finalResult of a successful installation.
+type ProfileInstallationSuccessResult struct {
+	AID          ISDPAID
+	SIMAResponse []byte
+}
+
+// ProfileInstallationErrorResult is the finalResult of a failed installation.
+type ProfileInstallationErrorResult struct {
+	BPPCommandID byte
+	ErrorReason  byte
+	SIMAResponse []byte
+}
+
+// Err returns the failure as a [LoadBoundProfilePackageError].
+func (r *ProfileInstallationErrorResult) Err() *LoadBoundProfilePackageError {
+	return &LoadBoundProfilePackageError{BPPCommandID: r.BPPCommandID, ErrorReason: r.ErrorReason}
 }
 
 func (r *LoadBoundProfilePackageResponse) UnmarshalBERTLV(tlv *bertlv.TLV) error {
 	if !tlv.Tag.If(bertlv.ContextSpecific, bertlv.Constructed, 55) {
 		return ErrUnexpectedTag
 	}
-	tlv = tlv.First(bertlv.ContextSpecific.Constructed(39))
-	r.TransactionID = tlv.First(bertlv.ContextSpecific.Primitive(0)).Value
-	r.FinalResult = tlv.First(bertlv.ContextSpecific.Constructed(2))
+	r.ProfileInstallationResult = tlv
+	if signature := tlv.First(bertlv.Application.Primitive(55)); signature != nil {
+		r.Signature = signature.Value
+	}
+	return r.ProfileInstallationResultData.UnmarshalBERTLV(tlv.First(bertlv.ContextSpecific.Constructed(39)))
+}
+
+func (r *ProfileInstallationResultData) UnmarshalBERTLV(tlv *bertlv.TLV) error {
+	if tlv == nil || !tlv.Tag.If(bertlv.ContextSpecific, bertlv.Constructed, 39) {
+		return ErrUnexpectedTag
+	}
+	if transactionID := tlv.First(bertlv.ContextSpecific.Primitive(0)); transactionID != nil {
+		r.TransactionID = transactionID.Value
+	}
+	if oid := tlv.First(bertlv.Universal.Primitive(6)); oid != nil {
+		var value asn1.ObjectIdentifier
+		if _, err := asn1.Unmarshal(oid.Bytes(), &value); err != nil {
+			return err
+		}
+		r.SMDPOID = value.String()
+	}
+	if r.FinalResult = tlv.First(bertlv.ContextSpecific.Constructed(2)); r.FinalResult == nil {
+		return errors.New("missing finalResult")
+	}
+	if successResult := r.FinalResult.First(bertlv.ContextSpecific.Constructed(0)); successResult != nil {
+		r.SuccessResult = &ProfileInstallationSuccessResult{
+			AID:          valueOf(successResult.First(bertlv.Application.Primitive(15))),
+			SIMAResponse: valueOf(successResult.First(bertlv.Universal.Primitive(4))),
+		}
+	}
+	if errorResult := r.FinalResult.First(bertlv.ContextSpecific.Constructed(1)); errorResult != nil {
+		bppCommandID := valueOf(errorResult.First(bertlv.ContextSpecific.Primitive(0)))
+		errorReason := valueOf(errorResult.First(bertlv.ContextSpecific.Primitive(1)))
+		if len(bppCommandID) == 0 || len(errorReason) == 0 {
+			return errors.New("invalid errorResult")
+		}
+		r.ErrorResult = &ProfileInstallationErrorResult{
+			BPPCommandID: bppCommandID[0],
+			ErrorReason:  errorReason[0],
+			SIMAResponse: valueOf(errorResult.First(bertlv.Universal.Primitive(4))),
+		}
+	}
 	r.Notification = new(NotificationMetadata)
 	return r.Notification.UnmarshalBERTLV(tlv.First(bertlv.ContextSpecific.Constructed(47)))
 }
 
 func (r *LoadBoundProfilePackageResponse) ISDPAID() ISDPAID {
-	if successResult := r.FinalResult.First(bertlv.ContextSpecific.Constructed(0)); successResult != nil {
-		return successResult.First(bertlv.Application.Primitive(15)).Value
+	if r.SuccessResult != nil {
+		return r.SuccessResult.AID
 	}
 	return nil
 }
 
 func (r *LoadBoundProfilePackageResponse) Valid() error {
-	result := r.FinalResult.First(bertlv.ContextSpecific.Constructed(1))
-	if result == nil {
+	if r.ErrorResult == nil {
 		return nil
 	}
-	return &LoadBoundProfilePackageError{
-		BPPCommandID: result.First(bertlv.ContextSpecific.Primitive(0)).Value[0],
-		ErrorReason:  result.First(bertlv.ContextSpecific.Primitive(1)).Value[0],
-	}
+	return r.ErrorResult.Err()
 }
 
 // endregion
@@ -292,12 +364,16 @@ func (r *RetrieveNotificationsListRequest) MarshalBERTLV() (*bertlv.TLV, error)
 
 type RetrieveNotificationsListResponse struct {
 	NotificationList []*PendingNotification
+	error            *bertlv.TLV
 }
 
 func (r *RetrieveNotificationsListResponse) UnmarshalBERTLV(tlv *bertlv.TLV) error {
 	if !tlv.Tag.If(bertlv.ContextSpecific, bertlv.Constructed, 43) {
 		return ErrUnexpectedTag
 	}
+	if r.error = tlv.First(bertlv.ContextSpecific.Primitive(1)); r.error != nil {
+		return nil
+	}
 	var notifications []*PendingNotification
 	for _, child := range tlv.Children {
 		notification := new(PendingNotification)
@@ -311,6 +387,11 @@ func (r *RetrieveNotificationsListResponse) UnmarshalBERTLV(tlv *bertlv.TLV) err
 }
 
 func (r *RetrieveNotificationsListResponse) Valid() error {
+	if r.error != nil && len(r.error.Value) > 0 {
+		return newResultError("ES10b.RetrieveNotificationsList", int8(r.error.Value[0]), map[int8]error{
+			1: ErrNoResultAvailable,
+		})
+	}
 	if len(r.NotificationList) > 0 {
 		return nil
 	}
@@ -353,13 +434,12 @@ func (r *NotificationSentResponse) UnmarshalBERTLV(tlv *bertlv.TLV) error {
 }
 
 func (r *NotificationSentResponse) Valid() error {
-	switch r.DeleteNotificationStatus {
-	case 0:
+	if r.DeleteNotificationStatus == 0 {
 		return nil
-	case 1:
-		return ErrNothingToDelete
 	}
-	return ErrUndefined
+	return newResultError("ES10b.RemoveNotificationFromList", r.DeleteNotificationStatus, map[int8]error{
+		1: ErrNothingToDelete,
+	})
 }
 
 // endregion
@@ -533,12 +613,19 @@ func (r *AuthenticateServerRequest) CardResponse() *ES9AuthenticateClientRequest
 	return &ES9AuthenticateClientRequest{TransactionID: r.TransactionID}
 }
 
+// defaultTAC is the TAC of the device info when the IMEI is not known, the TAC is mandatory and the IMEI optional.
+var defaultTAC = []byte{0x35, 0x29, 0x06, 0x11}
+
 func (r *AuthenticateServerRequest) MarshalBERTLV() (*bertlv.TLV, error) {
 	deviceInfo := bertlv.NewChildrenIter(bertlv.ContextSpecific.Constructed(1), func(yield func(*bertlv.TLV) bool) {
-		if !yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), r.IMEI[:4])) {
+		tac := defaultTAC
+		if len(r.IMEI) >= 4 {
+			tac = r.IMEI[:4]
+		}
+		if !yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(0), tac)) {
 			return
 		}
-		if !yield(bertlv.NewChildren(bertlv.ContextSpecific.Constructed(1))) {
+		if !yield(bertlv.NewChildren(bertlv.ContextSpecific.Constructed(1))) || len(r.IMEI) == 0 {
 			return
 		}
 		yield(bertlv.NewValue(bertlv.ContextSpecific.Primitive(2), r.IMEI))
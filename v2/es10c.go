@@ -2,6 +2,7 @@ package sgp22
 
 import (
 	"errors"
+	"fmt"
 	"slices"
 	"unicode/utf8"
 
@@ -78,11 +79,9 @@ func (r *ProfileInfoListResponse) Valid() error {
 	if r.error == nil {
 		return nil
 	}
-	switch r.error.Value[0] {
-	case 1:
-		return errors.New("incorrect input values")
-	}
-	return ErrUndefined
+	return newResultError("ES10c.GetProfilesInfo", int8(r.error.Value[0]), map[int8]error{
+		1: ErrIncorrectInputValues,
+	})
 }
 
 // endregion
@@ -109,6 +108,19 @@ const (
 	DeleteProfile
 )
 
+// String returns the name of the ES10 function of the operation.
+func (o ProfileOperation) String() string {
+	switch o {
+	case EnableProfile:
+		return "ES10c.EnableProfile"
+	case DisableProfile:
+		return "ES10c.DisableProfile"
+	case DeleteProfile:
+		return "ES10c.DeleteProfile"
+	}
+	return fmt.Sprintf("ProfileOperation(%d)", byte(o))
+}
+
 // ProfileOperationRequest is a request to enable, disable, or delete a profile.
 //
 // See https://aka.pw/sgp22/v2.5#page=201 (Section 5.7.16, ES10c.EnableProfile)
@@ -164,24 +176,35 @@ func (r *ProfileOperationResponse) UnmarshalBERTLV(tlv *bertlv.TLV) error {
 }
 
 func (r *ProfileOperationResponse) Valid() error {
-	switch r.Result {
-	case 0:
+	if r.Result == 0 {
 		return nil
-	case 1:
-		return errors.New("iccid or aid not found")
-	case 2:
-		if r.Operation == EnableProfile {
-			return errors.New("profile not in disabled state")
+	}
+	var errs map[int8]error
+	switch r.Operation {
+	case EnableProfile:
+		errs = map[int8]error{
+			1: ErrICCIDOrAIDNotFound,
+			2: ErrProfileNotInDisabledState,
+			3: ErrDisallowedByPolicy,
+			4: ErrWrongProfileReenabling,
+			5: ErrCatBusy,
+		}
+	case DisableProfile:
+		errs = map[int8]error{
+			1: ErrICCIDOrAIDNotFound,
+			2: ErrProfileNotInEnabledState,
+			3: ErrDisallowedByPolicy,
+			5: ErrCatBusy,
+		}
+	case DeleteProfile:
+		errs = map[int8]error{
+			1: ErrICCIDOrAIDNotFound,
+			2: ErrProfileNotInDisabledState,
+			3: ErrDisallowedByPolicy,
+			5: ErrCatBusy,
 		}
-		return errors.New("profile not in enabled state")
-	case 3:
-		return errors.New("disallowed by policy")
-	case 4:
-		return errors.New("wrong profile re-enabling")
-	case 5:
-		return ErrCatBusy
 	}
-	return ErrUndefined
+	return newResultError(r.Operation.String(), r.Result, errs)
 }
 
 // endregion
@@ -229,15 +252,13 @@ func (r *EuiccMemoryResetResponse) UnmarshalBERTLV(tlv *bertlv.TLV) error {
 }
 
 func (r *EuiccMemoryResetResponse) Valid() error {
-	switch r.Result {
-	case 0:
+	if r.Result == 0 {
 		return nil
-	case 1:
-		return ErrNothingToDelete
-	case 5:
-		return ErrCatBusy
 	}
-	return ErrUndefined
+	return newResultError("ES10c.eUICCMemoryReset", r.Result, map[int8]error{
+		1: ErrNothingToDelete,
+		5: ErrCatBusy,
+	})
 }
 
 // endregion
@@ -330,13 +351,12 @@ func (r *SetNicknameResponse) UnmarshalBERTLV(tlv *bertlv.TLV) error {
 }
 
 func (r *SetNicknameResponse) Valid() error {
-	switch r.Result {
-	case 0:
+	if r.Result == 0 {
 		return nil
-	case 1:
-		return ErrICCIDNotFound
 	}
-	return ErrUndefined
+	return newResultError("ES10c.SetNickname", r.Result, map[int8]error{
+		1: ErrICCIDOrAIDNotFound,
+	})
 }
 
 // endregion
@@ -12,6 +12,14 @@ func (r *ES11AuthenticateClientRequest) RemoteResponse() *ES11AuthenticateClient
 	return new(ES11AuthenticateClientResponse)
 }
 
+func (r *ES11AuthenticateClientRequest) FunctionName() string {
+	return "ES11.AuthenticateClient"
+}
+
+func (r *ES11AuthenticateClientRequest) SessionTransactionID(*ES11AuthenticateClientResponse) HexString {
+	return r.TransactionID
+}
+
 type ES11AuthenticateClientResponse struct {
 	Header        *Header       `json:"header"`
 	TransactionID HexString     `json:"transactionId"`
@@ -19,7 +27,7 @@ type ES11AuthenticateClientResponse struct {
 }
 
 func (r *ES11AuthenticateClientResponse) FunctionExecutionStatus() *ExecutionStatus {
-	return r.Header.ExecutionStatus
+	return r.Header.Status()
 }
 
 type EventEntry struct {
@@ -22,6 +22,14 @@ func (r *ES9InitiateAuthenticationRequest) RemoteResponse() *ES9InitiateAuthenti
 	return new(ES9InitiateAuthenticationResponse)
 }
 
+func (r *ES9InitiateAuthenticationRequest) FunctionName() string {
+	return "ES9+.InitiateAuthentication"
+}
+
+func (r *ES9InitiateAuthenticationRequest) SessionTransactionID(response *ES9InitiateAuthenticationResponse) HexString {
+	return response.TransactionID
+}
+
 type ES9InitiateAuthenticationResponse struct {
 	Header        *Header     `json:"header"`
 	TransactionID HexString   `json:"transactionId"`
@@ -32,7 +40,7 @@ type ES9InitiateAuthenticationResponse struct {
 }
 
 func (r *ES9InitiateAuthenticationResponse) FunctionExecutionStatus() *ExecutionStatus {
-	return r.Header.ExecutionStatus
+	return r.Header.Status()
 }
 
 func (r *ES9InitiateAuthenticationResponse) CardRequest() *AuthenticateServerRequest {
@@ -62,6 +70,14 @@ func (r *ES9BoundProfilePackageRequest) RemoteResponse() *ES9BoundProfilePackage
 	return new(ES9BoundProfilePackageResponse)
 }
 
+func (r *ES9BoundProfilePackageRequest) FunctionName() string {
+	return "ES9+.GetBoundProfilePackage"
+}
+
+func (r *ES9BoundProfilePackageRequest) SessionTransactionID(response *ES9BoundProfilePackageResponse) HexString {
+	return r.TransactionID
+}
+
 func (r *ES9BoundProfilePackageRequest) UnmarshalBERTLV(tlv *bertlv.TLV) error {
 	if !tlv.Tag.If(bertlv.ContextSpecific, bertlv.Constructed, 33) {
 		return ErrUnexpectedTag
@@ -81,7 +97,7 @@ type ES9BoundProfilePackageResponse struct {
 }
 
 func (r *ES9BoundProfilePackageResponse) FunctionExecutionStatus() *ExecutionStatus {
-	return r.Header.ExecutionStatus
+	return r.Header.Status()
 }
 
 func (r *ES9BoundProfilePackageResponse) CardRequest() *LoadBoundProfilePackageRequest {
@@ -105,6 +121,14 @@ func (r *ES9AuthenticateClientRequest) RemoteResponse() *ES9AuthenticateClientRe
 	return new(ES9AuthenticateClientResponse)
 }
 
+func (r *ES9AuthenticateClientRequest) FunctionName() string {
+	return "ES9+.AuthenticateClient"
+}
+
+func (r *ES9AuthenticateClientRequest) SessionTransactionID(response *ES9AuthenticateClientResponse) HexString {
+	return r.TransactionID
+}
+
 func (r *ES9AuthenticateClientRequest) UnmarshalBERTLV(tlv *bertlv.TLV) error {
 	if !tlv.Tag.If(bertlv.ContextSpecific, bertlv.Constructed, 56) {
 		return ErrUnexpectedTag
@@ -127,7 +151,7 @@ type ES9AuthenticateClientResponse struct {
 }
 
 func (r *ES9AuthenticateClientResponse) FunctionExecutionStatus() *ExecutionStatus {
-	return r.Header.ExecutionStatus
+	return r.Header.Status()
 }
 
 func (r *ES9AuthenticateClientResponse) CardRequest() *PrepareDownloadRequest {
@@ -159,6 +183,14 @@ func (r *ES9HandleNotificationRequest) RemoteResponse() *ES9HandleNotificationRe
 	return new(ES9HandleNotificationResponse)
 }
 
+func (r *ES9HandleNotificationRequest) FunctionName() string {
+	return "ES9+.HandleNotification"
+}
+
+func (r *ES9HandleNotificationRequest) SessionTransactionID(response *ES9HandleNotificationResponse) HexString {
+	return nil
+}
+
 type ES9HandleNotificationResponse struct {
 	Header *Header `json:"header"`
 }
@@ -190,6 +222,14 @@ func (r *ES9CancelSessionRequest) RemoteResponse() *ES9CancelSessionResponse {
 	return new(ES9CancelSessionResponse)
 }
 
+func (r *ES9CancelSessionRequest) FunctionName() string {
+	return "ES9+.CancelSession"
+}
+
+func (r *ES9CancelSessionRequest) SessionTransactionID(response *ES9CancelSessionResponse) HexString {
+	return r.TransactionID
+}
+
 func (r *ES9CancelSessionRequest) UnmarshalBERTLV(tlv *bertlv.TLV) error {
 	if !tlv.Tag.If(bertlv.ContextSpecific, bertlv.Constructed, 65) {
 		return ErrUnexpectedTag
@@ -207,7 +247,7 @@ type ES9CancelSessionResponse struct {
 }
 
 func (r *ES9CancelSessionResponse) FunctionExecutionStatus() *ExecutionStatus {
-	return r.Header.ExecutionStatus
+	return r.Header.Status()
 }
 
 // endregion
@@ -46,6 +46,20 @@ const (
 	NotificationEventDelete  NotificationEvent = 3
 )
 
+func (n NotificationEvent) String() string {
+	switch n {
+	case NotificationEventInstall:
+		return "install"
+	case NotificationEventEnable:
+		return "enable"
+	case NotificationEventDisable:
+		return "disable"
+	case NotificationEventDelete:
+		return "delete"
+	}
+	return "unknown"
+}
+
 // endregion
 
 // endregion
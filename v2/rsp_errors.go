@@ -1,7 +1,21 @@
 package sgp22
 
+// The status codes reported by the Is methods of RSPError.
+var (
+	statusInsufficientMemory      = StatusCodeData{"8.1", "4.8", "eUICC does not have sufficient space for this Profile"}
+	statusProfileNotReleased      = StatusCodeData{"8.2", "1.2", "Profile has not yet been released"}
+	statusMatchingIDRefused       = StatusCodeData{"8.2.6", "3.8", "MatchingID (AC_Token or EventID) is refused"}
+	statusConfirmationCodeMissing = StatusCodeData{"8.2.7", "2.2", "Confirmation Code is missing"}
+	statusConfirmationCodeRefused = StatusCodeData{"8.2.7", "3.8", "Confirmation Code is refused"}
+	statusConfirmationRetries     = StatusCodeData{"8.2.7", "6.4", "The maximum number of retries for the Confirmation Code has been exceeded"}
+	statusOrderExpired            = StatusCodeData{"8.8.5", "4.10", "The Download order has expired"}
+	statusOrderRetriesExceeded    = StatusCodeData{"8.8.5", "6.4", "The maximum number of retries for the Profile download order has been exceeded"}
+	statusTransactionIDUnknown    = StatusCodeData{"8.10.1", "3.9", "The RSP session identified by the TransactionID is unknown"}
+)
+
+// rspErrors are the status codes defined by SGP.22 with their message.
 var rspErrors = []StatusCodeData{
-	{"8.1", "4.8", "eUICC does not have sufficient space for this Profile"},
+	statusInsufficientMemory,
 	{"8.1", "6.1", "eUICC signature is invalid or serverChallenge is invalid"},
 	{"8.1.1", "2.2", "Indicates that the EID is missing in the context of this order (SM-DS address provided or MatchingID value is empty)"},
 	{"8.1.1", "3.1", "Indicates that a different EID is already associated with this ICCID"},
@@ -11,7 +25,7 @@ var rspErrors = []StatusCodeData{
 	{"8.1.2", "6.3", "EUM Certificate has expired"},
 	{"8.1.3", "6.1", "eUICC Certificate is invalid"},
 	{"8.1.3", "6.3", "eUICC Certificate has expired"},
-	{"8.2", "1.2", "Profile has not yet been released"},
+	statusProfileNotReleased,
 	{"8.2", "3.7", "BPP is not available for a new binding"},
 	{"8.2.1", "1.2", "Indicates that the function caller is not allowed to perform this function on the target Profile"},
 	{"8.2.1", "3.3", "Indicates that the Profile identified by the provided ICCID is not available"},
@@ -24,18 +38,18 @@ var rspErrors = []StatusCodeData{
 	{"8.2.5", "3.9", "Indicates that the Profile Type identified by this Profile Type is unknown to the SM-DP+"},
 	{"8.2.5", "4.3", "No eligible Profile for this eUICC/Device"},
 	{"8.2.6", "3.3", "Conflicting MatchingID value"},
-	{"8.2.6", "3.8", "MatchingID (AC_Token or EventID) is refused"},
+	statusMatchingIDRefused,
 	{"8.2.6", "3.10", "Indicates that a different MatchingID is associated with this ICCID"},
-	{"8.2.7", "2.2", "Confirmation Code is missing"},
-	{"8.2.7", "3.8", "Confirmation Code is refused"},
-	{"8.2.7", "6.4", "The maximum number of retries for the Confirmation Code has been exceeded"},
+	statusConfirmationCodeMissing,
+	statusConfirmationCodeRefused,
+	statusConfirmationRetries,
 	{"8.8", "3.10", "The provided SM-DP+ OID is invalid"},
 	{"8.8.1", "3.8", "Invalid SM-DP+ Address"},
 	{"8.8.2", "3.1", "None of the proposed Public Key Identifiers is supported by the SM-DP+"},
 	{"8.8.3", "3.1", "The Specification Version Number indicated by the eUICC is not supported by the SM-DP+"},
 	{"8.8.4", "3.7", "The SM-DP+ has no CERT.DPauth.ECDSA signed by one of the CI Public Key supported by the eUICC"},
-	{"8.8.5", "4.10", "The Download order has expired"},
-	{"8.8.5", "6.4", "The maximum number of retries for the Profile download order has been exceeded"},
+	statusOrderExpired,
+	statusOrderRetriesExceeded,
 	{"8.9", "4.2", "The cascade SM-DS registration has failed. SMDS has raised an error"},
 	{"8.9", "5.1", "Indicates that the smdsAddress is invalid or not reachable."},
 	{"8.9.1", "3.8", "Invalid SM-DS Address"},
@@ -44,6 +58,6 @@ var rspErrors = []StatusCodeData{
 	{"8.9.4", "3.7", "The SM-DS has no CERT.DS.ECDSA signed by one of the GSMA CI Public Key supported by the eUICC"},
 	{"8.9.5", "3.3", "The Event Record already exist in the SM-DS (EventID duplicated)"},
 	{"8.9.5", "3.9", "No Event identified by the Event ID for the EID exists"},
-	{"8.10.1", "3.9", "The RSP session identified by the TransactionID is unknown"},
+	statusTransactionIDUnknown,
 	{"8.11.1", "3.9", "Unknown CI Public Key. The CI used by the EUM Certificate is not a trusted root."},
 }
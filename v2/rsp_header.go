@@ -8,6 +8,14 @@ type Header struct {
 	CallID          string           `json:"functionCallIdentifier,omitempty"`
 }
 
+// Status returns the function execution status, nil when the response has no header.
+func (h *Header) Status() *ExecutionStatus {
+	if h == nil {
+		return nil
+	}
+	return h.ExecutionStatus
+}
+
 func (h Header) Error() error {
 	if h.ExecutionStatus.ExecutedSuccess() {
 		return nil
@@ -1,15 +1,15 @@
 package sgp22
 
 import (
-	"errors"
+	"context"
 	"net/url"
 
 	"github.com/KilimcininKorOglu/euicc-go/bertlv"
 )
 
 type Transmitter interface {
-	Transmit(bertlv.Marshaler, bertlv.Unmarshaler) error
-	TransmitRaw([]byte) ([]byte, error)
+	Transmit(context.Context, bertlv.Marshaler, bertlv.Unmarshaler) error
+	TransmitRaw(context.Context, []byte) ([]byte, error)
 }
 
 type CardRequest[R CardResponse] interface {
@@ -22,39 +22,49 @@ type CardResponse interface {
 	Valid() error
 }
 
-func InvokeAPDU[I CardRequest[O], O CardResponse](transmitter Transmitter, request I) (O, error) {
+func InvokeAPDU[I CardRequest[O], O CardResponse](ctx context.Context, transmitter Transmitter, request I) (O, error) {
 	response := request.CardResponse()
-	err := transmitter.Transmit(request, response)
+	err := transmitter.Transmit(ctx, request, response)
 	if err == nil {
 		err = response.Valid()
 	}
 	return response, err
 }
 
-func InvokeRawAPDU(transmitter Transmitter, command []byte) ([]byte, error) {
-	return transmitter.TransmitRaw(command)
+func InvokeRawAPDU(ctx context.Context, transmitter Transmitter, command []byte) ([]byte, error) {
+	return transmitter.TransmitRaw(ctx, command)
 }
 
 type HTTPClient interface {
-	SendRequest(url *url.URL, request, response any) error
+	SendRequest(ctx context.Context, url *url.URL, request, response any) error
 }
 
 type HTTPRequest[R HTTPResponse] interface {
 	URL(*url.URL) *url.URL
 	RemoteResponse() R
+	// FunctionName returns the name of the ES9+ or ES11 function, such as "ES9+.AuthenticateClient".
+	FunctionName() string
+	// SessionTransactionID returns the transaction ID of the RSP session of the request,
+	// or of the response for the function starting the session.
+	SessionTransactionID(response R) HexString
 }
 
 type HTTPResponse interface {
 	FunctionExecutionStatus() *ExecutionStatus
 }
 
-func InvokeHTTP[I HTTPRequest[O], O HTTPResponse](client HTTPClient, address *url.URL, request I) (O, error) {
+// InvokeHTTP sends the request to the server and returns its response.
+// A function the server did not execute successfully returns an [RSPError],
+// even when the server answered with an HTTP error status.
+func InvokeHTTP[I HTTPRequest[O], O HTTPResponse](ctx context.Context, client HTTPClient, address *url.URL, request I) (O, error) {
 	response := request.RemoteResponse()
-	if err := client.SendRequest(request.URL(address), request, response); err != nil {
+	err := client.SendRequest(ctx, request.URL(address), request, response)
+	status := response.FunctionExecutionStatus()
+	if err != nil && (status == nil || status.Status == "" || status.ExecutedSuccess()) {
 		return response, err
 	}
-	if !response.FunctionExecutionStatus().ExecutedSuccess() {
-		return response, errors.New(response.FunctionExecutionStatus().StatusCodeData.Error())
+	if !status.ExecutedSuccess() {
+		return response, newRSPError(request.FunctionName(), request.SessionTransactionID(response), status)
 	}
 	return response, nil
 }
@@ -8,3 +8,11 @@ func mustMarshalValue(tlv *bertlv.TLV, err error) *bertlv.TLV {
 	}
 	return tlv
 }
+
+// valueOf returns the value of the TLV, nil for a missing TLV.
+func valueOf(tlv *bertlv.TLV) []byte {
+	if tlv == nil {
+		return nil
+	}
+	return tlv.Value
+}